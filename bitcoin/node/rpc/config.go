@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Config struct {
+	// URL of the node JSON-RPC endpoint.
+	URL string `yaml:"url"`
+	// Credentials used to authenticate the requests, if empty the
+	// requests are not authenticated.
+	Username       string        `yaml:"username"`
+	Password       string        `yaml:"password"`
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// MaxRequestsPerSecond represents the maximum number of requests sent each
+	// second to the node, if zero the rate is not limited.
+	MaxRequestsPerSecond float64 `yaml:"max_requests_per_second"`
+	// MaxConcurrentRequests represents the maximum number of requests sent
+	// concurrently to the node, if zero the concurrency is not limited.
+	MaxConcurrentRequests uint32 `yaml:"max_concurrent_requests"`
+}
+
+func NewConfig(url string, username string, password string, timeout time.Duration) Config {
+	return Config{
+		URL:            url,
+		Username:       username,
+		Password:       password,
+		RequestTimeout: timeout,
+	}
+}
+
+func DefaultConfig(url string) Config {
+	return NewConfig(url, "", "", time.Second*10)
+}
+
+func (c *Config) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url can't be empty")
+	}
+
+	_, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if c.Password != "" && c.Username == "" {
+		return fmt.Errorf("username can't be empty if password is defined")
+	}
+
+	if c.MaxRequestsPerSecond < 0 {
+		return fmt.Errorf("max_requests_per_second must be >= 0")
+	}
+
+	return nil
+}
+
+// Implements the Unmarshaler interface of the yaml pkg.
+func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
+	// Local type to avoid recursion during the unmarshal
+	type privateCfg Config
+	config := privateCfg(DefaultConfig(""))
+	err := unmarshal(&config)
+	if err != nil {
+		return err
+	}
+
+	*c = Config(config)
+	return nil
+}
@@ -0,0 +1,125 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	btctypes "github.com/milkyway-labs/flux/bitcoin/types"
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// blockVerbosity is the verbosity used with getblock to get
+// the block along with the decoded txs.
+const blockVerbosity = 2
+
+var _ node.Node = &Node{}
+
+type Node struct {
+	cfg     Config
+	logger  zerolog.Logger
+	client  *jsonrpc2.Client
+	chainID string
+}
+
+func NewNode(ctx context.Context, logger zerolog.Logger, cfg Config) (*Node, error) {
+	logger = logger.With().Str("bitcoin-node", cfg.URL).Logger()
+
+	client, err := jsonrpc2.NewClient(cfg.URL, &http.Client{
+		Timeout: cfg.RequestTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create rpc client: %w", err)
+	}
+	client.WithMaxRequestsPerSecond(cfg.MaxRequestsPerSecond).
+		WithMaxConcurrentRequests(cfg.MaxConcurrentRequests)
+	if cfg.Username != "" {
+		client.WithBasicAuth(cfg.Username, cfg.Password)
+	}
+
+	var res BlockchainInfoResponse
+	if err := client.Call(ctx, "getblockchaininfo", []any{}, &res); err != nil {
+		return nil, fmt.Errorf("get chain id: %w", err)
+	}
+
+	return &Node{
+		cfg:     cfg,
+		logger:  logger,
+		client:  client,
+		chainID: res.Chain,
+	}, nil
+}
+
+// GetChainID implements node.Node.
+// The chain ID is the name of the network (e.g. main, test or signet).
+func (n *Node) GetChainID() string {
+	return n.chainID
+}
+
+// GetCurrentHeight implements node.Node.
+func (n *Node) GetCurrentHeight(ctx context.Context) (types.Height, error) {
+	var height types.Height
+	if err := n.client.Call(ctx, "getblockcount", []any{}, &height); err != nil {
+		return 0, fmt.Errorf("call getblockcount: %w", err)
+	}
+
+	return height, nil
+}
+
+// GetLowestHeight implements node.Node.
+func (n *Node) GetLowestHeight(ctx context.Context) (types.Height, error) {
+	var res BlockchainInfoResponse
+	if err := n.client.Call(ctx, "getblockchaininfo", []any{}, &res); err != nil {
+		return 0, fmt.Errorf("call getblockchaininfo: %w", err)
+	}
+
+	if !res.Pruned {
+		return 0, nil
+	}
+	return res.PruneHeight, nil
+}
+
+// GetBlock implements node.Node.
+func (n *Node) GetBlock(ctx context.Context, height types.Height) (types.Block, error) {
+	var hash string
+	if err := n.client.Call(ctx, "getblockhash", []any{height}, &hash); err != nil {
+		return nil, fmt.Errorf("call getblockhash: %w", err)
+	}
+
+	var block Block
+	if err := n.client.Call(ctx, "getblock", []any{hash, blockVerbosity}, &block); err != nil {
+		return nil, fmt.Errorf("call getblock: %w", err)
+	}
+
+	txs := make([]btctypes.Tx, len(block.Tx))
+	for i, tx := range block.Tx {
+		txs[i] = tx.ToTx(uint32(i))
+	}
+
+	header := btctypes.NewBlockHeader(
+		n.chainID,
+		block.Height,
+		time.Unix(block.Time, 0).UTC(),
+		block.Hash,
+		block.PreviousBlockHash,
+	)
+	header.Version = block.Version
+	header.MerkleRoot = block.MerkleRoot
+	header.Bits = block.Bits
+	header.Nonce = block.Nonce
+	header.Difficulty = block.Difficulty
+	header.Size = block.Size
+	header.Weight = block.Weight
+
+	return btctypes.NewBlock(header, txs), nil
+}
+
+// Config gets the Node configuration.
+func (n *Node) Config() Config {
+	return n.cfg
+}
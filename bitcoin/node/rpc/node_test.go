@@ -0,0 +1,164 @@
+package rpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/bitcoin/node/rpc"
+	btctypes "github.com/milkyway-labs/flux/bitcoin/types"
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+const testBlockHash = "00000000000000000001b3a3e1a6e1e2b0d5d1d0a8a2a8a2a8a2a8a2a8a2a8a2"
+
+var testResults = map[string]string{
+	"getblockchaininfo": `{"chain": "main", "pruned": true, "pruneheight": 800000}`,
+	"getblockcount":     `820000`,
+	"getblockhash":      `"` + testBlockHash + `"`,
+	"getblock": `{
+		"hash": "` + testBlockHash + `",
+		"height": 820000,
+		"version": 536870912,
+		"merkleroot": "aa",
+		"time": 1704067200,
+		"nonce": 42,
+		"bits": "17034219",
+		"difficulty": 72006146478567.1,
+		"previousblockhash": "bb",
+		"size": 1500,
+		"weight": 4000,
+		"tx": [
+			{
+				"txid": "cc",
+				"hash": "cd",
+				"version": 2,
+				"size": 200,
+				"vsize": 150,
+				"weight": 600,
+				"locktime": 0,
+				"vin": [{"coinbase": "03206b0c", "sequence": 4294967295}],
+				"vout": [
+					{"value": 6.25, "n": 0, "scriptPubKey": {"hex": "0014aa", "type": "witness_v0_keyhash", "address": "bc1qminer"}},
+					{"value": 0.00000000, "n": 1, "scriptPubKey": {"hex": "6a24aa", "type": "nulldata"}}
+				]
+			},
+			{
+				"txid": "dd",
+				"hash": "de",
+				"version": 2,
+				"size": 250,
+				"vsize": 160,
+				"weight": 640,
+				"locktime": 819999,
+				"vin": [{"txid": "ee", "vout": 1, "scriptSig": {"hex": ""}, "txinwitness": ["3044", "02aa"], "sequence": 4294967293}],
+				"vout": [
+					{"value": 0.12345678, "n": 0, "scriptPubKey": {"hex": "76a914aa88ac", "type": "pubkeyhash", "addresses": ["1legacy"]}}
+				],
+				"fee": 0.00001234
+			}
+		]
+	}`,
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req jsonrpc2.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(jsonrpc2.Response{
+			ID:     req.ID,
+			Result: json.RawMessage(testResults[req.Method]),
+		}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNode_Unauthorized(t *testing.T) {
+	cfg := rpc.DefaultConfig(newTestServer(t).URL)
+	_, err := rpc.NewNode(context.Background(), zerolog.Nop(), cfg)
+	require.Error(t, err)
+}
+
+func TestNode_GetBlock(t *testing.T) {
+	cfg := rpc.NewConfig(newTestServer(t).URL, "user", "pass", time.Second)
+	node, err := rpc.NewNode(context.Background(), zerolog.Nop(), cfg)
+	require.NoError(t, err)
+	require.Equal(t, "main", node.GetChainID())
+
+	height, err := node.GetCurrentHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(820000), height)
+
+	height, err = node.GetLowestHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(800000), height)
+
+	block, err := node.GetBlock(context.Background(), 820000)
+	require.NoError(t, err)
+
+	btcBlock, ok := block.(*btctypes.Block)
+	require.True(t, ok)
+	require.Equal(t, testBlockHash, btcBlock.GetHash())
+	require.Equal(t, "bb", btcBlock.GetParentHash())
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), btcBlock.GetTimeStamp())
+	require.Len(t, btcBlock.GetTxs(), 2)
+
+	coinbase := btcBlock.Txs[0]
+	require.True(t, coinbase.IsCoinbase())
+	require.Nil(t, coinbase.Fee)
+	require.Equal(t, int64(625_000_000), coinbase.GetOutputsValue())
+	require.Equal(t, "bc1qminer", coinbase.Outputs[0].Address)
+	require.Empty(t, coinbase.Outputs[1].Address)
+
+	tx := btcBlock.Txs[1]
+	require.False(t, tx.IsCoinbase())
+	require.Equal(t, uint32(1), tx.Index)
+	require.Equal(t, "ee", tx.Inputs[0].TxID)
+	require.Equal(t, uint32(1), tx.Inputs[0].Vout)
+	require.Equal(t, []string{"3044", "02aa"}, tx.Inputs[0].Witness)
+	require.Equal(t, int64(12_345_678), tx.Outputs[0].Value)
+	require.Equal(t, "1legacy", tx.Outputs[0].Address)
+	require.NotNil(t, tx.Fee)
+	require.Equal(t, int64(1234), *tx.Fee)
+}
+
+func TestAmount_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		value     string
+		expected  rpc.Amount
+		shouldErr bool
+	}{
+		{value: "0", expected: 0},
+		{value: "21000000", expected: 2_100_000_000_000_000},
+		{value: "0.1", expected: 10_000_000},
+		{value: "-0.00000001", expected: -1},
+		{value: "0.000000001", shouldErr: true},
+		{value: `"1"`, shouldErr: true},
+	}
+
+	for _, tc := range testCases {
+		var amount rpc.Amount
+		err := json.Unmarshal([]byte(tc.value), &amount)
+		if tc.shouldErr {
+			require.Error(t, err, tc.value)
+		} else {
+			require.NoError(t, err, tc.value)
+			require.Equal(t, tc.expected, amount, tc.value)
+		}
+	}
+}
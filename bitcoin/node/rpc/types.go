@@ -0,0 +1,153 @@
+package rpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	btctypes "github.com/milkyway-labs/flux/bitcoin/types"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// bitcoinDecimals represents the number of decimals of a bitcoin amount.
+const bitcoinDecimals = 8
+
+// Amount represents an amount of bitcoins, encoded as a JSON number with up to 8 decimals,
+// converted to satoshis without losing precision.
+type Amount int64
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	integer, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > bitcoinDecimals {
+		return fmt.Errorf("invalid amount %s: too many decimals", data)
+	}
+	fraction += strings.Repeat("0", bitcoinDecimals-len(fraction))
+
+	satoshis, err := strconv.ParseInt(integer+fraction, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", data, err)
+	}
+	if negative {
+		satoshis = -satoshis
+	}
+
+	*a = Amount(satoshis)
+	return nil
+}
+
+type BlockchainInfoResponse struct {
+	Chain string `json:"chain"`
+	// PruneHeight is the height of the lowest block stored by the node,
+	// defined only if the node is pruned.
+	PruneHeight types.Height `json:"pruneheight"`
+	Pruned      bool         `json:"pruned"`
+}
+
+type Block struct {
+	Hash              string        `json:"hash"`
+	Height            types.Height  `json:"height"`
+	Version           int32         `json:"version"`
+	MerkleRoot        string        `json:"merkleroot"`
+	Time              int64         `json:"time"`
+	Nonce             uint32        `json:"nonce"`
+	Bits              string        `json:"bits"`
+	Difficulty        float64       `json:"difficulty"`
+	PreviousBlockHash string        `json:"previousblockhash"`
+	Size              uint64        `json:"size"`
+	Weight            uint64        `json:"weight"`
+	Tx                []Transaction `json:"tx"`
+}
+
+type Transaction struct {
+	TxID     string   `json:"txid"`
+	Hash     string   `json:"hash"`
+	Version  uint32   `json:"version"`
+	Size     uint64   `json:"size"`
+	VSize    uint64   `json:"vsize"`
+	Weight   uint64   `json:"weight"`
+	LockTime uint32   `json:"locktime"`
+	Vin      []Input  `json:"vin"`
+	Vout     []Output `json:"vout"`
+	Fee      *Amount  `json:"fee"`
+}
+
+type ScriptSig struct {
+	Hex string `json:"hex"`
+}
+
+type Input struct {
+	TxID        string    `json:"txid"`
+	Vout        uint32    `json:"vout"`
+	Coinbase    string    `json:"coinbase"`
+	ScriptSig   ScriptSig `json:"scriptSig"`
+	TxInWitness []string  `json:"txinwitness"`
+	Sequence    uint32    `json:"sequence"`
+}
+
+type ScriptPubKey struct {
+	Hex     string `json:"hex"`
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	// Addresses is returned instead of Address by the nodes older than v22.
+	Addresses []string `json:"addresses"`
+}
+
+type Output struct {
+	Value        Amount       `json:"value"`
+	N            uint32       `json:"n"`
+	ScriptPubKey ScriptPubKey `json:"scriptPubKey"`
+}
+
+// ToTx converts the transaction to a btctypes.Tx.
+func (t Transaction) ToTx(index uint32) btctypes.Tx {
+	inputs := make([]btctypes.TxInput, len(t.Vin))
+	for i, input := range t.Vin {
+		inputs[i] = btctypes.TxInput{
+			TxID:      input.TxID,
+			Vout:      input.Vout,
+			Coinbase:  input.Coinbase,
+			ScriptSig: input.ScriptSig.Hex,
+			Witness:   input.TxInWitness,
+			Sequence:  input.Sequence,
+		}
+	}
+
+	outputs := make([]btctypes.TxOutput, len(t.Vout))
+	for i, output := range t.Vout {
+		address := output.ScriptPubKey.Address
+		if address == "" && len(output.ScriptPubKey.Addresses) == 1 {
+			address = output.ScriptPubKey.Addresses[0]
+		}
+		outputs[i] = btctypes.TxOutput{
+			Index:        output.N,
+			Value:        int64(output.Value),
+			Address:      address,
+			ScriptPubKey: output.ScriptPubKey.Hex,
+			Type:         output.ScriptPubKey.Type,
+		}
+	}
+
+	var fee *int64
+	if t.Fee != nil {
+		value := int64(*t.Fee)
+		fee = &value
+	}
+
+	return btctypes.Tx{
+		TxID:        t.TxID,
+		WitnessHash: t.Hash,
+		Index:       index,
+		Version:     t.Version,
+		LockTime:    t.LockTime,
+		Size:        t.Size,
+		VSize:       t.VSize,
+		Weight:      t.Weight,
+		Fee:         fee,
+		Inputs:      inputs,
+		Outputs:     outputs,
+	}
+}
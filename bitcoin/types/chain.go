@@ -0,0 +1,172 @@
+package types
+
+import (
+	"time"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+// ----------------------------------------------------------------------------
+// -- Block related data structures
+// ----------------------------------------------------------------------------
+
+type BlockHeader struct {
+	ChainID    string
+	Height     types.Height
+	Time       time.Time
+	Hash       string
+	ParentHash string
+	Version    int32
+	MerkleRoot string
+	// Bits represents the compact encoding of the block target.
+	Bits       string
+	Nonce      uint32
+	Difficulty float64
+	Size       uint64
+	Weight     uint64
+}
+
+func NewBlockHeader(
+	chainID string,
+	height types.Height,
+	time time.Time,
+	hash string,
+	parentHash string,
+) BlockHeader {
+	return BlockHeader{
+		ChainID:    chainID,
+		Height:     height,
+		Time:       time,
+		Hash:       hash,
+		ParentHash: parentHash,
+	}
+}
+
+type Block struct {
+	Header BlockHeader
+	Txs    []Tx
+}
+
+var _ types.Block = &Block{}
+
+func NewBlock(header BlockHeader, txs []Tx) *Block {
+	return &Block{
+		Header: header,
+		Txs:    txs,
+	}
+}
+
+// GetChainID implements types.Block.
+func (b *Block) GetChainID() string {
+	return b.Header.ChainID
+}
+
+// GetHeight implements types.Block.
+func (b *Block) GetHeight() types.Height {
+	return b.Header.Height
+}
+
+// GetHash implements types.Block.
+func (b *Block) GetHash() string {
+	return b.Header.Hash
+}
+
+// GetParentHash implements types.Block.
+func (b *Block) GetParentHash() string {
+	return b.Header.ParentHash
+}
+
+// GetTimeStamp implements types.Block.
+func (b *Block) GetTimeStamp() time.Time {
+	return b.Header.Time
+}
+
+// GetTxs implements types.Block.
+func (b *Block) GetTxs() []types.Tx {
+	result := make([]types.Tx, len(b.Txs))
+	for i := range b.Txs {
+		result[i] = &b.Txs[i]
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// -- Tx related data structures
+// ----------------------------------------------------------------------------
+
+var _ types.Tx = &Tx{}
+
+type Tx struct {
+	TxID string
+	// Hash of the tx including the witness data.
+	WitnessHash string
+	// Index of the tx inside the block.
+	Index    uint32
+	Version  uint32
+	LockTime uint32
+	Size     uint64
+	VSize    uint64
+	Weight   uint64
+	// Fee paid by the tx in satoshis, nil if the node didn't provide it
+	// (e.g. for the coinbase tx).
+	Fee     *int64
+	Inputs  []TxInput
+	Outputs []TxOutput
+}
+
+// GetHash implements types.Tx.
+func (t *Tx) GetHash() string {
+	return t.TxID
+}
+
+// IsSuccessful implements types.Tx.
+// The txs included in a block are always valid.
+func (t *Tx) IsSuccessful() bool {
+	return true
+}
+
+// IsCoinbase tells if the tx is the one that creates the block reward.
+func (t *Tx) IsCoinbase() bool {
+	return len(t.Inputs) == 1 && t.Inputs[0].IsCoinbase()
+}
+
+// GetOutputsValue gets the sum of the values of the tx outputs, in satoshis.
+func (t *Tx) GetOutputsValue() int64 {
+	var total int64
+	for _, output := range t.Outputs {
+		total += output.Value
+	}
+	return total
+}
+
+// TxInput represents an input of a tx, that spends the output of a previous tx.
+type TxInput struct {
+	// ID of the tx that created the spent output, empty for the coinbase input.
+	TxID string
+	// Index of the spent output inside the tx that created it.
+	Vout uint32
+	// Coinbase contains the hex encoded data of the coinbase input.
+	Coinbase  string
+	ScriptSig string
+	Witness   []string
+	Sequence  uint32
+}
+
+// IsCoinbase tells if the input is the one of the coinbase tx.
+func (i *TxInput) IsCoinbase() bool {
+	return i.Coinbase != ""
+}
+
+// TxOutput represents an output of a tx.
+type TxOutput struct {
+	// Index of the output inside the tx.
+	Index uint32
+	// Value of the output in satoshis.
+	Value int64
+	// Address that can spend the output, empty if the output script
+	// doesn't have an address (e.g. OP_RETURN outputs).
+	Address      string
+	ScriptPubKey string
+	// Type of the output script (e.g. witness_v0_keyhash).
+	Type string
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/milkyway-labs/flux/cli/migrate"
 	"github.com/milkyway-labs/flux/cli/parse"
 	"github.com/milkyway-labs/flux/cli/root"
 	"github.com/milkyway-labs/flux/cli/start"
@@ -18,6 +19,7 @@ func NewDefaultIndexerCLI(cliCtx *types.CliContext) *cobra.Command {
 	// Add the sub-commands
 	rootCmd.AddCommand(start.NewStartCmd())
 	rootCmd.AddCommand(parse.NewParseCmd())
+	rootCmd.AddCommand(migrate.NewMigrateCmd())
 
 	return rootCmd
 }
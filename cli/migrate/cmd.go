@@ -0,0 +1,60 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	clitypes "github.com/milkyway-labs/flux/cli/types"
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/indexer"
+)
+
+func NewMigrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the schema migrations of the databases used by the indexers",
+	}
+
+	migrateCmd.AddCommand(NewMigrateUpCmd())
+	migrateCmd.AddCommand(NewMigrateDownCmd())
+	migrateCmd.AddCommand(NewMigrateStatusCmd())
+
+	return migrateCmd
+}
+
+// buildIndexers builds the indexer with the provided name,
+// or all the configured indexers if the name is empty.
+func buildIndexers(ctx context.Context, cliCtx *clitypes.CliContext, indexerName string) ([]indexer.Indexer, error) {
+	cfg, err := cliCtx.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if indexerName == "" {
+		return cliCtx.IndexersBuilder.BuildAll(ctx, cfg)
+	}
+
+	requestedIndexer, err := cliCtx.IndexersBuilder.BuildByName(ctx, cfg, indexerName)
+	if err != nil {
+		return nil, err
+	}
+	return []indexer.Indexer{requestedIndexer}, nil
+}
+
+// getMigrator gets the Migrator used to manage the schema of the indexer's database.
+// Returns false if the indexer's database doesn't support migrations.
+func getMigrator(indexer *indexer.Indexer) (database.Migrator, bool) {
+	migrator, ok := indexer.GetDatabase().(database.Migrator)
+	return migrator, ok
+}
+
+func printMigration(cmd *cobra.Command, status database.MigrationStatus) {
+	appliedAt := "pending"
+	if status.IsApplied() {
+		appliedAt = fmt.Sprintf("applied at %s", status.AppliedAt.Format(time.RFC3339))
+	}
+	cmd.Printf("%s %d_%s: %s\n", status.Source, status.Version, status.Name, appliedAt)
+}
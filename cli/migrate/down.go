@@ -0,0 +1,58 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clitypes "github.com/milkyway-labs/flux/cli/types"
+)
+
+const (
+	flagSteps = "steps"
+)
+
+func NewMigrateDownCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "down [indexer-name]",
+		Short: "Revert the last applied migrations of the database used by the provided indexer",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := clitypes.GetCliContext(cmd)
+			steps, err := cmd.Flags().GetUint(flagSteps)
+			if err != nil {
+				return err
+			}
+
+			return migrateDown(cmd.Context(), cmd, cliCtx, args[0], steps)
+		},
+	}
+
+	cmd.Flags().Uint(flagSteps, 1, "Number of migrations to revert")
+
+	return cmd
+}
+
+func migrateDown(ctx context.Context, cmd *cobra.Command, cliCtx *clitypes.CliContext, indexerName string, steps uint) error {
+	indexers, err := buildIndexers(ctx, cliCtx, indexerName)
+	if err != nil {
+		return err
+	}
+
+	migrator, ok := getMigrator(&indexers[0])
+	if !ok {
+		return fmt.Errorf("the database of indexer %s doesn't support migrations", indexerName)
+	}
+
+	reverted, err := migrator.MigrateDown(ctx, steps)
+	for _, status := range reverted {
+		printMigration(cmd, status)
+	}
+	if err != nil {
+		return fmt.Errorf("revert migrations: %w", err)
+	}
+
+	cmd.Printf("indexer %s: reverted %d migrations\n", indexerName, len(reverted))
+	return nil
+}
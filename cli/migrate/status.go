@@ -0,0 +1,54 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clitypes "github.com/milkyway-labs/flux/cli/types"
+)
+
+func NewMigrateStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status (indexer-name)",
+		Short: "Print the status of the migrations of the database used by the provided indexer, or by all the indexers if not provided",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := clitypes.GetCliContext(cmd)
+			indexerName := ""
+			if len(args) == 1 {
+				indexerName = args[0]
+			}
+
+			return migrationsStatus(cmd.Context(), cmd, cliCtx, indexerName)
+		},
+	}
+}
+
+func migrationsStatus(ctx context.Context, cmd *cobra.Command, cliCtx *clitypes.CliContext, indexerName string) error {
+	indexers, err := buildIndexers(ctx, cliCtx, indexerName)
+	if err != nil {
+		return err
+	}
+
+	for i := range indexers {
+		migrator, ok := getMigrator(&indexers[i])
+		if !ok {
+			cmd.Printf("indexer %s: database doesn't support migrations\n", indexers[i].GetName())
+			continue
+		}
+
+		statuses, err := migrator.GetMigrationsStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("get migrations status of indexer %s: %w", indexers[i].GetName(), err)
+		}
+
+		cmd.Printf("indexer %s:\n", indexers[i].GetName())
+		for _, status := range statuses {
+			printMigration(cmd, status)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clitypes "github.com/milkyway-labs/flux/cli/types"
+)
+
+func NewMigrateUpCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "up (indexer-name)",
+		Short: "Apply the pending migrations to the database of the provided indexer, or of all the indexers if not provided",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := clitypes.GetCliContext(cmd)
+			indexerName := ""
+			if len(args) == 1 {
+				indexerName = args[0]
+			}
+
+			return migrateUp(cmd.Context(), cmd, cliCtx, indexerName)
+		},
+	}
+}
+
+func migrateUp(ctx context.Context, cmd *cobra.Command, cliCtx *clitypes.CliContext, indexerName string) error {
+	indexers, err := buildIndexers(ctx, cliCtx, indexerName)
+	if err != nil {
+		return err
+	}
+
+	for i := range indexers {
+		migrator, ok := getMigrator(&indexers[i])
+		if !ok {
+			cmd.Printf("indexer %s: database doesn't support migrations\n", indexers[i].GetName())
+			continue
+		}
+
+		applied, err := migrator.MigrateUp(ctx)
+		for _, status := range applied {
+			printMigration(cmd, status)
+		}
+		if err != nil {
+			return fmt.Errorf("migrate database of indexer %s: %w", indexers[i].GetName(), err)
+		}
+
+		cmd.Printf("indexer %s: applied %d migrations\n", indexers[i].GetName(), len(applied))
+	}
+
+	return nil
+}
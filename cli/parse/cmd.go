@@ -11,6 +11,7 @@ func NewParseCmd() *cobra.Command {
 	}
 
 	parseCmd.AddCommand(NewParseBlocksRangeCmd())
+	parseCmd.AddCommand(NewParseFailedBlocksCmd())
 
 	return parseCmd
 }
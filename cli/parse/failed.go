@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	clitypes "github.com/milkyway-labs/flux/cli/types"
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/indexer"
+)
+
+const (
+	flagDryRun = "dry-run"
+)
+
+func NewParseFailedBlocksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "failed [indexer-name]",
+		Short: "Re-parse the blocks that the indexer failed to parse after reaching the maximum number of attempts",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := clitypes.GetCliContext(cmd)
+			dryRun, err := cmd.Flags().GetBool(flagDryRun)
+			if err != nil {
+				return err
+			}
+
+			return parseFailedBlocks(cmd.Context(), cmd, cliCtx, args[0], dryRun)
+		},
+	}
+
+	cmd.Flags().Bool(flagDryRun, false, "Only print the failed blocks without re-parsing them")
+
+	return cmd
+}
+
+func parseFailedBlocks(
+	ctx context.Context,
+	cmd *cobra.Command,
+	cliCtx *clitypes.CliContext,
+	indexerName string,
+	dryRun bool,
+) error {
+	// Load the indexer config
+	cfg, err := cliCtx.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Build the requested requestedIndexer
+	requestedIndexer, err := cliCtx.IndexersBuilder.BuildByName(ctx, cfg, indexerName)
+	if err != nil {
+		return err
+	}
+
+	// Ensure the database schema is up to date before reading the failed blocks
+	db := requestedIndexer.GetDatabase()
+	err = database.MigrateOnStart(ctx, db)
+	if err != nil {
+		return fmt.Errorf("migrate database of indexer %s: %w", indexerName, err)
+	}
+
+	failedBlocks, err := db.GetFailedBlocks(requestedIndexer.GetName(), requestedIndexer.GetChainID())
+	if err != nil {
+		return fmt.Errorf("get failed blocks: %w", err)
+	}
+
+	if len(failedBlocks) == 0 {
+		cmd.Println("no failed blocks found")
+		return nil
+	}
+
+	for _, failedBlock := range failedBlocks {
+		cmd.Printf("height: %d, module: %q, attempts: %d, failed at: %s, error: %s\n",
+			failedBlock.Height, failedBlock.Module, failedBlock.Attempts, failedBlock.Timestamp, failedBlock.Error)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	requestedIndexer.WithCustomHeightProducer(
+		indexer.NewFailedBlocksHeightProducer(db, requestedIndexer.GetName(), requestedIndexer.GetChainID()),
+	)
+
+	// Start re-parsing the failed blocks
+	wg := sync.WaitGroup{}
+	err = requestedIndexer.Start(ctx, &wg)
+	if err != nil {
+		return err
+	}
+	wg.Wait()
+
+	return nil
+}
@@ -9,10 +9,15 @@ import (
 	"github.com/spf13/cobra"
 
 	clitypes "github.com/milkyway-labs/flux/cli/types"
+	"github.com/milkyway-labs/flux/database"
 	"github.com/milkyway-labs/flux/indexer"
 	"github.com/milkyway-labs/flux/types"
 )
 
+const (
+	flagModules = "modules"
+)
+
 func NewParseBlocksRangeCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "range [indexer-name] [start-height] (end-height)",
@@ -35,10 +40,17 @@ func NewParseBlocksRangeCmd() *cobra.Command {
 				endHeight = parsed
 			}
 
-			return parseBlocksRange(cmd.Context(), cliCtx, indexerName, types.Height(startHeight), types.Height(endHeight))
+			modules, err := cmd.Flags().GetStringSlice(flagModules)
+			if err != nil {
+				return err
+			}
+
+			return parseBlocksRange(cmd.Context(), cliCtx, indexerName, types.Height(startHeight), types.Height(endHeight), modules)
 		},
 	}
 
+	rootCmd.Flags().StringSlice(flagModules, nil, "Names of the modules that will re-parse the blocks, if not provided all the indexer's modules are used")
+
 	return rootCmd
 }
 
@@ -48,6 +60,7 @@ func parseBlocksRange(
 	indexerName string,
 	startHeight types.Height,
 	endHeight types.Height,
+	modules []string,
 ) error {
 	// Load the indexer config
 	cfg, err := cliCtx.LoadConfig()
@@ -61,8 +74,21 @@ func parseBlocksRange(
 		return err
 	}
 
+	// Ensure the database schema is up to date before starting the indexer
+	err = database.MigrateOnStart(ctx, requestedIndexer.GetDatabase())
+	if err != nil {
+		return fmt.Errorf("migrate database of indexer %s: %w", indexerName, err)
+	}
+
+	// Make sure the requested modules are used by the indexer
+	for _, module := range modules {
+		if _, err := requestedIndexer.GetModule(module); err != nil {
+			return err
+		}
+	}
+
 	requestedIndexer.WithCustomHeightProducer(
-		indexer.NewRangeHeightProducer(startHeight, endHeight),
+		indexer.NewRangeHeightProducer(startHeight, endHeight).WithModules(modules),
 	)
 
 	// Start indexing the requested range
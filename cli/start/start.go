@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/milkyway-labs/flux/cli/types"
+	"github.com/milkyway-labs/flux/database"
 	"github.com/milkyway-labs/flux/prometheus"
 )
 
@@ -46,6 +47,17 @@ func startParsing(ctx context.Context, cliCtx *types.CliContext) error {
 		return err
 	}
 
+	// Ensure the databases schema is up to date before starting the indexers
+	for _, indexer := range indexers {
+		if indexer.IsDisabled() {
+			continue
+		}
+
+		if err := database.MigrateOnStart(ctx, indexer.GetDatabase()); err != nil {
+			return fmt.Errorf("migrate database of indexer %s: %w", indexer.GetName(), err)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
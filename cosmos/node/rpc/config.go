@@ -8,9 +8,106 @@ import (
 	"github.com/milkyway-labs/flux/types"
 )
 
+// Strategy represents how the node selects the endpoint used to perform a request.
+type Strategy string
+
+const (
+	// StrategyRoundRobin distributes the requests across the healthy endpoints
+	// proportionally to their weight.
+	StrategyRoundRobin Strategy = "round_robin"
+	// StrategyLatency sends the requests to the healthy endpoint with the lowest latency.
+	StrategyLatency Strategy = "latency"
+)
+
+func (s Strategy) Validate() error {
+	if s != StrategyRoundRobin && s != StrategyLatency {
+		return fmt.Errorf("invalid strategy, we only support `%s` and `%s` current: `%s`",
+			StrategyRoundRobin, StrategyLatency, s)
+	}
+
+	return nil
+}
+
+// EndpointConfig represents the configuration of one of the RPC endpoints used by the node.
+type EndpointConfig struct {
+	URL string `yaml:"url"`
+	// Weight used to distribute the requests with the round_robin strategy.
+	Weight uint32 `yaml:"weight"`
+}
+
+func NewEndpointConfig(url string, weight uint32) EndpointConfig {
+	return EndpointConfig{
+		URL:    url,
+		Weight: weight,
+	}
+}
+
+// Implements the Unmarshaler interface of the yaml pkg.
+func (c *EndpointConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	// Local type to avoid recursion during the unmarshal
+	type privateCfg EndpointConfig
+	config := privateCfg(NewEndpointConfig("", 1))
+	err := unmarshal(&config)
+	if err != nil {
+		return err
+	}
+
+	*c = EndpointConfig(config)
+	return nil
+}
+
+func (c *EndpointConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url can't be empty")
+	}
+
+	_, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if c.Weight == 0 {
+		return fmt.Errorf("weight must be > 0")
+	}
+
+	return nil
+}
+
 type Config struct {
-	URL            string        `yaml:"url"`
+	// URL of the node RPC endpoint, can be used instead of Endpoints
+	// when the node has a single endpoint.
+	URL string `yaml:"url"`
+	// Endpoints contains the RPC endpoints used by the node, the requests
+	// are distributed across the healthy ones following the Strategy.
+	Endpoints []EndpointConfig `yaml:"endpoints"`
+	// Strategy used to select the endpoint that performs a request.
+	Strategy Strategy `yaml:"strategy"`
+	// MaxFailures represents the number of consecutive failed requests after
+	// which an endpoint is ejected.
+	MaxFailures uint32 `yaml:"max_failures"`
+	// MaxHeightLag represents the number of blocks an endpoint can be behind
+	// the best endpoint before being ejected.
+	MaxHeightLag uint64 `yaml:"max_height_lag"`
+	// EjectionTime represents the amount of time after which an ejected
+	// endpoint is checked again to be re-admitted.
+	EjectionTime   time.Duration `yaml:"ejection_time"`
 	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// MaxRequestsPerSecond represents the maximum number of requests sent each
+	// second to each endpoint, if zero the rate is not limited.
+	MaxRequestsPerSecond float64 `yaml:"max_requests_per_second"`
+	// MaxConcurrentRequests represents the maximum number of requests sent
+	// concurrently to each endpoint, if zero the concurrency is not limited.
+	MaxConcurrentRequests uint32 `yaml:"max_concurrent_requests"`
+	// WebSocketURL represents the URL of the node CometBFT WebSocket endpoint (e.g. ws://localhost:26657/websocket)
+	// used to be notified of the new blocks. If empty, the node is polled to know
+	// when new blocks are produced.
+	WebSocketURL string `yaml:"websocket_url"`
+	// PrefetchBlocks represents the number of upcoming blocks that are fetched
+	// along with the requested one, using a single batch request.
+	PrefetchBlocks uint32 `yaml:"prefetch_blocks"`
+	// DecodeTxs tells if the node should decode the protobuf encoded txs to
+	// provide their messages, fee, memo and signers.
+	DecodeTxs bool `yaml:"decode_txs"`
 	// Tells until which height the indexer will parse the tx.log field to get the
 	// transaction events. After this height, the indexer will use the tx.events
 	// field directly. TxEventsFromLogUntilHeight is nil, the indexer will always use
@@ -30,6 +127,10 @@ func NewConfig(
 ) Config {
 	return Config{
 		URL:                                   url,
+		Strategy:                              StrategyRoundRobin,
+		MaxFailures:                           3,
+		MaxHeightLag:                          10,
+		EjectionTime:                          30 * time.Second,
 		RequestTimeout:                        timeout,
 		TxEventsFromLogUntilHeight:            txEventsFromLogUntilHeight,
 		DecodeBlockEventAttributesUntilHeight: decodeBlockEventAttributesUntilHeight,
@@ -37,14 +138,57 @@ func NewConfig(
 }
 
 func (c *Config) Validate() error {
-	_, err := url.Parse(c.URL)
-	if err != nil {
-		return fmt.Errorf("invalid url: %w", err)
+	if c.URL != "" && len(c.Endpoints) > 0 {
+		return fmt.Errorf("url and endpoints can't be defined together")
+	}
+
+	endpoints := c.GetEndpoints()
+	if len(endpoints) == 0 {
+		return fmt.Errorf("url or endpoints must be defined")
+	}
+	for i, endpoint := range endpoints {
+		if err := endpoint.Validate(); err != nil {
+			return fmt.Errorf("invalid endpoint %d: %w", i, err)
+		}
+	}
+
+	if err := c.Strategy.Validate(); err != nil {
+		return err
+	}
+
+	if c.MaxFailures == 0 {
+		return fmt.Errorf("max_failures must be > 0")
+	}
+
+	if c.EjectionTime <= 0 {
+		return fmt.Errorf("ejection_time must be > 0")
+	}
+
+	if c.MaxRequestsPerSecond < 0 {
+		return fmt.Errorf("max_requests_per_second must be >= 0")
+	}
+
+	if c.WebSocketURL != "" {
+		wsURL, err := url.Parse(c.WebSocketURL)
+		if err != nil {
+			return fmt.Errorf("invalid websocket_url: %w", err)
+		}
+		if wsURL.Scheme != "ws" && wsURL.Scheme != "wss" {
+			return fmt.Errorf("invalid websocket_url scheme, we only support `ws` and `wss` current: `%s`", wsURL.Scheme)
+		}
 	}
 
 	return nil
 }
 
+// GetEndpoints gets the RPC endpoints used by the node.
+func (c *Config) GetEndpoints() []EndpointConfig {
+	if c.URL != "" {
+		return []EndpointConfig{NewEndpointConfig(c.URL, 1)}
+	}
+	return c.Endpoints
+}
+
 func (c *Config) TxEventsFromLog(height types.Height) bool {
 	return c.TxEventsFromLogUntilHeight != nil && height <= *c.TxEventsFromLogUntilHeight
 }
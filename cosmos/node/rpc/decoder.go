@@ -0,0 +1,233 @@
+package rpc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	cosmostypes "github.com/milkyway-labs/flux/cosmos/types"
+)
+
+// TxDecoder represents a function that given the bytes of a transaction
+// returns the decoded transaction.
+type TxDecoder func(txData []byte) (*cosmostypes.DecodedTx, error)
+
+// DefaultTxDecoder is the default TxDecoder used by the cosmos rpc node,
+// it decodes the protobuf encoded cosmos.tx.v1beta1.TxRaw transactions.
+func DefaultTxDecoder(txData []byte) (*cosmostypes.DecodedTx, error) {
+	var tx cosmostypes.DecodedTx
+	err := parseProtoFields(txData, func(f protoField) error {
+		switch f.num {
+		case 1:
+			return f.decodeMessage(func(data []byte) error { return decodeTxBody(data, &tx.Body) })
+		case 2:
+			return f.decodeMessage(func(data []byte) error { return decodeAuthInfo(data, &tx.AuthInfo) })
+		case 3:
+			signature, err := f.getBytes()
+			tx.Signatures = append(tx.Signatures, signature)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode tx raw: %w", err)
+	}
+
+	return &tx, nil
+}
+
+// decodeTxBody decodes a cosmos.tx.v1beta1.TxBody message.
+func decodeTxBody(data []byte, body *cosmostypes.TxBody) error {
+	return parseProtoFields(data, func(f protoField) error {
+		var err error
+		switch f.num {
+		case 1:
+			body.Messages, err = appendAny(body.Messages, f)
+		case 2:
+			body.Memo, err = f.getString()
+		case 3:
+			body.TimeoutHeight, err = f.getUint64()
+		case 1023:
+			body.ExtensionOptions, err = appendAny(body.ExtensionOptions, f)
+		case 2047:
+			body.NonCriticalExtensionOptions, err = appendAny(body.NonCriticalExtensionOptions, f)
+		}
+		if err != nil {
+			return fmt.Errorf("tx body: %w", err)
+		}
+		return nil
+	})
+}
+
+// decodeAuthInfo decodes a cosmos.tx.v1beta1.AuthInfo message.
+func decodeAuthInfo(data []byte, authInfo *cosmostypes.AuthInfo) error {
+	return parseProtoFields(data, func(f protoField) error {
+		var err error
+		switch f.num {
+		case 1:
+			var signerInfo cosmostypes.SignerInfo
+			err = f.decodeMessage(func(data []byte) error { return decodeSignerInfo(data, &signerInfo) })
+			authInfo.SignerInfos = append(authInfo.SignerInfos, signerInfo)
+		case 2:
+			err = f.decodeMessage(func(data []byte) error { return decodeFee(data, &authInfo.Fee) })
+		}
+		if err != nil {
+			return fmt.Errorf("auth info: %w", err)
+		}
+		return nil
+	})
+}
+
+// decodeSignerInfo decodes a cosmos.tx.v1beta1.SignerInfo message.
+func decodeSignerInfo(data []byte, signerInfo *cosmostypes.SignerInfo) error {
+	return parseProtoFields(data, func(f protoField) error {
+		var err error
+		switch f.num {
+		case 1:
+			var publicKey cosmostypes.Any
+			err = f.decodeMessage(func(data []byte) error { return decodeAny(data, &publicKey) })
+			signerInfo.PublicKey = &publicKey
+		case 3:
+			signerInfo.Sequence, err = f.getUint64()
+		}
+		if err != nil {
+			return fmt.Errorf("signer info: %w", err)
+		}
+		return nil
+	})
+}
+
+// decodeFee decodes a cosmos.tx.v1beta1.Fee message.
+func decodeFee(data []byte, fee *cosmostypes.Fee) error {
+	return parseProtoFields(data, func(f protoField) error {
+		var err error
+		switch f.num {
+		case 1:
+			var coin cosmostypes.Coin
+			err = f.decodeMessage(func(data []byte) error { return decodeCoin(data, &coin) })
+			fee.Amount = append(fee.Amount, coin)
+		case 2:
+			fee.GasLimit, err = f.getUint64()
+		case 3:
+			fee.Payer, err = f.getString()
+		case 4:
+			fee.Granter, err = f.getString()
+		}
+		if err != nil {
+			return fmt.Errorf("fee: %w", err)
+		}
+		return nil
+	})
+}
+
+// decodeCoin decodes a cosmos.base.v1beta1.Coin message.
+func decodeCoin(data []byte, coin *cosmostypes.Coin) error {
+	return parseProtoFields(data, func(f protoField) error {
+		var err error
+		switch f.num {
+		case 1:
+			coin.Denom, err = f.getString()
+		case 2:
+			coin.Amount, err = f.getString()
+		}
+		if err != nil {
+			return fmt.Errorf("coin: %w", err)
+		}
+		return nil
+	})
+}
+
+// decodeAny decodes a google.protobuf.Any message.
+func decodeAny(data []byte, value *cosmostypes.Any) error {
+	return parseProtoFields(data, func(f protoField) error {
+		var err error
+		switch f.num {
+		case 1:
+			value.TypeURL, err = f.getString()
+		case 2:
+			value.Value, err = f.getBytes()
+		}
+		if err != nil {
+			return fmt.Errorf("any: %w", err)
+		}
+		return nil
+	})
+}
+
+// appendAny decodes the google.protobuf.Any message contained in the
+// provided field and appends it to the given slice.
+func appendAny(values []cosmostypes.Any, f protoField) ([]cosmostypes.Any, error) {
+	var value cosmostypes.Any
+	err := f.decodeMessage(func(data []byte) error { return decodeAny(data, &value) })
+	return append(values, value), err
+}
+
+// ----------------------------------------------------------------------------
+// ---- Protobuf wire format
+// ----------------------------------------------------------------------------
+
+// protoField represents a field of a protobuf encoded message.
+type protoField struct {
+	num    protowire.Number
+	typ    protowire.Type
+	varint uint64
+	bytes  []byte
+}
+
+// parseProtoFields parses the fields of the provided protobuf encoded message,
+// calling fn for each one of them.
+func parseProtoFields(data []byte, fn func(f protoField) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		f := protoField{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return fmt.Errorf("field %d: %w", num, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f protoField) checkType(typ protowire.Type) error {
+	if f.typ != typ {
+		return fmt.Errorf("field %d: invalid wire type %d, expected %d", f.num, f.typ, typ)
+	}
+	return nil
+}
+
+func (f protoField) getUint64() (uint64, error) {
+	return f.varint, f.checkType(protowire.VarintType)
+}
+
+func (f protoField) getBytes() ([]byte, error) {
+	return f.bytes, f.checkType(protowire.BytesType)
+}
+
+func (f protoField) getString() (string, error) {
+	return string(f.bytes), f.checkType(protowire.BytesType)
+}
+
+func (f protoField) decodeMessage(decode func(data []byte) error) error {
+	if err := f.checkType(protowire.BytesType); err != nil {
+		return err
+	}
+	return decode(f.bytes)
+}
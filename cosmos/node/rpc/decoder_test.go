@@ -0,0 +1,102 @@
+package rpc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/milkyway-labs/flux/cosmos/node/rpc"
+	cosmostypes "github.com/milkyway-labs/flux/cosmos/types"
+)
+
+func appendBytesField(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func appendVarintField(b []byte, num protowire.Number, value uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+func encodeAny(typeURL string, value []byte) []byte {
+	var b []byte
+	b = appendBytesField(b, 1, []byte(typeURL))
+	return appendBytesField(b, 2, value)
+}
+
+func TestDefaultTxDecoder(t *testing.T) {
+	var body []byte
+	body = appendBytesField(body, 1, encodeAny("/cosmos.bank.v1beta1.MsgSend", []byte{1, 2}))
+	body = appendBytesField(body, 1, encodeAny("/cosmos.staking.v1beta1.MsgDelegate", []byte{3}))
+	body = appendBytesField(body, 2, []byte("memo"))
+	body = appendVarintField(body, 3, 100)
+	// Unknown fields are ignored
+	body = appendVarintField(body, 4, 1)
+
+	var coin []byte
+	coin = appendBytesField(coin, 1, []byte("uatom"))
+	coin = appendBytesField(coin, 2, []byte("5000"))
+
+	var fee []byte
+	fee = appendBytesField(fee, 1, coin)
+	fee = appendVarintField(fee, 2, 200000)
+	fee = appendBytesField(fee, 4, []byte("granter"))
+
+	var signerInfo []byte
+	signerInfo = appendBytesField(signerInfo, 1, encodeAny("/cosmos.crypto.secp256k1.PubKey", []byte{4}))
+	signerInfo = appendVarintField(signerInfo, 3, 7)
+
+	var authInfo []byte
+	authInfo = appendBytesField(authInfo, 1, signerInfo)
+	authInfo = appendBytesField(authInfo, 2, fee)
+
+	var txRaw []byte
+	txRaw = appendBytesField(txRaw, 1, body)
+	txRaw = appendBytesField(txRaw, 2, authInfo)
+	txRaw = appendBytesField(txRaw, 3, []byte{5, 6})
+
+	tx, err := rpc.DefaultTxDecoder(txRaw)
+	require.NoError(t, err)
+
+	require.Equal(t, []cosmostypes.Any{
+		cosmostypes.NewAny("/cosmos.bank.v1beta1.MsgSend", []byte{1, 2}),
+		cosmostypes.NewAny("/cosmos.staking.v1beta1.MsgDelegate", []byte{3}),
+	}, tx.GetMessages())
+	require.Equal(t, "memo", tx.GetMemo())
+	require.Equal(t, uint64(100), tx.Body.TimeoutHeight)
+	require.Equal(t, cosmostypes.Fee{
+		Amount:   []cosmostypes.Coin{{Denom: "uatom", Amount: "5000"}},
+		GasLimit: 200000,
+		Granter:  "granter",
+	}, tx.GetFee())
+
+	publicKey := cosmostypes.NewAny("/cosmos.crypto.secp256k1.PubKey", []byte{4})
+	require.Equal(t, []cosmostypes.SignerInfo{{PublicKey: &publicKey, Sequence: 7}}, tx.GetSigners())
+	require.Equal(t, [][]byte{{5, 6}}, tx.Signatures)
+}
+
+func TestDefaultTxDecoder_InvalidTx(t *testing.T) {
+	// Body encoded as a varint instead of a message
+	_, err := rpc.DefaultTxDecoder(appendVarintField(nil, 1, 10))
+	require.Error(t, err)
+
+	// Truncated message
+	_, err = rpc.DefaultTxDecoder([]byte{0x0a, 0x05, 0x01})
+	require.Error(t, err)
+}
+
+func TestTx_GetMessages(t *testing.T) {
+	tx := cosmostypes.NewTx(0, 0, "", nil, "HASH", nil, "", 0, 0, nil, nil)
+	require.Empty(t, tx.GetMessages())
+
+	tx.Decoded = &cosmostypes.DecodedTx{
+		Body: cosmostypes.TxBody{
+			Messages: []cosmostypes.Any{cosmostypes.NewAny("/cosmos.bank.v1beta1.MsgSend", nil)},
+		},
+	}
+	messages := tx.GetMessages()
+	require.Len(t, messages, 1)
+	require.Equal(t, "/cosmos.bank.v1beta1.MsgSend", messages[0].GetType())
+}
@@ -0,0 +1,352 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// latencySmoothing represents the weight given to a new latency sample
+// when updating the average latency of an endpoint.
+const latencySmoothing = 0.2
+
+var _ jsonrpc2.BatchCaller = &EndpointsPool{}
+
+// endpoint represents one of the RPC endpoints used by the node.
+type endpoint struct {
+	url    string
+	weight int64
+	client *jsonrpc2.Client
+	logger zerolog.Logger
+
+	// Weight used by the smooth weighted round-robin to select the endpoint.
+	currentWeight int64
+	// Tells if the endpoint can be used to perform the requests.
+	healthy bool
+	// Number of consecutive failed requests.
+	failures uint32
+	// Time at which the endpoint has been ejected.
+	ejectedAt time.Time
+	// Latest height reported by the endpoint.
+	height types.Height
+	// Average latency of the requests performed with the endpoint.
+	latency time.Duration
+}
+
+// EndpointsPool distributes the JSON-RPC calls across multiple endpoints.
+// The endpoints that fail or fall behind the others are ejected and
+// re-admitted once they pass a health check.
+type EndpointsPool struct {
+	cfg       Config
+	mu        sync.Mutex
+	endpoints []*endpoint
+	// ID of the chain that all the endpoints must serve.
+	chainID string
+	// Tells if a health check is running.
+	checking atomic.Bool
+}
+
+// NewEndpointsPool creates a new EndpointsPool with the endpoints defined in
+// the provided config, all the endpoints are considered healthy.
+func NewEndpointsPool(logger zerolog.Logger, cfg Config) (*EndpointsPool, error) {
+	endpointsCfg := cfg.GetEndpoints()
+	endpoints := make([]*endpoint, len(endpointsCfg))
+	for i, endpointCfg := range endpointsCfg {
+		client, err := jsonrpc2.NewClient(endpointCfg.URL, &http.Client{
+			Timeout: cfg.RequestTimeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create rpc client for %s: %w", endpointCfg.URL, err)
+		}
+		client.WithMaxRequestsPerSecond(cfg.MaxRequestsPerSecond).
+			WithMaxConcurrentRequests(cfg.MaxConcurrentRequests)
+
+		endpoints[i] = &endpoint{
+			url:     endpointCfg.URL,
+			weight:  int64(endpointCfg.Weight),
+			client:  client,
+			logger:  logger.With().Str("endpoint", endpointCfg.URL).Logger(),
+			healthy: true,
+		}
+	}
+
+	return &EndpointsPool{
+		cfg:       cfg,
+		endpoints: endpoints,
+	}, nil
+}
+
+// Call implements jsonrpc2.Caller.
+// The call is performed with one of the healthy endpoints selected following the
+// configured strategy, in case of failure the call is retried with the other endpoints.
+func (p *EndpointsPool) Call(ctx context.Context, method string, params any, result any) error {
+	return p.do(ctx, func(client *jsonrpc2.Client) error {
+		return client.Call(ctx, method, params, result)
+	})
+}
+
+// BatchCall implements jsonrpc2.BatchCaller.
+// The batch is sent to one of the healthy endpoints selected following the
+// configured strategy, in case of failure the batch is sent to the other endpoints.
+func (p *EndpointsPool) BatchCall(ctx context.Context, batch []jsonrpc2.BatchElem) error {
+	return p.do(ctx, func(client *jsonrpc2.Client) error {
+		return client.BatchCall(ctx, batch)
+	})
+}
+
+// do performs the request with the selected endpoint, failing over to the
+// other endpoints in case of failure.
+func (p *EndpointsPool) do(ctx context.Context, request func(client *jsonrpc2.Client) error) error {
+	p.checkEjectedEndpoints()
+
+	var lastErr error
+	tried := make(map[*endpoint]bool, len(p.endpoints))
+	for range p.endpoints {
+		e := p.selectEndpoint(tried)
+		if e == nil {
+			break
+		}
+		tried[e] = true
+
+		start := time.Now()
+		err := request(e.client)
+		if err == nil {
+			p.recordSuccess(e, time.Since(start))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		// The JSON-RPC errors are returned by working endpoints, so we try
+		// the other endpoints without considering it a failure
+		var rpcErr *jsonrpc2.Error
+		if !errors.As(err, &rpcErr) {
+			p.recordFailure(e, err)
+		}
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// GetBestHeight checks the health of the endpoints and gets the highest
+// height reported by the healthy ones.
+func (p *EndpointsPool) GetBestHeight(ctx context.Context) (types.Height, error) {
+	return p.checkEndpoints(ctx, false)
+}
+
+// setChainID sets the ID of the chain that all the endpoints must serve,
+// the endpoints serving a different chain are ejected.
+func (p *EndpointsPool) setChainID(chainID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.chainID = chainID
+}
+
+// selectEndpoint selects the endpoint that should perform the next call, ignoring
+// the already tried ones. If all the healthy endpoints have been tried, the ejected
+// ones are used as a last resort. Returns nil if all the endpoints have been tried.
+func (p *EndpointsPool) selectEndpoint(tried map[*endpoint]bool) *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var candidates []*endpoint
+	for _, e := range p.endpoints {
+		if e.healthy && !tried[e] {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, e := range p.endpoints {
+			if !tried[e] {
+				candidates = append(candidates, e)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	switch p.cfg.Strategy {
+	case StrategyLatency:
+		selected := candidates[0]
+		for _, e := range candidates[1:] {
+			if e.latency < selected.latency {
+				selected = e
+			}
+		}
+		return selected
+
+	default:
+		// Smooth weighted round-robin, see https://github.com/phusion/nginx/commit/27e94984486058d73157038f7950a0a36ecc6e35
+		var selected *endpoint
+		var totalWeight int64
+		for _, e := range candidates {
+			e.currentWeight += e.weight
+			totalWeight += e.weight
+			if selected == nil || e.currentWeight > selected.currentWeight {
+				selected = e
+			}
+		}
+		selected.currentWeight -= totalWeight
+		return selected
+	}
+}
+
+// recordSuccess updates the state of the endpoint after a successful call.
+func (p *EndpointsPool) recordSuccess(e *endpoint, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.failures = 0
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration((1-latencySmoothing)*float64(e.latency) + latencySmoothing*float64(latency))
+	}
+}
+
+// recordFailure updates the state of the endpoint after a failed call,
+// ejecting it once it reaches the maximum number of consecutive failures.
+func (p *EndpointsPool) recordFailure(e *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.failures++
+	if e.healthy && e.failures >= p.cfg.MaxFailures {
+		p.eject(e, err.Error())
+	}
+}
+
+// eject marks the endpoint as not healthy, must be called holding the lock.
+func (p *EndpointsPool) eject(e *endpoint, reason string) {
+	if e.healthy {
+		e.logger.Warn().Str("reason", reason).Msg("ejecting endpoint")
+	}
+	e.healthy = false
+	e.ejectedAt = time.Now()
+}
+
+// checkEjectedEndpoints starts a health check in background if
+// some of the ejected endpoints can be re-admitted.
+func (p *EndpointsPool) checkEjectedEndpoints() {
+	p.mu.Lock()
+	shouldCheck := false
+	for _, e := range p.endpoints {
+		if !e.healthy && time.Since(e.ejectedAt) >= p.cfg.EjectionTime {
+			shouldCheck = true
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	if shouldCheck && p.checking.CompareAndSwap(false, true) {
+		go func() {
+			defer p.checking.Store(false)
+			ctx, cancel := context.WithTimeout(context.Background(), p.cfg.RequestTimeout)
+			defer cancel()
+			_, _ = p.checkEndpoints(ctx, true)
+		}()
+	}
+}
+
+// checkEndpoints queries the status of the healthy endpoints and of the ejected
+// ones that can be re-admitted. The endpoints that fail to report their status,
+// serve a different chain or fall behind the best height are ejected, while
+// the other ones are re-admitted. If onlyEjected is true, the status of the healthy
+// endpoints that have already reported a height is not queried.
+// Returns the highest height reported by the healthy endpoints.
+func (p *EndpointsPool) checkEndpoints(ctx context.Context, onlyEjected bool) (types.Height, error) {
+	p.mu.Lock()
+	var toCheck []*endpoint
+	for _, e := range p.endpoints {
+		canBeReadmitted := !e.healthy && time.Since(e.ejectedAt) >= p.cfg.EjectionTime
+		if canBeReadmitted || (e.healthy && (!onlyEjected || e.height == 0)) {
+			toCheck = append(toCheck, e)
+		}
+	}
+	chainID := p.chainID
+	p.mu.Unlock()
+
+	// Query the endpoints concurrently
+	statuses := make([]*StatusResponse, len(toCheck))
+	errs := make([]error, len(toCheck))
+	wg := sync.WaitGroup{}
+	for i, e := range toCheck {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var res StatusResponse
+			if err := e.client.Call(ctx, "status", StatusRequest{}, &res); err != nil {
+				errs[i] = err
+				return
+			}
+			if chainID != "" && res.NodeInfo.Network != chainID {
+				errs[i] = fmt.Errorf("endpoint serves chain %s instead of %s", res.NodeInfo.Network, chainID)
+				return
+			}
+			statuses[i] = &res
+		}()
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, e := range toCheck {
+		if errs[i] != nil {
+			p.eject(e, errs[i].Error())
+			continue
+		}
+		e.height = statuses[i].SyncInfo.LatestBlockHeight
+	}
+
+	// Compute the best height, considering also the healthy endpoints that have not been checked
+	var bestHeight types.Height
+	found := false
+	for _, e := range p.endpoints {
+		if e.healthy && e.height > 0 {
+			bestHeight = max(bestHeight, e.height)
+			found = true
+		}
+	}
+	for i, status := range statuses {
+		if status != nil {
+			bestHeight = max(bestHeight, toCheck[i].height)
+			found = true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no healthy endpoints: %w", errors.Join(errs...))
+	}
+
+	// Eject the endpoints that fall behind and re-admit the other ones
+	for i, e := range toCheck {
+		if statuses[i] == nil {
+			continue
+		}
+
+		if uint64(bestHeight-e.height) > p.cfg.MaxHeightLag {
+			p.eject(e, fmt.Sprintf("endpoint height %d is behind the best height %d", e.height, bestHeight))
+			continue
+		}
+
+		if !e.healthy {
+			e.logger.Info().Msg("re-admitting endpoint")
+		}
+		e.healthy = true
+		e.failures = 0
+	}
+
+	return bestHeight, nil
+}
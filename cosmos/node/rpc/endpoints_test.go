@@ -0,0 +1,125 @@
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/cosmos/node/rpc"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// newStatusServer creates a server that replies to the status requests with the given height.
+func newStatusServer(t *testing.T, chainID string, height types.Height) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w,
+			`{"jsonrpc":"2.0","id":-1,"result":{"node_info":{"network":"%s"},"sync_info":{"latest_block_height":"%d","earliest_block_height":"1"}}}`,
+			chainID, height,
+		)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newFailingServer creates a server that always replies with an internal server error.
+func newFailingServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newPoolConfig(urls ...string) rpc.Config {
+	cfg := rpc.DefaultConfig("")
+	for _, url := range urls {
+		cfg.Endpoints = append(cfg.Endpoints, rpc.NewEndpointConfig(url, 1))
+	}
+	return cfg
+}
+
+func TestEndpointsPool_CallFailover(t *testing.T) {
+	failing := newFailingServer(t)
+	working := newStatusServer(t, "test-1", 100)
+
+	cfg := newPoolConfig(failing.URL, working.URL)
+	require.NoError(t, cfg.Validate())
+	pool, err := rpc.NewEndpointsPool(zerolog.Nop(), cfg)
+	require.NoError(t, err)
+
+	// All the calls must succeed, even the ones sent to the failing endpoint
+	for range 10 {
+		var res rpc.StatusResponse
+		require.NoError(t, pool.Call(context.Background(), "status", rpc.StatusRequest{}, &res))
+		require.Equal(t, types.Height(100), res.SyncInfo.LatestBlockHeight)
+	}
+}
+
+func TestEndpointsPool_AllEndpointsFailing(t *testing.T) {
+	cfg := newPoolConfig(newFailingServer(t).URL, newFailingServer(t).URL)
+	pool, err := rpc.NewEndpointsPool(zerolog.Nop(), cfg)
+	require.NoError(t, err)
+
+	var res rpc.StatusResponse
+	require.Error(t, pool.Call(context.Background(), "status", rpc.StatusRequest{}, &res))
+
+	_, err = pool.GetBestHeight(context.Background())
+	require.Error(t, err)
+}
+
+func TestEndpointsPool_GetBestHeight(t *testing.T) {
+	cfg := newPoolConfig(
+		newStatusServer(t, "test-1", 100).URL,
+		newStatusServer(t, "test-1", 105).URL,
+		newFailingServer(t).URL,
+	)
+	pool, err := rpc.NewEndpointsPool(zerolog.Nop(), cfg)
+	require.NoError(t, err)
+
+	height, err := pool.GetBestHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(105), height)
+}
+
+func TestEndpointsPool_EjectLaggingEndpoint(t *testing.T) {
+	lagging := newStatusServer(t, "test-1", 80)
+	lagging.Config.Handler = countRequests(lagging.Config.Handler)
+	cfg := newPoolConfig(newStatusServer(t, "test-1", 100).URL, lagging.URL)
+	pool, err := rpc.NewEndpointsPool(zerolog.Nop(), cfg)
+	require.NoError(t, err)
+
+	height, err := pool.GetBestHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(100), height)
+
+	// The lagging endpoint has been ejected and should not receive other requests
+	requests := lagging.Config.Handler.(*requestsCounter).count.Load()
+	for range 10 {
+		var res rpc.StatusResponse
+		require.NoError(t, pool.Call(context.Background(), "status", rpc.StatusRequest{}, &res))
+		require.Equal(t, types.Height(100), res.SyncInfo.LatestBlockHeight)
+	}
+	require.Equal(t, requests, lagging.Config.Handler.(*requestsCounter).count.Load())
+}
+
+// requestsCounter is an http.Handler that counts the received requests.
+type requestsCounter struct {
+	handler http.Handler
+	count   atomic.Int64
+}
+
+func countRequests(handler http.Handler) *requestsCounter {
+	return &requestsCounter{handler: handler}
+}
+
+func (c *requestsCounter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.count.Add(1)
+	c.handler.ServeHTTP(w, r)
+}
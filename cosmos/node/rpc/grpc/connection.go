@@ -21,12 +21,12 @@ var (
 // GRPCOverRPC represents a custom gRPC connection implementation that relies on a RPC connection instead of
 // a gRPC connection
 type GRPCOverRPC struct {
-	jsonrpcClient *jsonrpc2.Client
+	jsonrpcClient jsonrpc2.Caller
 	gprcCdc       encoding.Codec
 }
 
 // NewGRPCOverRPC creates a new GRPCOverRPC instance
-func NewGRPCOverRPC(jsonRPCClient *jsonrpc2.Client, cdc encoding.Codec) *GRPCOverRPC {
+func NewGRPCOverRPC(jsonRPCClient jsonrpc2.Caller, cdc encoding.Codec) *GRPCOverRPC {
 	return &GRPCOverRPC{
 		jsonrpcClient: jsonRPCClient,
 		gprcCdc:       cdc,
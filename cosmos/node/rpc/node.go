@@ -3,7 +3,7 @@ package rpc
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"strings"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/encoding"
@@ -11,39 +11,64 @@ import (
 	"github.com/milkyway-labs/flux/cosmos/node/rpc/grpc"
 	cosmostypes "github.com/milkyway-labs/flux/cosmos/types"
 	"github.com/milkyway-labs/flux/node"
-	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
 	"github.com/milkyway-labs/flux/types"
 )
 
-var _ node.Node = &Node{}
+var (
+	_ node.Node                    = &Node{}
+	_ node.FinalizedHeightProvider = &Node{}
+)
 
 type Node struct {
-	cfg      Config
-	logger   zerolog.Logger
-	client   *jsonrpc2.Client
-	chainID  string
-	txHasher TxHasher
+	cfg    Config
+	logger zerolog.Logger
+	client *EndpointsPool
+	// Prefetcher used to fetch the upcoming blocks, nil if the
+	// prefetch is disabled.
+	prefetcher *blocksPrefetcher
+	chainID    string
+	txHasher   TxHasher
+	// Decoder used to decode the txs, nil if the txs are not decoded.
+	txDecoder TxDecoder
 }
 
 func NewNode(ctx context.Context, logger zerolog.Logger, cfg Config) (*Node, error) {
-	jsonRPCClient, err := jsonrpc2.NewClient(cfg.URL, &http.Client{
-		Timeout: cfg.RequestTimeout,
-	})
+	urls := make([]string, 0, len(cfg.GetEndpoints()))
+	for _, endpoint := range cfg.GetEndpoints() {
+		urls = append(urls, endpoint.URL)
+	}
+	logger = logger.With().Str("cosmos-node", strings.Join(urls, ",")).Logger()
+
+	pool, err := NewEndpointsPool(logger, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("create rpc client: %w", err)
+		return nil, err
 	}
 
 	var res StatusResponse
-	if err := jsonRPCClient.Call(ctx, "status", StatusRequest{}, &res); err != nil {
+	if err := pool.Call(ctx, "status", StatusRequest{}, &res); err != nil {
 		return nil, fmt.Errorf("get chain id: %w", err)
 	}
+	// Make sure that all the endpoints serve the same chain
+	pool.setChainID(res.NodeInfo.Network)
+
+	var prefetcher *blocksPrefetcher
+	if cfg.PrefetchBlocks > 0 {
+		prefetcher = newBlocksPrefetcher(pool, cfg.PrefetchBlocks)
+	}
+
+	var txDecoder TxDecoder
+	if cfg.DecodeTxs {
+		txDecoder = DefaultTxDecoder
+	}
 
 	return &Node{
-		cfg:      cfg,
-		logger:   logger.With().Str("cosmos-node", cfg.URL).Logger(),
-		client:   jsonRPCClient,
-		chainID:  res.NodeInfo.Network,
-		txHasher: DefaultTxHasher,
+		cfg:        cfg,
+		logger:     logger,
+		client:     pool,
+		prefetcher: prefetcher,
+		chainID:    res.NodeInfo.Network,
+		txHasher:   DefaultTxHasher,
+		txDecoder:  txDecoder,
 	}, nil
 }
 
@@ -53,13 +78,20 @@ func (r *Node) GetChainID() string {
 }
 
 // GetCurrentHeight implements node.Node.
+// The height is the highest one reported by the healthy endpoints.
 func (r *Node) GetCurrentHeight(ctx context.Context) (types.Height, error) {
-	var res StatusResponse
-	if err := r.client.Call(ctx, "status", StatusRequest{}, &res); err != nil {
+	height, err := r.client.GetBestHeight(ctx)
+	if err != nil {
 		return 0, fmt.Errorf("call status: %w", err)
 	}
 
-	return res.SyncInfo.LatestBlockHeight, nil
+	return height, nil
+}
+
+// GetFinalizedHeight implements node.FinalizedHeightProvider.
+// CometBFT provides instant finality, so the latest block is always final.
+func (r *Node) GetFinalizedHeight(ctx context.Context) (types.Height, error) {
+	return r.GetCurrentHeight(ctx)
 }
 
 // GetLowestHeight implements node.Node.
@@ -74,15 +106,11 @@ func (r *Node) GetLowestHeight(ctx context.Context) (types.Height, error) {
 
 // GetBlock implements node.Node.
 func (r *Node) GetBlock(ctx context.Context, height types.Height) (types.Block, error) {
-	var blockResponse BlockResponse
-	if err := r.client.Call(ctx, "block", BlockRequest{Height: &height}, &blockResponse); err != nil {
-		return nil, fmt.Errorf("call block: %w", err)
-	}
-
-	var blockResultsResponse BlockResultsResponse
-	if err := r.client.Call(ctx, "block_results", BlockResultsRequest{Height: &height}, &blockResultsResponse); err != nil {
-		return nil, fmt.Errorf("call block_results: %w", err)
+	raw, err := r.getRawBlock(ctx, height)
+	if err != nil {
+		return nil, err
 	}
+	blockResponse, blockResultsResponse := raw.block, raw.results
 
 	// Extract the tx events
 	txs := make([]cosmostypes.Tx, len(blockResultsResponse.TxsResults))
@@ -102,14 +130,32 @@ func (r *Node) GetBlock(ctx context.Context, height types.Height) (types.Block,
 			txEvents = txResult.Events
 		}
 
-		hash := r.txHasher(blockResponse.Block.Txs[txIndex].Bytes())
+		rawTx := blockResponse.Block.Txs[txIndex].Bytes()
+		hash := r.txHasher(rawTx)
 		hexHash := fmt.Sprintf("%X", hash)
+
+		var decodedTx *cosmostypes.DecodedTx
+		if r.txDecoder != nil {
+			decodedTx, err = r.txDecoder(rawTx)
+			if err != nil {
+				// The txs that are not protobuf encoded (e.g. the ones injected by
+				// the chain) are indexed without the decoded data
+				r.logger.Warn().Err(err).Uint64("height", uint64(height)).Str("tx", hexHash).Msg("decode tx")
+				decodedTx = nil
+			}
+		}
 		txs[txIndex] = cosmostypes.NewTx(
+			uint32(txIndex),
 			txResult.Code,
+			txResult.Codespace,
 			txResult.Data,
 			hexHash,
 			txEvents,
 			txResult.Log,
+			txResult.GasWanted,
+			txResult.GasUsed,
+			rawTx,
+			decodedTx,
 		)
 	}
 
@@ -160,16 +206,52 @@ func (r *Node) GetBlock(ctx context.Context, height types.Height) (types.Block,
 		blockResultsResponse.EndBlockEvents = append(blockResultsResponse.EndBlockEvents, endBlockEvents...)
 	}
 
-	blockHeader := cosmostypes.NewBlockHeader(blockResponse.Block.ChainID, blockResponse.Block.Height, blockResponse.Block.Time)
+	header := blockResponse.Block.BlockHeader
+	blockHeader := cosmostypes.BlockHeader{
+		ChainID:            header.ChainID,
+		Height:             header.Height,
+		Time:               header.Time,
+		Hash:               blockResponse.BlockID.Hash.String(),
+		ParentHash:         header.LastBlockID.Hash.String(),
+		ProposerAddress:    header.ProposerAddress.String(),
+		AppHash:            header.AppHash.String(),
+		LastCommitHash:     header.LastCommitHash.String(),
+		DataHash:           header.DataHash.String(),
+		ValidatorsHash:     header.ValidatorsHash.String(),
+		NextValidatorsHash: header.NextValidatorsHash.String(),
+		ConsensusHash:      header.ConsensusHash.String(),
+		LastResultsHash:    header.LastResultsHash.String(),
+		EvidenceHash:       header.EvidenceHash.String(),
+	}
+
+	validatorUpdates := make([]cosmostypes.ValidatorUpdate, len(blockResultsResponse.ValidatorUpdates))
+	for i, update := range blockResultsResponse.ValidatorUpdates {
+		validatorUpdates[i] = update.ToValidatorUpdate()
+	}
+
 	return cosmostypes.NewBlock(
 		blockHeader,
 		txs,
 		blockResultsResponse.BeginBlockEvents,
 		blockResultsResponse.EndBlockEvents,
 		blockResultsResponse.FinalizeBlockEvents,
+		blockResponse.Block.LastCommit.ToCommit(),
+		validatorUpdates,
+		blockResultsResponse.ConsensusParamUpdates.ToConsensusParams(),
 	), nil
 }
 
+// getRawBlock fetches the block and block_results responses of the
+// provided height with a single batch request.
+func (r *Node) getRawBlock(ctx context.Context, height types.Height) (*rawBlock, error) {
+	if r.prefetcher != nil {
+		return r.prefetcher.getBlock(ctx, height)
+	}
+
+	blocks, errs := fetchRawBlocks(ctx, r.client, []types.Height{height})
+	return blocks[0], errs[0]
+}
+
 // Config gets the Node configuration.
 func (r *Node) Config() Config {
 	return r.cfg
@@ -181,6 +263,13 @@ func (r *Node) NewGRPCOverRPC(codec encoding.Codec) *grpc.GRPCOverRPC {
 	return grpc.NewGRPCOverRPC(r.client, codec)
 }
 
+// WithTxDecoder sets the decoder used to decode the transactions included in a block.
+// If no `txDecoder` is provided, the transactions are not decoded.
+func (r *Node) WithTxDecoder(txDecoder TxDecoder) *Node {
+	r.txDecoder = txDecoder
+	return r
+}
+
 // WithCustomTxHasher modifies how the node calculates the hash of a transaction included in a block.
 // If no `txHasher` is provided, the default hash function is used.
 func (r *Node) WithCustomTxHasher(txHasher TxHasher) *Node {
@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// rawBlock contains the node responses required to build a block.
+type rawBlock struct {
+	block   BlockResponse
+	results BlockResultsResponse
+}
+
+// fetchRawBlocks fetches the blocks at the provided heights using a single batch request.
+// Returns the fetched blocks along with the error that occurred while fetching each of them.
+func fetchRawBlocks(ctx context.Context, client jsonrpc2.BatchCaller, heights []types.Height) ([]*rawBlock, []error) {
+	blocks := make([]*rawBlock, len(heights))
+	batch := make([]jsonrpc2.BatchElem, 0, len(heights)*2)
+	for i := range heights {
+		blocks[i] = &rawBlock{}
+		batch = append(batch,
+			jsonrpc2.NewBatchElem("block", BlockRequest{Height: &heights[i]}, &blocks[i].block),
+			jsonrpc2.NewBatchElem("block_results", BlockResultsRequest{Height: &heights[i]}, &blocks[i].results),
+		)
+	}
+
+	errs := make([]error, len(heights))
+	if err := client.BatchCall(ctx, batch); err != nil {
+		for i := range errs {
+			errs[i] = fmt.Errorf("batch call: %w", err)
+		}
+		return blocks, errs
+	}
+
+	for i := range heights {
+		if err := batch[i*2].Error; err != nil {
+			errs[i] = fmt.Errorf("call block: %w", err)
+		} else if err := batch[i*2+1].Error; err != nil {
+			errs[i] = fmt.Errorf("call block_results: %w", err)
+		}
+	}
+
+	return blocks, errs
+}
+
+// prefetchEntry represents a block that is being prefetched.
+type prefetchEntry struct {
+	height types.Height
+	// Channel closed once the block has been fetched.
+	done  chan struct{}
+	block *rawBlock
+	err   error
+}
+
+// isDone tells if the block has been fetched.
+func (e *prefetchEntry) isDone() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// blocksPrefetcher fetches the requested blocks along with the upcoming ones using
+// a single batch request, so that the requests of the upcoming blocks can be
+// served without contacting the node.
+type blocksPrefetcher struct {
+	client   jsonrpc2.BatchCaller
+	prefetch types.Height
+
+	mu sync.Mutex
+	// Blocks that have been prefetched and not yet requested, each
+	// block is delivered only once.
+	entries map[types.Height]*prefetchEntry
+}
+
+func newBlocksPrefetcher(client jsonrpc2.BatchCaller, prefetch uint32) *blocksPrefetcher {
+	return &blocksPrefetcher{
+		client:   client,
+		prefetch: types.Height(prefetch),
+		entries:  make(map[types.Height]*prefetchEntry),
+	}
+}
+
+// getBlock gets the block at the provided height, fetching also the upcoming
+// blocks that have not been prefetched yet.
+func (p *blocksPrefetcher) getBlock(ctx context.Context, height types.Height) (*rawBlock, error) {
+	p.mu.Lock()
+	entry, found := p.entries[height]
+	if found {
+		delete(p.entries, height)
+		p.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.err == nil {
+			return entry.block, nil
+		}
+
+		// The prefetch failed, fetch the block again
+		blocks, errs := fetchRawBlocks(ctx, p.client, []types.Height{height})
+		return blocks[0], errs[0]
+	}
+
+	p.prune(height)
+	entries := p.reserve(height)
+	p.mu.Unlock()
+
+	heights := make([]types.Height, len(entries)+1)
+	heights[0] = height
+	for i, entry := range entries {
+		heights[i+1] = entry.height
+	}
+
+	blocks, errs := fetchRawBlocks(ctx, p.client, heights)
+	for i, entry := range entries {
+		entry.block, entry.err = blocks[i+1], errs[i+1]
+		close(entry.done)
+	}
+
+	return blocks[0], errs[0]
+}
+
+// reserve reserves the entries of the blocks following the provided height that
+// are not being prefetched, must be called holding the lock.
+func (p *blocksPrefetcher) reserve(height types.Height) []*prefetchEntry {
+	var entries []*prefetchEntry
+	for h := height + 1; h <= height+p.prefetch; h++ {
+		// Limit the number of blocks kept in memory
+		if types.Height(len(p.entries)) >= p.prefetch*4 {
+			break
+		}
+		if _, found := p.entries[h]; found {
+			continue
+		}
+
+		entry := &prefetchEntry{height: h, done: make(chan struct{})}
+		p.entries[h] = entry
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// prune removes the fetched blocks that are far behind the provided
+// height, since they will likely never be requested.
+// Must be called holding the lock.
+func (p *blocksPrefetcher) prune(height types.Height) {
+	for h, entry := range p.entries {
+		if h+p.prefetch*2 < height && entry.isDone() {
+			delete(p.entries, h)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+var _ jsonrpc2.BatchCaller = &testBatchCaller{}
+
+// testBatchCaller is a jsonrpc2.BatchCaller that provides the blocks up to
+// the given height and tracks the requested heights.
+type testBatchCaller struct {
+	mu        sync.Mutex
+	maxHeight types.Height
+	requested []types.Height
+}
+
+func (c *testBatchCaller) Call(context.Context, string, any, any) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (c *testBatchCaller) BatchCall(_ context.Context, batch []jsonrpc2.BatchElem) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range batch {
+		elem := &batch[i]
+		switch params := elem.Params.(type) {
+		case BlockRequest:
+			if *params.Height > c.maxHeight {
+				elem.Error = fmt.Errorf("height %d is not available", *params.Height)
+				continue
+			}
+			c.requested = append(c.requested, *params.Height)
+			elem.Result.(*BlockResponse).Block.Height = *params.Height
+		case BlockResultsRequest:
+			if *params.Height > c.maxHeight {
+				elem.Error = fmt.Errorf("height %d is not available", *params.Height)
+				continue
+			}
+			elem.Result.(*BlockResultsResponse).Height = *params.Height
+		}
+	}
+	return nil
+}
+
+func TestBlocksPrefetcher(t *testing.T) {
+	client := &testBatchCaller{maxHeight: 12}
+	prefetcher := newBlocksPrefetcher(client, 5)
+
+	// The first request fetches also the upcoming blocks
+	block, err := prefetcher.getBlock(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, types.Height(1), block.block.Block.Height)
+	require.Equal(t, []types.Height{1, 2, 3, 4, 5, 6}, client.requested)
+
+	// The prefetched blocks are served without contacting the node
+	for height := types.Height(2); height <= 6; height++ {
+		block, err := prefetcher.getBlock(context.Background(), height)
+		require.NoError(t, err)
+		require.Equal(t, height, block.block.Block.Height)
+	}
+	require.Len(t, client.requested, 6)
+
+	// The blocks after the chain tip are fetched again once requested
+	client.requested = nil
+	_, err = prefetcher.getBlock(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, []types.Height{10, 11, 12}, client.requested)
+
+	_, err = prefetcher.getBlock(context.Background(), 13)
+	require.Error(t, err)
+
+	client.maxHeight = 13
+	block, err = prefetcher.getBlock(context.Background(), 13)
+	require.NoError(t, err)
+	require.Equal(t, types.Height(13), block.block.Block.Height)
+}
@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// newBlockQuery is the query used to subscribe to the new blocks.
+const newBlockQuery = "tm.event='NewBlock'"
+
+var _ node.HeightsSubscriber = &Node{}
+
+// SubscribeHeights implements node.HeightsSubscriber.
+// The new heights are received through the CometBFT WebSocket endpoint,
+// returns node.ErrSubscriptionNotSupported if the websocket_url is not configured.
+func (r *Node) SubscribeHeights(ctx context.Context) (<-chan types.Height, error) {
+	if r.cfg.WebSocketURL == "" {
+		return nil, node.ErrSubscriptionNotSupported
+	}
+
+	client, err := jsonrpc2.DialWebSocket(ctx, r.cfg.WebSocketURL)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribeCtx, cancel := context.WithTimeout(ctx, r.cfg.RequestTimeout)
+	defer cancel()
+	var res SubscribeResponse
+	err = client.Call(subscribeCtx, "subscribe", SubscribeRequest{Query: newBlockQuery}, &res)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("subscribe to new blocks: %w", err)
+	}
+
+	heights := make(chan types.Height)
+	go func() {
+		defer close(heights)
+		defer client.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification, ok := <-client.Notifications():
+				if !ok {
+					r.logger.Warn().Err(client.Err()).Msg("websocket connection closed")
+					return
+				}
+				if notification.Error != nil {
+					r.logger.Warn().Err(notification.Error).Msg("new blocks subscription error")
+					return
+				}
+
+				var event NewBlockEvent
+				if err := json.Unmarshal(notification.Result, &event); err != nil {
+					r.logger.Warn().Err(err).Msg("unmarshal new block event")
+					continue
+				}
+				// Ignore the messages that are not related to the new blocks
+				if event.Query != newBlockQuery {
+					continue
+				}
+
+				select {
+				case heights <- event.Data.Value.Block.Height:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return heights, nil
+}
@@ -29,23 +29,68 @@ type BlockRequest struct {
 	Height *types.Height `json:"height,string,omitempty"`
 }
 
+type BlockID struct {
+	Hash types.HexBytes `json:"hash"`
+}
+
 type BlockResponse struct {
-	Block Block `json:"block"`
+	BlockID BlockID `json:"block_id"`
+	Block   Block   `json:"block"`
 }
 
 type BlockHeader struct {
-	ChainID string       `json:"chain_id"`
-	Height  types.Height `json:"height,string"`
-	Time    time.Time    `json:"time"`
+	ChainID            string         `json:"chain_id"`
+	Height             types.Height   `json:"height,string"`
+	Time               time.Time      `json:"time"`
+	LastBlockID        BlockID        `json:"last_block_id"`
+	LastCommitHash     types.HexBytes `json:"last_commit_hash"`
+	DataHash           types.HexBytes `json:"data_hash"`
+	ValidatorsHash     types.HexBytes `json:"validators_hash"`
+	NextValidatorsHash types.HexBytes `json:"next_validators_hash"`
+	ConsensusHash      types.HexBytes `json:"consensus_hash"`
+	AppHash            types.HexBytes `json:"app_hash"`
+	LastResultsHash    types.HexBytes `json:"last_results_hash"`
+	EvidenceHash       types.HexBytes `json:"evidence_hash"`
+	ProposerAddress    types.HexBytes `json:"proposer_address"`
 }
 
 type BlockData struct {
 	Txs []types.Base64Bytes `json:"txs"`
 }
 
+type CommitSig struct {
+	BlockIDFlag      uint8             `json:"block_id_flag"`
+	ValidatorAddress types.HexBytes    `json:"validator_address"`
+	Timestamp        time.Time         `json:"timestamp"`
+	Signature        types.Base64Bytes `json:"signature"`
+}
+
+type Commit struct {
+	Height     types.Height `json:"height,string"`
+	Round      int32        `json:"round"`
+	BlockID    BlockID      `json:"block_id"`
+	Signatures []CommitSig  `json:"signatures"`
+}
+
+// ToCommit converts the commit into a cosmostypes.Commit.
+func (c Commit) ToCommit() cosmostypes.Commit {
+	signatures := make([]cosmostypes.CommitSig, len(c.Signatures))
+	for i, signature := range c.Signatures {
+		signatures[i] = cosmostypes.CommitSig{
+			BlockIDFlag:      cosmostypes.BlockIDFlag(signature.BlockIDFlag),
+			ValidatorAddress: signature.ValidatorAddress.String(),
+			Timestamp:        signature.Timestamp,
+			Signature:        signature.Signature,
+		}
+	}
+
+	return cosmostypes.NewCommit(c.Height, c.Round, c.BlockID.Hash.String(), signatures)
+}
+
 type Block struct {
 	BlockHeader `json:"header"`
 	BlockData   `json:"data"`
+	LastCommit  Commit `json:"last_commit"`
 }
 
 type BlockResultsRequest struct {
@@ -53,15 +98,107 @@ type BlockResultsRequest struct {
 }
 
 type BlockResultsResponse struct {
-	Height              types.Height           `json:"height,string"`
-	TxsResults          []ResponseDeliverTx    `json:"txs_results"`
-	BeginBlockEvents    cosmostypes.ABCIEvents `json:"begin_block_events"`
-	EndBlockEvents      cosmostypes.ABCIEvents `json:"end_block_events"`
-	FinalizeBlockEvents cosmostypes.ABCIEvents `json:"finalize_block_events"`
+	Height                types.Height           `json:"height,string"`
+	TxsResults            []ResponseDeliverTx    `json:"txs_results"`
+	BeginBlockEvents      cosmostypes.ABCIEvents `json:"begin_block_events"`
+	EndBlockEvents        cosmostypes.ABCIEvents `json:"end_block_events"`
+	FinalizeBlockEvents   cosmostypes.ABCIEvents `json:"finalize_block_events"`
+	ValidatorUpdates      []ValidatorUpdate      `json:"validator_updates"`
+	ConsensusParamUpdates *ConsensusParams       `json:"consensus_param_updates"`
+}
+
+// PublicKey represents a public key encoded as a protobuf oneof, the Sum value
+// contains a single entry whose key is the public key type.
+type PublicKey struct {
+	Sum PublicKeySum `json:"Sum"`
+}
+
+type PublicKeySum struct {
+	Type  string                       `json:"type"`
+	Value map[string]types.Base64Bytes `json:"value"`
+}
+
+type ValidatorUpdate struct {
+	PubKey PublicKey `json:"pub_key"`
+	Power  int64     `json:"power,string"`
+}
+
+// ToValidatorUpdate converts the update into a cosmostypes.ValidatorUpdate.
+func (u ValidatorUpdate) ToValidatorUpdate() cosmostypes.ValidatorUpdate {
+	var pubKeyType string
+	var pubKey []byte
+	for keyType, key := range u.PubKey.Sum.Value {
+		pubKeyType = keyType
+		pubKey = key
+	}
+
+	return cosmostypes.NewValidatorUpdate(pubKeyType, pubKey, u.Power)
+}
+
+type BlockParams struct {
+	MaxBytes int64 `json:"max_bytes,string"`
+	MaxGas   int64 `json:"max_gas,string"`
+}
+
+type EvidenceParams struct {
+	MaxAgeNumBlocks int64 `json:"max_age_num_blocks,string"`
+	// Duration expressed in nanoseconds.
+	MaxAgeDuration int64 `json:"max_age_duration,string"`
+	MaxBytes       int64 `json:"max_bytes,string"`
+}
+
+type ValidatorParams struct {
+	PubKeyTypes []string `json:"pub_key_types"`
+}
+
+type VersionParams struct {
+	App uint64 `json:"app,string"`
+}
+
+type ConsensusParams struct {
+	Block     *BlockParams     `json:"block"`
+	Evidence  *EvidenceParams  `json:"evidence"`
+	Validator *ValidatorParams `json:"validator"`
+	Version   *VersionParams   `json:"version"`
+}
+
+// ToConsensusParams converts the params into cosmostypes.ConsensusParams.
+func (p *ConsensusParams) ToConsensusParams() *cosmostypes.ConsensusParams {
+	if p == nil {
+		return nil
+	}
+
+	result := &cosmostypes.ConsensusParams{}
+	if p.Block != nil {
+		result.Block = &cosmostypes.BlockParams{
+			MaxBytes: p.Block.MaxBytes,
+			MaxGas:   p.Block.MaxGas,
+		}
+	}
+	if p.Evidence != nil {
+		result.Evidence = &cosmostypes.EvidenceParams{
+			MaxAgeNumBlocks: p.Evidence.MaxAgeNumBlocks,
+			MaxAgeDuration:  time.Duration(p.Evidence.MaxAgeDuration),
+			MaxBytes:        p.Evidence.MaxBytes,
+		}
+	}
+	if p.Validator != nil {
+		result.Validator = &cosmostypes.ValidatorParams{
+			PubKeyTypes: p.Validator.PubKeyTypes,
+		}
+	}
+	if p.Version != nil {
+		result.Version = &cosmostypes.VersionParams{
+			App: p.Version.App,
+		}
+	}
+
+	return result
 }
 
 type ResponseDeliverTx struct {
 	Code      uint32                 `json:"code"`
+	Codespace string                 `json:"codespace"`
 	Data      types.Base64Bytes      `json:"data"`
 	Log       string                 `json:"log"`
 	GasWanted int64                  `json:"gas_wanted,string"`
@@ -72,3 +209,23 @@ type ResponseDeliverTx struct {
 func (resp ResponseDeliverTx) IsOK() bool {
 	return resp.Code == 0
 }
+
+type SubscribeRequest struct {
+	Query string `json:"query"`
+}
+
+type SubscribeResponse struct{}
+
+type NewBlockEventValue struct {
+	Block Block `json:"block"`
+}
+
+type NewBlockEventData struct {
+	Type  string             `json:"type"`
+	Value NewBlockEventValue `json:"value"`
+}
+
+type NewBlockEvent struct {
+	Query string            `json:"query"`
+	Data  NewBlockEventData `json:"data"`
+}
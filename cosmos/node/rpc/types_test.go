@@ -0,0 +1,88 @@
+package rpc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/cosmos/node/rpc"
+	cosmostypes "github.com/milkyway-labs/flux/cosmos/types"
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestBlockResponse_Unmarshal(t *testing.T) {
+	data := `{
+		"block_id": {"hash": "AA"},
+		"block": {
+			"header": {
+				"chain_id": "test-1",
+				"height": "10",
+				"time": "2024-01-01T00:00:00Z",
+				"last_block_id": {"hash": "BB"},
+				"app_hash": "CC",
+				"proposer_address": "DD"
+			},
+			"data": {"txs": []},
+			"last_commit": {
+				"height": "9",
+				"round": 1,
+				"block_id": {"hash": "BB"},
+				"signatures": [
+					{"block_id_flag": 2, "validator_address": "DD", "timestamp": "2024-01-01T00:00:00Z", "signature": "AQI="},
+					{"block_id_flag": 1, "validator_address": "", "timestamp": "0001-01-01T00:00:00Z", "signature": null}
+				]
+			}
+		}
+	}`
+
+	var res rpc.BlockResponse
+	require.NoError(t, json.Unmarshal([]byte(data), &res))
+	require.Equal(t, "CC", res.Block.AppHash.String())
+	require.Equal(t, "DD", res.Block.ProposerAddress.String())
+
+	commit := res.Block.LastCommit.ToCommit()
+	require.Equal(t, types.Height(9), commit.Height)
+	require.Equal(t, int32(1), commit.Round)
+	require.Equal(t, "BB", commit.BlockHash)
+	require.Len(t, commit.Signatures, 2)
+	require.True(t, commit.Signatures[0].IsSigned())
+	require.Equal(t, "DD", commit.Signatures[0].ValidatorAddress)
+	require.Equal(t, []byte{1, 2}, commit.Signatures[0].Signature)
+	require.False(t, commit.Signatures[1].IsSigned())
+}
+
+func TestBlockResultsResponse_Unmarshal(t *testing.T) {
+	data := `{
+		"height": "10",
+		"txs_results": [{"code": 5, "codespace": "sdk", "gas_wanted": "200", "gas_used": "150"}],
+		"validator_updates": [
+			{"pub_key": {"Sum": {"type": "tendermint.crypto.PublicKey_Ed25519", "value": {"ed25519": "AQI="}}}, "power": "100"}
+		],
+		"consensus_param_updates": {
+			"block": {"max_bytes": "22020096", "max_gas": "-1"},
+			"evidence": {"max_age_num_blocks": "100000", "max_age_duration": "172800000000000", "max_bytes": "1048576"},
+			"validator": {"pub_key_types": ["ed25519"]}
+		}
+	}`
+
+	var res rpc.BlockResultsResponse
+	require.NoError(t, json.Unmarshal([]byte(data), &res))
+
+	require.Equal(t, "sdk", res.TxsResults[0].Codespace)
+	require.Equal(t, int64(200), res.TxsResults[0].GasWanted)
+	require.Equal(t, int64(150), res.TxsResults[0].GasUsed)
+
+	require.Len(t, res.ValidatorUpdates, 1)
+	require.Equal(t,
+		cosmostypes.NewValidatorUpdate("ed25519", []byte{1, 2}, 100),
+		res.ValidatorUpdates[0].ToValidatorUpdate(),
+	)
+
+	params := res.ConsensusParamUpdates.ToConsensusParams()
+	require.Equal(t, &cosmostypes.BlockParams{MaxBytes: 22020096, MaxGas: -1}, params.Block)
+	require.Equal(t, 48*time.Hour, params.Evidence.MaxAgeDuration)
+	require.Equal(t, []string{"ed25519"}, params.Validator.PubKeyTypes)
+	require.Nil(t, params.Version)
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"slices"
 
+	"github.com/milkyway-labs/flux/types"
 	"github.com/milkyway-labs/flux/utils"
 )
 
@@ -15,11 +16,24 @@ type ABCIEventAttribute struct {
 // ---- ABCI Event
 // ---------------------------------------------------------------------------
 
+var _ types.Event = &ABCIEvent{}
+
 type ABCIEvent struct {
 	Type       string               `json:"type"`
 	Attributes []ABCIEventAttribute `json:"attributes"`
 }
 
+// GetType implements types.Event.
+func (e *ABCIEvent) GetType() string {
+	return e.Type
+}
+
+// GetAttributeValue implements types.Event.
+func (e *ABCIEvent) GetAttributeValue(key string) (string, bool) {
+	attribute, found := e.FindAttribute(key)
+	return attribute.Value, found
+}
+
 // FindAttribute finds the first attribute that matches the given predicate
 func (e *ABCIEvent) FindAttributeFunc(predicate func(a ABCIEventAttribute) bool) (ABCIEventAttribute, bool) {
 	index := slices.IndexFunc(e.Attributes, predicate)
@@ -85,6 +99,15 @@ func (events ABCIEvents) FindEventsWithType(t string) []ABCIEvent {
 	})
 }
 
+// ToEvents converts the events into a slice of types.Event
+func (events ABCIEvents) ToEvents() []types.Event {
+	result := make([]types.Event, len(events))
+	for i := range events {
+		result[i] = &events[i]
+	}
+	return result
+}
+
 // ---------------------------------------------------------------------------
 // ---- ABCI Event contained inside the tx log
 // ---------------------------------------------------------------------------
@@ -11,16 +11,37 @@ import (
 // ----------------------------------------------------------------------------
 
 type BlockHeader struct {
-	ChainID string
-	Height  types.Height
-	Time    time.Time
+	ChainID    string
+	Height     types.Height
+	Time       time.Time
+	Hash       string
+	ParentHash string
+	// Address of the validator that proposed the block.
+	ProposerAddress string
+	// Hashes of the chain state and of the data included in the block.
+	AppHash            string
+	LastCommitHash     string
+	DataHash           string
+	ValidatorsHash     string
+	NextValidatorsHash string
+	ConsensusHash      string
+	LastResultsHash    string
+	EvidenceHash       string
 }
 
-func NewBlockHeader(chainID string, height types.Height, time time.Time) BlockHeader {
+func NewBlockHeader(
+	chainID string,
+	height types.Height,
+	time time.Time,
+	hash string,
+	parentHash string,
+) BlockHeader {
 	return BlockHeader{
-		ChainID: chainID,
-		Height:  height,
-		Time:    time,
+		ChainID:    chainID,
+		Height:     height,
+		Time:       time,
+		Hash:       hash,
+		ParentHash: parentHash,
 	}
 }
 
@@ -30,9 +51,16 @@ type Block struct {
 	BeginBlockEvents    ABCIEvents
 	EndBlockEvents      ABCIEvents
 	FinalizeBlockEvents ABCIEvents
+	// LastCommit contains the signatures of the validators that committed the previous block.
+	LastCommit Commit
+	// ValidatorUpdates contains the changes to the validator set applied by the block.
+	ValidatorUpdates []ValidatorUpdate
+	// ConsensusParamUpdates contains the consensus params updated by the block, nil
+	// if the block didn't update them.
+	ConsensusParamUpdates *ConsensusParams
 }
 
-var _ types.Block = &Block{}
+var _ types.EventsBlock = &Block{}
 
 func NewBlock(
 	header BlockHeader,
@@ -40,13 +68,19 @@ func NewBlock(
 	beginBlockEvents ABCIEvents,
 	endBlockEvents ABCIEvents,
 	finalizeBlockEvents ABCIEvents,
+	lastCommit Commit,
+	validatorUpdates []ValidatorUpdate,
+	consensusParamUpdates *ConsensusParams,
 ) *Block {
 	return &Block{
-		Header:              header,
-		Txs:                 txs,
-		BeginBlockEvents:    beginBlockEvents,
-		EndBlockEvents:      endBlockEvents,
-		FinalizeBlockEvents: finalizeBlockEvents,
+		Header:                header,
+		Txs:                   txs,
+		BeginBlockEvents:      beginBlockEvents,
+		EndBlockEvents:        endBlockEvents,
+		FinalizeBlockEvents:   finalizeBlockEvents,
+		LastCommit:            lastCommit,
+		ValidatorUpdates:      validatorUpdates,
+		ConsensusParamUpdates: consensusParamUpdates,
 	}
 }
 
@@ -60,6 +94,16 @@ func (b *Block) GetHeight() types.Height {
 	return b.Header.Height
 }
 
+// GetHash implements types.Block.
+func (b *Block) GetHash() string {
+	return b.Header.Hash
+}
+
+// GetParentHash implements types.Block.
+func (b *Block) GetParentHash() string {
+	return b.Header.ParentHash
+}
+
 // GetTimeStamp implements types.Block.
 func (b *Block) GetTimeStamp() time.Time {
 	return b.Header.Time
@@ -74,33 +118,67 @@ func (b *Block) GetTxs() []types.Tx {
 	return result
 }
 
+// GetEvents implements types.EventsBlock.
+func (b *Block) GetEvents() []types.Event {
+	events := make([]types.Event, 0, len(b.BeginBlockEvents)+len(b.EndBlockEvents)+len(b.FinalizeBlockEvents))
+	events = append(events, b.BeginBlockEvents.ToEvents()...)
+	events = append(events, b.EndBlockEvents.ToEvents()...)
+	events = append(events, b.FinalizeBlockEvents.ToEvents()...)
+	return events
+}
+
 // ----------------------------------------------------------------------------
 // -- Tx related data structures
 // ----------------------------------------------------------------------------
 
-var _ types.Tx = &Tx{}
+var (
+	_ types.EventsTx   = &Tx{}
+	_ types.MessagesTx = &Tx{}
+)
 
 type Tx struct {
-	Code   uint32
-	Data   []byte
-	TxHash string
-	Events ABCIEvents
-	Log    string
+	// Index of the tx inside the block.
+	Index     uint32
+	Code      uint32
+	Codespace string
+	Data      []byte
+	TxHash    string
+	Events    ABCIEvents
+	Log       string
+	GasWanted int64
+	GasUsed   int64
+	// Raw contains the bytes of the tx as included in the block.
+	Raw []byte
+	// Decoded contains the tx decoded from the raw bytes, nil if the node
+	// is not decoding the txs or if the tx couldn't be decoded.
+	Decoded *DecodedTx
 }
 
 func NewTx(
+	index uint32,
 	code uint32,
+	codespace string,
 	data []byte,
 	hash string,
 	events ABCIEvents,
 	log string,
+	gasWanted int64,
+	gasUsed int64,
+	raw []byte,
+	decoded *DecodedTx,
 ) Tx {
 	return Tx{
-		Code:   code,
-		Data:   data,
-		TxHash: hash,
-		Events: events,
-		Log:    log,
+		Index:     index,
+		Code:      code,
+		Codespace: codespace,
+		Data:      data,
+		TxHash:    hash,
+		Events:    events,
+		Log:       log,
+		GasWanted: gasWanted,
+		GasUsed:   gasUsed,
+		Raw:       raw,
+		Decoded:   decoded,
 	}
 }
 
@@ -113,3 +191,21 @@ func (t *Tx) GetHash() string {
 func (t *Tx) IsSuccessful() bool {
 	return t.Code == 0
 }
+
+// GetEvents implements types.EventsTx.
+func (t *Tx) GetEvents() []types.Event {
+	return t.Events.ToEvents()
+}
+
+// GetMessages implements types.MessagesTx.
+func (t *Tx) GetMessages() []types.Message {
+	if t.Decoded == nil {
+		return nil
+	}
+
+	messages := make([]types.Message, len(t.Decoded.Body.Messages))
+	for i := range t.Decoded.Body.Messages {
+		messages[i] = &t.Decoded.Body.Messages[i]
+	}
+	return messages
+}
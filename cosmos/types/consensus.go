@@ -0,0 +1,108 @@
+package types
+
+import (
+	"time"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+// ----------------------------------------------------------------------------
+// -- Commit related data structures
+// ----------------------------------------------------------------------------
+
+// BlockIDFlag indicates which BlockID the signature is for.
+type BlockIDFlag uint8
+
+const (
+	// BlockIDFlagUnknown indicates an unknown flag.
+	BlockIDFlagUnknown BlockIDFlag = iota
+	// BlockIDFlagAbsent indicates that the validator didn't sign the block.
+	BlockIDFlagAbsent
+	// BlockIDFlagCommit indicates that the validator voted for the block.
+	BlockIDFlagCommit
+	// BlockIDFlagNil indicates that the validator voted for nil.
+	BlockIDFlagNil
+)
+
+// CommitSig represents the signature of a validator included in a commit.
+type CommitSig struct {
+	BlockIDFlag      BlockIDFlag
+	ValidatorAddress string
+	Timestamp        time.Time
+	Signature        []byte
+}
+
+// IsSigned tells if the validator signed the committed block.
+func (s CommitSig) IsSigned() bool {
+	return s.BlockIDFlag == BlockIDFlagCommit
+}
+
+// Commit contains the signatures of the validators that committed a block.
+type Commit struct {
+	Height     types.Height
+	Round      int32
+	BlockHash  string
+	Signatures []CommitSig
+}
+
+func NewCommit(height types.Height, round int32, blockHash string, signatures []CommitSig) Commit {
+	return Commit{
+		Height:     height,
+		Round:      round,
+		BlockHash:  blockHash,
+		Signatures: signatures,
+	}
+}
+
+// ----------------------------------------------------------------------------
+// -- Validator set related data structures
+// ----------------------------------------------------------------------------
+
+// ValidatorUpdate represents a change of the validator set, a validator
+// with zero power is removed from the set.
+type ValidatorUpdate struct {
+	// Type of the validator public key (e.g. ed25519).
+	PubKeyType string
+	PubKey     []byte
+	Power      int64
+}
+
+func NewValidatorUpdate(pubKeyType string, pubKey []byte, power int64) ValidatorUpdate {
+	return ValidatorUpdate{
+		PubKeyType: pubKeyType,
+		PubKey:     pubKey,
+		Power:      power,
+	}
+}
+
+// ----------------------------------------------------------------------------
+// -- Consensus params related data structures
+// ----------------------------------------------------------------------------
+
+// ConsensusParams represents the consensus params updated by a block,
+// the params that have not been updated are nil.
+type ConsensusParams struct {
+	Block     *BlockParams
+	Evidence  *EvidenceParams
+	Validator *ValidatorParams
+	Version   *VersionParams
+}
+
+type BlockParams struct {
+	MaxBytes int64
+	MaxGas   int64
+}
+
+type EvidenceParams struct {
+	MaxAgeNumBlocks int64
+	MaxAgeDuration  time.Duration
+	MaxBytes        int64
+}
+
+type ValidatorParams struct {
+	PubKeyTypes []string
+}
+
+type VersionParams struct {
+	App uint64
+}
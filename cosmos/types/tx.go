@@ -0,0 +1,100 @@
+package types
+
+import (
+	"google.golang.org/grpc/encoding"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+// ----------------------------------------------------------------------------
+// -- Decoded tx related data structures
+// ----------------------------------------------------------------------------
+
+var _ types.Message = &Any{}
+
+// Any represents a protobuf message along with its type URL
+// (e.g. /cosmos.bank.v1beta1.MsgSend).
+type Any struct {
+	TypeURL string
+	Value   []byte
+}
+
+func NewAny(typeURL string, value []byte) Any {
+	return Any{
+		TypeURL: typeURL,
+		Value:   value,
+	}
+}
+
+// GetType implements types.Message.
+func (a *Any) GetType() string {
+	return a.TypeURL
+}
+
+// Unmarshal decodes the message value into the provided message
+// using the given codec.
+func (a *Any) Unmarshal(cdc encoding.Codec, message any) error {
+	return cdc.Unmarshal(a.Value, message)
+}
+
+type Coin struct {
+	Denom  string
+	Amount string
+}
+
+type Fee struct {
+	Amount   []Coin
+	GasLimit uint64
+	// Address of the account that pays the fee, if empty the first signer pays it.
+	Payer string
+	// Address of the account that granted the fee allowance, if any.
+	Granter string
+}
+
+// SignerInfo represents one of the signers of a tx.
+type SignerInfo struct {
+	// PublicKey of the signer, nil if the account public key is already
+	// known by the chain.
+	PublicKey *Any
+	Sequence  uint64
+}
+
+type AuthInfo struct {
+	SignerInfos []SignerInfo
+	Fee         Fee
+}
+
+type TxBody struct {
+	Messages                    []Any
+	Memo                        string
+	TimeoutHeight               uint64
+	ExtensionOptions            []Any
+	NonCriticalExtensionOptions []Any
+}
+
+// DecodedTx represents a Cosmos SDK tx decoded from its raw bytes.
+type DecodedTx struct {
+	Body       TxBody
+	AuthInfo   AuthInfo
+	Signatures [][]byte
+}
+
+// GetMessages gets the messages included in the tx.
+func (t *DecodedTx) GetMessages() []Any {
+	return t.Body.Messages
+}
+
+// GetMemo gets the memo of the tx.
+func (t *DecodedTx) GetMemo() string {
+	return t.Body.Memo
+}
+
+// GetFee gets the fee paid by the tx.
+func (t *DecodedTx) GetFee() Fee {
+	return t.AuthInfo.Fee
+}
+
+// GetSigners gets the signers of the tx.
+func (t *DecodedTx) GetSigners() []SignerInfo {
+	return t.AuthInfo.SignerInfos
+}
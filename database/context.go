@@ -0,0 +1,21 @@
+package database
+
+import "context"
+
+type BlockTxContextKey string
+
+const blockTxContextKey = BlockTxContextKey("database.block_tx")
+
+// InjectBlockTx returns a copy of the provided context that contains the given BlockTx.
+func InjectBlockTx(ctx context.Context, tx BlockTx) context.Context {
+	return context.WithValue(ctx, blockTxContextKey, tx)
+}
+
+// GetBlockTx gets the BlockTx stored in the provided context.
+// Modules can use this to perform their writes inside the same transaction
+// used by the indexer to store the block being processed.
+// Returns false if the context doesn't contain a BlockTx.
+func GetBlockTx(ctx context.Context) (BlockTx, bool) {
+	tx, ok := ctx.Value(blockTxContextKey).(BlockTx)
+	return tx, ok
+}
@@ -1,11 +1,81 @@
 package database
 
 import (
+	"context"
 	"time"
 
 	"github.com/milkyway-labs/flux/types"
 )
 
+// IndexedBlock represents the information that the indexer stores about
+// a block that has been indexed.
+type IndexedBlock struct {
+	Height     types.Height
+	Hash       string
+	ParentHash string
+	Timestamp  time.Time
+}
+
+func NewIndexedBlock(height types.Height, hash string, parentHash string, timestamp time.Time) IndexedBlock {
+	return IndexedBlock{
+		Height:     height,
+		Hash:       hash,
+		ParentHash: parentHash,
+		Timestamp:  timestamp,
+	}
+}
+
+// HeightRange represents a range of consecutive heights, including both From and To.
+type HeightRange struct {
+	From types.Height `json:"from"`
+	To   types.Height `json:"to"`
+}
+
+func NewHeightRange(from types.Height, to types.Height) HeightRange {
+	return HeightRange{
+		From: from,
+		To:   to,
+	}
+}
+
+// ExpandHeightRanges returns all the heights included in the provided ranges.
+func ExpandHeightRanges(ranges []HeightRange) []types.Height {
+	var result []types.Height
+	for _, heightRange := range ranges {
+		for height := heightRange.From; height <= heightRange.To; height++ {
+			result = append(result, height)
+			// Avoid the overflow when To is the max height
+			if height == heightRange.To {
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// FailedBlock represents a block that the indexer failed to index after
+// reaching the maximum number of attempts.
+type FailedBlock struct {
+	Height types.Height
+	// Module contains the name of the module that failed to process the block,
+	// empty if the failure is not related to a module (e.g. the block could not be fetched).
+	Module    string
+	Error     string
+	Attempts  uint32
+	Timestamp time.Time
+}
+
+func NewFailedBlock(height types.Height, module string, err string, attempts uint32, timestamp time.Time) FailedBlock {
+	return FailedBlock{
+		Height:    height,
+		Module:    module,
+		Error:     err,
+		Attempts:  attempts,
+		Timestamp: timestamp,
+	}
+}
+
 // Database represents a database used by the indexer to store the indexing state.
 type Database interface {
 	// GetLowestBlock retrieves the height of the lowest indexed block by the
@@ -17,7 +87,83 @@ type Database interface {
 	// A block is considered missing if it has not been indexed yet
 	// or if a previous indexing operation failed.
 	GetMissingBlocks(indexer string, chainID string, from types.Height, to types.Height) ([]types.Height, error)
-	// Stores in the database that the given height for the chain with the provided ID
+	// GetModuleMissingBlocks retrieves the blocks that need to be processed by the module with
+	// the provided name in the provided block range.
+	// A block is considered missing if the module has not processed it yet, even if
+	// it has been already processed by the other indexer's modules.
+	GetModuleMissingBlocks(indexer string, chainID string, module string, from types.Height, to types.Height) ([]types.Height, error)
+	// GetMissingRanges retrieves the same blocks returned by GetMissingBlocks grouped in ranges of
+	// consecutive heights, sorted by height. This should be preferred over GetMissingBlocks on long
+	// histories, since the size of the result depends on the number of gaps instead of the number of heights.
+	GetMissingRanges(indexer string, chainID string, from types.Height, to types.Height) ([]HeightRange, error)
+	// GetModuleMissingRanges retrieves the same blocks returned by GetModuleMissingBlocks grouped in
+	// ranges of consecutive heights, sorted by height.
+	GetModuleMissingRanges(indexer string, chainID string, module string, from types.Height, to types.Height) ([]HeightRange, error)
+	// GetIndexedBlock retrieves the block indexed by the provided indexer at the given height.
+	// If the block has not been indexed, a nil block is returned.
+	GetIndexedBlock(indexer string, chainID string, height types.Height) (*IndexedBlock, error)
+	// Stores in the database that the given block for the chain with the provided ID
 	// has been indexed by the provided indexer.
-	SaveIndexedBlock(indexer string, chainID string, height types.Height, timestamp time.Time) error
+	SaveIndexedBlock(indexer string, chainID string, block IndexedBlock) error
+	// DeleteIndexedBlocks removes all the blocks indexed by the provided indexer and
+	// its modules, along with the failures stored for them, having a height greater
+	// or equal to the provided one.
+	// This is used to discard the blocks that have been orphaned by a chain reorganization.
+	DeleteIndexedBlocks(indexer string, chainID string, from types.Height) error
+	// InitModulesProgress stores all the blocks indexed by the provided indexer as processed by
+	// the provided modules, only if none of the indexer's modules has processed a block yet.
+	// This allows the indexers that have indexed blocks before the progress of each module
+	// was tracked to not process them again with all the modules.
+	// Returns true if the progress of the modules has been initialized.
+	InitModulesProgress(indexer string, chainID string, modules []string) (bool, error)
+	// SaveFailedBlock stores the provided block as failed, so that it can be
+	// inspected and retried later.
+	SaveFailedBlock(indexer string, chainID string, block FailedBlock) error
+	// GetFailedBlocks retrieves the blocks that the provided indexer failed to index,
+	// ordered by height.
+	GetFailedBlocks(indexer string, chainID string) ([]FailedBlock, error)
+	// DeleteFailedBlocks removes all the failures stored for the block at the provided height.
+	DeleteFailedBlocks(indexer string, chainID string, height types.Height) error
+	// BeginBlockTx starts a new BlockTx that can be used to perform all the writes
+	// related to a block atomically.
+	BeginBlockTx(ctx context.Context) (BlockTx, error)
+}
+
+// BlockTx represents a unit of work used by the indexer to group all the writes
+// performed while indexing a block, so that the data written by the modules and
+// the indexed block are either committed or discarded together.
+// The modules that don't depend on each other share the same BlockTx while
+// processing a block concurrently, so the implementations must be safe for concurrent use.
+type BlockTx interface {
+	// SaveIndexedBlock stores inside the transaction that the given block for the chain
+	// with the provided ID has been indexed by the provided indexer.
+	SaveIndexedBlock(indexer string, chainID string, block IndexedBlock) error
+	// SaveModuleIndexedBlock stores inside the transaction that the block at the given height
+	// has been processed by the module with the provided name.
+	SaveModuleIndexedBlock(indexer string, chainID string, module string, height types.Height) error
+	// DeleteIndexedBlocks removes inside the transaction all the blocks indexed by the provided
+	// indexer and its modules, along with the failures stored for them, having a height
+	// greater or equal to the provided one.
+	DeleteIndexedBlocks(indexer string, chainID string, from types.Height) error
+	// DeleteFailedBlocks removes inside the transaction all the failures stored
+	// for the block at the provided height.
+	DeleteFailedBlocks(indexer string, chainID string, height types.Height) error
+	// DeleteModuleFailedBlock removes inside the transaction the failure stored for the
+	// block at the provided height by the module with the provided name.
+	// An empty module name refers to the failure not related to a module.
+	DeleteModuleFailedBlock(indexer string, chainID string, module string, height types.Height) error
+	// Commit commits all the writes performed inside the transaction.
+	Commit() error
+	// Rollback discards all the writes performed inside the transaction.
+	// Calling Rollback after Commit has no effect.
+	Rollback() error
+}
+
+// HeightPreparer represents a Database that needs to prepare its storage before
+// the block at a given height is indexed, e.g. creating the partition that contains it.
+type HeightPreparer interface {
+	// PrepareHeight is called by the indexer before starting the BlockTx used to
+	// index the block at the provided height, so that the storage is prepared
+	// outside of the transaction.
+	PrepareHeight(height types.Height) error
 }
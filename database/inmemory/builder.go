@@ -0,0 +1,26 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/milkyway-labs/flux/database"
+)
+
+const DatabaseType = "memory"
+
+func DatabaseBuilder(
+	_ context.Context,
+	_ string,
+	rawConfig []byte,
+) (database.Database, error) {
+	var config Config
+	err := yaml.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal memory db config: %w", err)
+	}
+
+	return NewDatabase(&config), nil
+}
@@ -0,0 +1,32 @@
+package inmemory
+
+type Config struct {
+	// MaxStoredBlocks represents the number of most recent blocks whose hash,
+	// parent hash and timestamp are kept in memory, the older blocks are only
+	// tracked by height. Set to 0 to keep the details of all the blocks.
+	MaxStoredBlocks uint64 `yaml:"max_stored_blocks"`
+}
+
+func NewConfig(maxStoredBlocks uint64) Config {
+	return Config{
+		MaxStoredBlocks: maxStoredBlocks,
+	}
+}
+
+func DefaultConfig() Config {
+	return NewConfig(10_000)
+}
+
+// Implements the Unmarshaler interface of the yaml pkg.
+func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
+	// Local type to avoid recursion during the unmarshal
+	type privateCfg Config
+	config := privateCfg(DefaultConfig())
+	err := unmarshal(&config)
+	if err != nil {
+		return err
+	}
+
+	*c = Config(config)
+	return nil
+}
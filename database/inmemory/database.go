@@ -0,0 +1,352 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// type check to ensure interface is properly implemented
+var _ database.Database = &Database{}
+
+// chainKey identifies the data stored by an indexer for a chain.
+type chainKey struct {
+	indexer string
+	chainID string
+}
+
+// failedBlockKey identifies a failure stored for a block.
+type failedBlockKey struct {
+	height types.Height
+	module string
+}
+
+// chainState contains the data stored by an indexer for a chain.
+type chainState struct {
+	// Heights of the indexed blocks.
+	heights heightRanges
+	// Details of the most recent indexed blocks.
+	blocks map[types.Height]database.IndexedBlock
+	// Highest indexed height, used to discard the details of the old blocks.
+	highest types.Height
+	// Heights processed by each module.
+	modules map[string]heightRanges
+	failed  map[failedBlockKey]database.FailedBlock
+}
+
+func newChainState() *chainState {
+	return &chainState{
+		blocks:  make(map[types.Height]database.IndexedBlock),
+		modules: make(map[string]heightRanges),
+		failed:  make(map[failedBlockKey]database.FailedBlock),
+	}
+}
+
+// Database implements database.Database keeping all the data in memory.
+// The indexed heights are stored as ranges, so that the memory used doesn't
+// grow with the number of indexed blocks, while the hash, parent hash and
+// timestamp are kept only for the most recent blocks.
+type Database struct {
+	cfg *Config
+
+	mu     sync.RWMutex
+	chains map[chainKey]*chainState
+}
+
+func NewDatabase(cfg *Config) *Database {
+	return &Database{
+		cfg:    cfg,
+		chains: make(map[chainKey]*chainState),
+	}
+}
+
+// getChain returns the state of the provided chain, nil if the chain has no data.
+// The caller must hold the lock.
+func (db *Database) getChain(indexer string, chainID string) *chainState {
+	return db.chains[chainKey{indexer: indexer, chainID: chainID}]
+}
+
+// getOrCreateChain returns the state of the provided chain, creating it if it doesn't exist.
+// The caller must hold the write lock.
+func (db *Database) getOrCreateChain(indexer string, chainID string) *chainState {
+	key := chainKey{indexer: indexer, chainID: chainID}
+	chain, found := db.chains[key]
+	if !found {
+		chain = newChainState()
+		db.chains[key] = chain
+	}
+	return chain
+}
+
+// GetLowestBlock implements database.Database.
+func (db *Database) GetLowestBlock(indexer string, chainID string) (*types.Height, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	chain := db.getChain(indexer, chainID)
+	if chain == nil {
+		return nil, nil
+	}
+
+	height, found := chain.heights.lowest()
+	if !found {
+		return nil, nil
+	}
+	return &height, nil
+}
+
+// GetMissingBlocks implements database.Database.
+func (db *Database) GetMissingBlocks(indexer string, chainID string, from types.Height, to types.Height) ([]types.Height, error) {
+	missingRanges, err := db.GetMissingRanges(indexer, chainID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return database.ExpandHeightRanges(missingRanges), nil
+}
+
+// GetModuleMissingBlocks implements database.Database.
+func (db *Database) GetModuleMissingBlocks(
+	indexer string,
+	chainID string,
+	module string,
+	from types.Height,
+	to types.Height,
+) ([]types.Height, error) {
+	missingRanges, err := db.GetModuleMissingRanges(indexer, chainID, module, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return database.ExpandHeightRanges(missingRanges), nil
+}
+
+// GetMissingRanges implements database.Database.
+func (db *Database) GetMissingRanges(indexer string, chainID string, from types.Height, to types.Height) ([]database.HeightRange, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range, from(%d) must not be greater than to(%d)", from, to)
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var heights heightRanges
+	if chain := db.getChain(indexer, chainID); chain != nil {
+		heights = chain.heights
+	}
+	return heights.missing(from, to), nil
+}
+
+// GetModuleMissingRanges implements database.Database.
+func (db *Database) GetModuleMissingRanges(
+	indexer string,
+	chainID string,
+	module string,
+	from types.Height,
+	to types.Height,
+) ([]database.HeightRange, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range, from(%d) must not be greater than to(%d)", from, to)
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var heights heightRanges
+	if chain := db.getChain(indexer, chainID); chain != nil {
+		heights = chain.modules[module]
+	}
+	return heights.missing(from, to), nil
+}
+
+// GetIndexedBlock implements database.Database.
+// The blocks whose details have been discarded are returned with only their height.
+func (db *Database) GetIndexedBlock(indexer string, chainID string, height types.Height) (*database.IndexedBlock, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	chain := db.getChain(indexer, chainID)
+	if chain == nil || !chain.heights.contains(height) {
+		return nil, nil
+	}
+
+	block, found := chain.blocks[height]
+	if !found {
+		block = database.IndexedBlock{Height: height}
+	}
+	return &block, nil
+}
+
+// SaveIndexedBlock implements database.Database.
+func (db *Database) SaveIndexedBlock(indexer string, chainID string, block database.IndexedBlock) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.saveIndexedBlock(indexer, chainID, block)
+	return nil
+}
+
+// DeleteIndexedBlocks implements database.Database.
+func (db *Database) DeleteIndexedBlocks(indexer string, chainID string, from types.Height) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.deleteIndexedBlocks(indexer, chainID, from)
+	return nil
+}
+
+// InitModulesProgress implements database.Database.
+func (db *Database) InitModulesProgress(indexer string, chainID string, modules []string) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	chain := db.getChain(indexer, chainID)
+	if chain == nil || len(chain.heights) == 0 {
+		return false, nil
+	}
+	for _, heights := range chain.modules {
+		if len(heights) > 0 {
+			return false, nil
+		}
+	}
+
+	for _, module := range modules {
+		chain.modules[module] = chain.heights.clone()
+	}
+	return len(modules) > 0, nil
+}
+
+// SaveFailedBlock implements database.Database.
+func (db *Database) SaveFailedBlock(indexer string, chainID string, block database.FailedBlock) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	chain := db.getOrCreateChain(indexer, chainID)
+	chain.failed[failedBlockKey{height: block.Height, module: block.Module}] = block
+	return nil
+}
+
+// GetFailedBlocks implements database.Database.
+func (db *Database) GetFailedBlocks(indexer string, chainID string) ([]database.FailedBlock, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	chain := db.getChain(indexer, chainID)
+	if chain == nil || len(chain.failed) == 0 {
+		return nil, nil
+	}
+
+	result := slices.Collect(maps.Values(chain.failed))
+	slices.SortFunc(result, compareFailedBlocks)
+	return result, nil
+}
+
+// DeleteFailedBlocks implements database.Database.
+func (db *Database) DeleteFailedBlocks(indexer string, chainID string, height types.Height) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.deleteFailedBlocks(indexer, chainID, height)
+	return nil
+}
+
+// BeginBlockTx implements database.Database.
+func (db *Database) BeginBlockTx(_ context.Context) (database.BlockTx, error) {
+	return newBlockTx(db), nil
+}
+
+// saveIndexedBlock stores the provided block as indexed.
+// The caller must hold the write lock.
+func (db *Database) saveIndexedBlock(indexer string, chainID string, block database.IndexedBlock) {
+	chain := db.getOrCreateChain(indexer, chainID)
+	chain.heights = chain.heights.add(block.Height)
+	chain.blocks[block.Height] = block
+	chain.highest = max(chain.highest, block.Height)
+
+	// Discard the details of the old blocks once they are twice the configured
+	// amount, so that the cost of the cleanup is spread across the saved blocks
+	maxStoredBlocks := db.cfg.MaxStoredBlocks
+	if maxStoredBlocks > 0 && uint64(len(chain.blocks)) > 2*maxStoredBlocks {
+		for height := range chain.blocks {
+			if uint64(chain.highest-height) >= maxStoredBlocks {
+				delete(chain.blocks, height)
+			}
+		}
+	}
+}
+
+// saveModuleIndexedBlock stores that the provided module has processed the block at the given height.
+// The caller must hold the write lock.
+func (db *Database) saveModuleIndexedBlock(indexer string, chainID string, module string, height types.Height) {
+	chain := db.getOrCreateChain(indexer, chainID)
+	chain.modules[module] = chain.modules[module].add(height)
+}
+
+// deleteIndexedBlocks removes the blocks, and their failures, having a height greater
+// or equal to the provided one.
+// The caller must hold the write lock.
+func (db *Database) deleteIndexedBlocks(indexer string, chainID string, from types.Height) {
+	chain := db.getChain(indexer, chainID)
+	if chain == nil {
+		return
+	}
+
+	chain.heights = chain.heights.removeFrom(from)
+	for module, heights := range chain.modules {
+		chain.modules[module] = heights.removeFrom(from)
+	}
+	for height := range chain.blocks {
+		if height >= from {
+			delete(chain.blocks, height)
+		}
+	}
+	for key := range chain.failed {
+		if key.height >= from {
+			delete(chain.failed, key)
+		}
+	}
+	if chain.highest >= from {
+		chain.highest = 0
+		if len(chain.heights) > 0 {
+			chain.highest = chain.heights[len(chain.heights)-1].To
+		}
+	}
+}
+
+// deleteFailedBlocks removes all the failures stored for the block at the provided height.
+// The caller must hold the write lock.
+func (db *Database) deleteFailedBlocks(indexer string, chainID string, height types.Height) {
+	chain := db.getChain(indexer, chainID)
+	if chain == nil {
+		return
+	}
+
+	for key := range chain.failed {
+		if key.height == height {
+			delete(chain.failed, key)
+		}
+	}
+}
+
+// deleteModuleFailedBlock removes the failure stored for the block at the provided height by the given module.
+// The caller must hold the write lock.
+func (db *Database) deleteModuleFailedBlock(indexer string, chainID string, module string, height types.Height) {
+	chain := db.getChain(indexer, chainID)
+	if chain == nil {
+		return
+	}
+
+	delete(chain.failed, failedBlockKey{height: height, module: module})
+}
+
+// compareFailedBlocks sorts the failed blocks by height and module.
+func compareFailedBlocks(a, b database.FailedBlock) int {
+	if c := cmp.Compare(a.Height, b.Height); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Module, b.Module)
+}
@@ -0,0 +1,26 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milkyway-labs/flux/database/inmemory"
+	dbsuite "github.com/milkyway-labs/flux/database/suite"
+)
+
+func TestDatabaseTestSuite(t *testing.T) {
+	testSuite := new(DbTestSuite)
+	testSuite.WithBeforeTestHook(testSuite.SetupTest)
+	suite.Run(t, testSuite)
+}
+
+type DbTestSuite struct {
+	dbsuite.Suite
+}
+
+func (suite *DbTestSuite) SetupTest() {
+	// Use a new database for each test, discarding the data of the previous one
+	dbCfg := inmemory.DefaultConfig()
+	suite.InitDB(inmemory.NewDatabase(&dbCfg))
+}
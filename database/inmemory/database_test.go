@@ -0,0 +1,132 @@
+package inmemory_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/database/inmemory"
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestDatabase_MaxStoredBlocks(t *testing.T) {
+	cfg := inmemory.NewConfig(10)
+	db := inmemory.NewDatabase(&cfg)
+
+	for height := types.Height(1); height <= 100; height++ {
+		block := database.NewIndexedBlock(height, fmt.Sprintf("hash-%d", height), fmt.Sprintf("hash-%d", height-1), time.Now())
+		require.NoError(t, db.SaveIndexedBlock("indexer", "chain", block))
+	}
+
+	// The old blocks are tracked only by height
+	block, err := db.GetIndexedBlock("indexer", "chain", 1)
+	require.NoError(t, err)
+	require.Equal(t, &database.IndexedBlock{Height: 1}, block)
+
+	// The most recent blocks keep all their details
+	block, err = db.GetIndexedBlock("indexer", "chain", 91)
+	require.NoError(t, err)
+	require.Equal(t, "hash-91", block.Hash)
+
+	missing, err := db.GetMissingBlocks("indexer", "chain", 1, 101)
+	require.NoError(t, err)
+	require.Equal(t, []types.Height{101}, missing)
+}
+
+func TestDatabase_ConcurrentBlockTxs(t *testing.T) {
+	cfg := inmemory.DefaultConfig()
+	db := inmemory.NewDatabase(&cfg)
+
+	var wg sync.WaitGroup
+	for height := types.Height(1); height <= 100; height++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := db.BeginBlockTx(context.Background())
+			require.NoError(t, err)
+			require.NoError(t, tx.SaveModuleIndexedBlock("indexer", "chain", "module", height))
+			require.NoError(t, tx.SaveIndexedBlock("indexer", "chain", database.NewIndexedBlock(height, "", "", time.Now())))
+			require.NoError(t, tx.Commit())
+		}()
+	}
+	wg.Wait()
+
+	missing, err := db.GetMissingBlocks("indexer", "chain", 1, 100)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	missing, err = db.GetModuleMissingBlocks("indexer", "chain", "module", 1, 100)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func TestDatabase_BlockTxClosed(t *testing.T) {
+	cfg := inmemory.DefaultConfig()
+	db := inmemory.NewDatabase(&cfg)
+
+	tx, err := db.BeginBlockTx(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.SaveIndexedBlock("indexer", "chain", database.IndexedBlock{Height: 1}))
+	require.NoError(t, tx.Commit())
+	require.NoError(t, tx.Rollback())
+	require.Error(t, tx.Commit())
+	require.Error(t, tx.SaveIndexedBlock("indexer", "chain", database.IndexedBlock{Height: 2}))
+
+	lowest, err := db.GetLowestBlock("indexer", "chain")
+	require.NoError(t, err)
+	require.Equal(t, types.Height(1), *lowest)
+}
+
+func TestDatabase_SnapshotRestore(t *testing.T) {
+	cfg := inmemory.DefaultConfig()
+	db := inmemory.NewDatabase(&cfg)
+
+	timestamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, height := range []types.Height{1, 2, 3, 5} {
+		tx, err := db.BeginBlockTx(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, tx.SaveIndexedBlock("indexer", "chain", database.NewIndexedBlock(height, "hash", "parent", timestamp)))
+		require.NoError(t, tx.SaveModuleIndexedBlock("indexer", "chain", "module", height))
+		require.NoError(t, tx.Commit())
+	}
+	require.NoError(t, db.SaveFailedBlock("indexer", "chain", database.NewFailedBlock(4, "module", "error", 3, timestamp)))
+
+	snapshot := db.Snapshot()
+	require.Equal(t, []database.HeightRange{{From: 1, To: 3}, {From: 5, To: 5}}, snapshot.Chains[0].Heights)
+
+	// Changes performed after the snapshot must not affect it
+	require.NoError(t, db.DeleteIndexedBlocks("indexer", "chain", 2))
+	require.Equal(t, []database.HeightRange{{From: 1, To: 3}, {From: 5, To: 5}}, snapshot.Chains[0].Heights)
+
+	// Restore the snapshot serialized as JSON inside a new database
+	raw, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	var decoded inmemory.Snapshot
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+
+	restored := inmemory.NewDatabase(&cfg)
+	require.NoError(t, restored.Restore(&decoded))
+	require.Equal(t, snapshot, restored.Snapshot())
+
+	missing, err := restored.GetModuleMissingBlocks("indexer", "chain", "module", 1, 5)
+	require.NoError(t, err)
+	require.Equal(t, []types.Height{4}, missing)
+
+	block, err := restored.GetIndexedBlock("indexer", "chain", 3)
+	require.NoError(t, err)
+	require.Equal(t, database.NewIndexedBlock(3, "hash", "parent", timestamp), *block)
+
+	failed, err := restored.GetFailedBlocks("indexer", "chain")
+	require.NoError(t, err)
+	require.Equal(t, []database.FailedBlock{database.NewFailedBlock(4, "module", "error", 3, timestamp)}, failed)
+
+	// Restore rejects invalid ranges
+	decoded.Chains[0].Heights = []database.HeightRange{{From: 5, To: 1}}
+	require.Error(t, restored.Restore(&decoded))
+}
@@ -0,0 +1,120 @@
+package inmemory
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// heightRanges represents a set of heights stored as sorted, non-overlapping
+// and non-adjacent ranges, so that the memory used doesn't depend on the number
+// of consecutive heights.
+type heightRanges []database.HeightRange
+
+// search returns the index of the first range that ends at or after the provided height.
+func (r heightRanges) search(height types.Height) int {
+	return sort.Search(len(r), func(i int) bool {
+		return r[i].To >= height
+	})
+}
+
+// contains tells if the provided height is part of the set.
+func (r heightRanges) contains(height types.Height) bool {
+	index := r.search(height)
+	return index < len(r) && r[index].From <= height
+}
+
+// lowest returns the lowest height of the set, false if the set is empty.
+func (r heightRanges) lowest() (types.Height, bool) {
+	if len(r) == 0 {
+		return 0, false
+	}
+	return r[0].From, true
+}
+
+// add adds the provided height to the set, merging the ranges that become adjacent.
+func (r heightRanges) add(height types.Height) heightRanges {
+	index := r.search(height)
+	if index < len(r) && r[index].From <= height {
+		return r
+	}
+
+	extendsPrev := index > 0 && r[index-1].To == height-1
+	extendsNext := index < len(r) && height < types.MaxHeight && r[index].From == height+1
+	switch {
+	case extendsPrev && extendsNext:
+		r[index-1].To = r[index].To
+		return slices.Delete(r, index, index+1)
+	case extendsPrev:
+		r[index-1].To = height
+		return r
+	case extendsNext:
+		r[index].From = height
+		return r
+	default:
+		return slices.Insert(r, index, database.NewHeightRange(height, height))
+	}
+}
+
+// addRange adds all the heights of the provided range to the set,
+// merging the ranges that overlap or become adjacent.
+func (r heightRanges) addRange(heightRange database.HeightRange) heightRanges {
+	// Index of the first range that overlaps or is adjacent to the new one
+	start := 0
+	if heightRange.From > 0 {
+		start = r.search(heightRange.From - 1)
+	}
+
+	// Index after the last range that overlaps or is adjacent to the new one
+	end := start
+	for end < len(r) && (heightRange.To == types.MaxHeight || r[end].From <= heightRange.To+1) {
+		end++
+	}
+
+	if start < end {
+		heightRange.From = min(heightRange.From, r[start].From)
+		heightRange.To = max(heightRange.To, r[end-1].To)
+	}
+	return slices.Replace(r, start, end, heightRange)
+}
+
+// removeFrom removes from the set all the heights greater or equal to the provided one.
+func (r heightRanges) removeFrom(height types.Height) heightRanges {
+	index := r.search(height)
+	if index == len(r) {
+		return r
+	}
+
+	if r[index].From < height {
+		r[index].To = height - 1
+		index++
+	}
+	return r[:index]
+}
+
+// missing returns the ranges of heights in the [from, to] range that are not part of the set.
+func (r heightRanges) missing(from types.Height, to types.Height) []database.HeightRange {
+	var result []database.HeightRange
+	next := from
+	for _, heightRange := range r[r.search(from):] {
+		if heightRange.From > to {
+			break
+		}
+		if heightRange.From > next {
+			result = append(result, database.NewHeightRange(next, heightRange.From-1))
+		}
+		if heightRange.To >= to {
+			return result
+		}
+		next = heightRange.To + 1
+	}
+
+	return append(result, database.NewHeightRange(next, to))
+}
+
+// clone returns a copy of the set.
+func (r heightRanges) clone() heightRanges {
+	return slices.Clone(r)
+}
@@ -0,0 +1,93 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestHeightRanges_Add(t *testing.T) {
+	var ranges heightRanges
+	for _, height := range []types.Height{5, 1, 3, 2, 10, 4, 9, 5} {
+		ranges = ranges.add(height)
+	}
+
+	require.Equal(t, heightRanges{{From: 1, To: 5}, {From: 9, To: 10}}, ranges)
+	require.True(t, ranges.contains(1))
+	require.True(t, ranges.contains(10))
+	require.False(t, ranges.contains(0))
+	require.False(t, ranges.contains(6))
+	require.False(t, ranges.contains(11))
+
+	lowest, found := ranges.lowest()
+	require.True(t, found)
+	require.Equal(t, types.Height(1), lowest)
+
+	ranges = ranges.add(types.MaxHeight).add(0)
+	require.Equal(t, heightRanges{{From: 0, To: 5}, {From: 9, To: 10}, {From: types.MaxHeight, To: types.MaxHeight}}, ranges)
+}
+
+func TestHeightRanges_AddRange(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ranges   heightRanges
+		toAdd    database.HeightRange
+		expected heightRanges
+	}{
+		{
+			name:     "add to empty set",
+			toAdd:    database.HeightRange{From: 1, To: 3},
+			expected: heightRanges{{From: 1, To: 3}},
+		},
+		{
+			name:     "add between ranges",
+			ranges:   heightRanges{{From: 1, To: 3}, {From: 10, To: 12}},
+			toAdd:    database.HeightRange{From: 6, To: 7},
+			expected: heightRanges{{From: 1, To: 3}, {From: 6, To: 7}, {From: 10, To: 12}},
+		},
+		{
+			name:     "merge adjacent ranges",
+			ranges:   heightRanges{{From: 1, To: 3}, {From: 10, To: 12}},
+			toAdd:    database.HeightRange{From: 4, To: 9},
+			expected: heightRanges{{From: 1, To: 12}},
+		},
+		{
+			name:     "merge overlapping ranges",
+			ranges:   heightRanges{{From: 1, To: 3}, {From: 5, To: 6}, {From: 10, To: 12}},
+			toAdd:    database.HeightRange{From: 0, To: 11},
+			expected: heightRanges{{From: 0, To: 12}},
+		},
+		{
+			name:     "add range up to max height",
+			ranges:   heightRanges{{From: 1, To: 3}, {From: 10, To: 12}},
+			toAdd:    database.HeightRange{From: 11, To: types.MaxHeight},
+			expected: heightRanges{{From: 1, To: 3}, {From: 10, To: types.MaxHeight}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.ranges.addRange(tc.toAdd))
+		})
+	}
+}
+
+func TestHeightRanges_RemoveFrom(t *testing.T) {
+	ranges := heightRanges{{From: 1, To: 5}, {From: 9, To: 10}}
+	require.Equal(t, heightRanges{{From: 1, To: 5}, {From: 9, To: 10}}, ranges.clone().removeFrom(11))
+	require.Equal(t, heightRanges{{From: 1, To: 5}, {From: 9, To: 9}}, ranges.clone().removeFrom(10))
+	require.Equal(t, heightRanges{{From: 1, To: 5}}, ranges.clone().removeFrom(7))
+	require.Equal(t, heightRanges{{From: 1, To: 2}}, ranges.clone().removeFrom(3))
+	require.Empty(t, ranges.clone().removeFrom(0))
+}
+
+func TestHeightRanges_Missing(t *testing.T) {
+	ranges := heightRanges{{From: 3, To: 5}, {From: 8, To: 8}}
+	require.Equal(t, []database.HeightRange{{From: 1, To: 2}, {From: 6, To: 7}, {From: 9, To: 10}}, ranges.missing(1, 10))
+	require.Equal(t, []database.HeightRange{{From: 6, To: 7}}, ranges.missing(4, 8))
+	require.Nil(t, ranges.missing(3, 5))
+	require.Equal(t, []database.HeightRange{{From: 6, To: 7}, {From: 9, To: types.MaxHeight}}, ranges.missing(4, types.MaxHeight))
+}
@@ -0,0 +1,126 @@
+package inmemory
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/milkyway-labs/flux/database"
+)
+
+// Snapshot represents a copy of the data stored inside a Database.
+// It can be serialized to JSON and used to restore the data later,
+// e.g. to persist the state of an ephemeral indexer across restarts.
+type Snapshot struct {
+	Chains []ChainSnapshot `json:"chains"`
+}
+
+// ChainSnapshot contains the data stored by an indexer for a chain.
+type ChainSnapshot struct {
+	Indexer string `json:"indexer"`
+	ChainID string `json:"chain_id"`
+	// Heights contains the ranges of the indexed blocks.
+	Heights []database.HeightRange `json:"heights"`
+	// Blocks contains the details of the most recent indexed blocks.
+	Blocks []database.IndexedBlock `json:"blocks"`
+	// Modules contains the ranges of the blocks processed by each module.
+	Modules      map[string][]database.HeightRange `json:"modules"`
+	FailedBlocks []database.FailedBlock            `json:"failed_blocks"`
+}
+
+// Snapshot returns a copy of all the data stored inside the database.
+// The returned snapshot doesn't share any memory with the database, so it's
+// not affected by the writes performed after this call.
+func (db *Database) Snapshot() *Snapshot {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	snapshot := &Snapshot{
+		Chains: make([]ChainSnapshot, 0, len(db.chains)),
+	}
+	for key, chain := range db.chains {
+		modules := make(map[string][]database.HeightRange, len(chain.modules))
+		for module, heights := range chain.modules {
+			modules[module] = heights.clone()
+		}
+
+		blocks := slices.Collect(maps.Values(chain.blocks))
+		slices.SortFunc(blocks, func(a, b database.IndexedBlock) int {
+			return cmp.Compare(a.Height, b.Height)
+		})
+		failedBlocks := slices.Collect(maps.Values(chain.failed))
+		slices.SortFunc(failedBlocks, compareFailedBlocks)
+
+		snapshot.Chains = append(snapshot.Chains, ChainSnapshot{
+			Indexer:      key.indexer,
+			ChainID:      key.chainID,
+			Heights:      chain.heights.clone(),
+			Blocks:       blocks,
+			Modules:      modules,
+			FailedBlocks: failedBlocks,
+		})
+	}
+
+	// Sort the chains so that the same data always produce the same snapshot
+	slices.SortFunc(snapshot.Chains, func(a, b ChainSnapshot) int {
+		if c := cmp.Compare(a.Indexer, b.Indexer); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ChainID, b.ChainID)
+	})
+
+	return snapshot
+}
+
+// Restore replaces all the data stored inside the database with
+// the ones contained in the provided snapshot.
+func (db *Database) Restore(snapshot *Snapshot) error {
+	chains := make(map[chainKey]*chainState, len(snapshot.Chains))
+	for _, chainSnapshot := range snapshot.Chains {
+		chain := newChainState()
+
+		heights, err := toHeightRanges(chainSnapshot.Heights)
+		if err != nil {
+			return fmt.Errorf("invalid heights of %s on chain %s: %w", chainSnapshot.Indexer, chainSnapshot.ChainID, err)
+		}
+		chain.heights = heights
+
+		for module, moduleHeights := range chainSnapshot.Modules {
+			chain.modules[module], err = toHeightRanges(moduleHeights)
+			if err != nil {
+				return fmt.Errorf("invalid heights of module %s on chain %s: %w", module, chainSnapshot.ChainID, err)
+			}
+		}
+		for _, block := range chainSnapshot.Blocks {
+			chain.blocks[block.Height] = block
+		}
+		for _, block := range chainSnapshot.FailedBlocks {
+			chain.failed[failedBlockKey{height: block.Height, module: block.Module}] = block
+		}
+		if len(chain.heights) > 0 {
+			chain.highest = chain.heights[len(chain.heights)-1].To
+		}
+
+		chains[chainKey{indexer: chainSnapshot.Indexer, chainID: chainSnapshot.ChainID}] = chain
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.chains = chains
+
+	return nil
+}
+
+// toHeightRanges builds a set containing all the heights of the provided ranges,
+// which can be unsorted and overlapping.
+func toHeightRanges(ranges []database.HeightRange) (heightRanges, error) {
+	var result heightRanges
+	for _, heightRange := range ranges {
+		if heightRange.From > heightRange.To {
+			return nil, fmt.Errorf("invalid range, from(%d) must not be greater than to(%d)", heightRange.From, heightRange.To)
+		}
+		result = result.addRange(heightRange)
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package inmemory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// type check to ensure interface is properly implemented
+var _ database.BlockTx = &BlockTx{}
+
+// BlockTx implements database.BlockTx buffering the writes until the
+// transaction is committed, then applying all of them while holding the
+// database lock so that the other readers never observe a partial block.
+type BlockTx struct {
+	db *Database
+
+	mu     sync.Mutex
+	ops    []func()
+	closed bool
+}
+
+func newBlockTx(db *Database) *BlockTx {
+	return &BlockTx{
+		db: db,
+	}
+}
+
+// addOp buffers the provided operation, it will be executed on Commit.
+func (tx *BlockTx) addOp(op func()) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.closed {
+		return fmt.Errorf("transaction has already been committed or rolled back")
+	}
+	tx.ops = append(tx.ops, op)
+	return nil
+}
+
+// SaveIndexedBlock implements database.BlockTx.
+func (tx *BlockTx) SaveIndexedBlock(indexer string, chainID string, block database.IndexedBlock) error {
+	return tx.addOp(func() {
+		tx.db.saveIndexedBlock(indexer, chainID, block)
+	})
+}
+
+// SaveModuleIndexedBlock implements database.BlockTx.
+func (tx *BlockTx) SaveModuleIndexedBlock(indexer string, chainID string, module string, height types.Height) error {
+	return tx.addOp(func() {
+		tx.db.saveModuleIndexedBlock(indexer, chainID, module, height)
+	})
+}
+
+// DeleteIndexedBlocks implements database.BlockTx.
+func (tx *BlockTx) DeleteIndexedBlocks(indexer string, chainID string, from types.Height) error {
+	return tx.addOp(func() {
+		tx.db.deleteIndexedBlocks(indexer, chainID, from)
+	})
+}
+
+// DeleteFailedBlocks implements database.BlockTx.
+func (tx *BlockTx) DeleteFailedBlocks(indexer string, chainID string, height types.Height) error {
+	return tx.addOp(func() {
+		tx.db.deleteFailedBlocks(indexer, chainID, height)
+	})
+}
+
+// DeleteModuleFailedBlock implements database.BlockTx.
+func (tx *BlockTx) DeleteModuleFailedBlock(indexer string, chainID string, module string, height types.Height) error {
+	return tx.addOp(func() {
+		tx.db.deleteModuleFailedBlock(indexer, chainID, module, height)
+	})
+}
+
+// Commit implements database.BlockTx.
+func (tx *BlockTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.closed {
+		return fmt.Errorf("transaction has already been committed or rolled back")
+	}
+	tx.closed = true
+
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	for _, op := range tx.ops {
+		op()
+	}
+	tx.ops = nil
+
+	return nil
+}
+
+// Rollback implements database.BlockTx.
+func (tx *BlockTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	tx.closed = true
+	tx.ops = nil
+	return nil
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// MigrationStatus represents the state of a schema migration.
+type MigrationStatus struct {
+	// Source identifies who defined the migration, e.g. the database
+	// itself or the module that registered it.
+	Source  string
+	Version uint64
+	Name    string
+	// AppliedAt contains the time at which the migration has been applied,
+	// nil if the migration is pending.
+	AppliedAt *time.Time
+}
+
+// IsApplied tells if the migration has been applied to the database.
+func (s MigrationStatus) IsApplied() bool {
+	return s.AppliedAt != nil
+}
+
+// Migrator represents a Database whose schema is managed through versioned migrations.
+type Migrator interface {
+	// GetMigrationsStatus returns the status of all the known migrations,
+	// sorted in the order in which they are applied.
+	GetMigrationsStatus(ctx context.Context) ([]MigrationStatus, error)
+	// MigrateUp applies all the pending migrations and returns the applied ones.
+	MigrateUp(ctx context.Context) ([]MigrationStatus, error)
+	// MigrateDown reverts the provided number of migrations, starting from
+	// the last applied one, and returns the reverted ones.
+	MigrateDown(ctx context.Context, steps uint) ([]MigrationStatus, error)
+	// MigrateOnStart is called before the indexers start, it applies the pending
+	// migrations or returns an error if there are pending migrations that
+	// should not be applied automatically.
+	MigrateOnStart(ctx context.Context) error
+}
+
+// MigrateOnStart calls MigrateOnStart on the provided database if it implements
+// the Migrator interface, so that its schema is up to date before the indexer
+// that uses it is started.
+func MigrateOnStart(ctx context.Context, db Database) error {
+	migrator, ok := db.(Migrator)
+	if !ok {
+		return nil
+	}
+
+	return migrator.MigrateOnStart(ctx)
+}
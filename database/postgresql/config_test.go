@@ -0,0 +1,31 @@
+package postgresql_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database/postgresql"
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestConfig_GetPartitionRange(t *testing.T) {
+	cfg := postgresql.DefaultConfig().WithPartitionSize(1000)
+
+	testCases := []struct {
+		height       types.Height
+		expectedFrom int64
+		expectedTo   int64
+	}{
+		{height: 0, expectedFrom: 0, expectedTo: 1000},
+		{height: 999, expectedFrom: 0, expectedTo: 1000},
+		{height: 1000, expectedFrom: 1000, expectedTo: 2000},
+		{height: 123_456, expectedFrom: 123_000, expectedTo: 124_000},
+	}
+
+	for _, tc := range testCases {
+		from, to := cfg.GetPartitionRange(tc.height)
+		require.Equal(t, tc.expectedFrom, from, tc.height)
+		require.Equal(t, tc.expectedTo, to, tc.height)
+	}
+}
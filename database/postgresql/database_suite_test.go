@@ -1,17 +1,19 @@
 package postgresql_test
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/milkyway-labs/flux/database"
 	"github.com/milkyway-labs/flux/database/postgresql"
 	dbsuite "github.com/milkyway-labs/flux/database/suite"
+	"github.com/milkyway-labs/flux/types"
 )
 
 func TestDatabaseTestSuite(t *testing.T) {
@@ -43,22 +45,10 @@ func (suite *DbTestSuite) SetupTest() {
 	_, err = parserDb.SQL.Exec(`CREATE SCHEMA public;`)
 	suite.Require().NoError(err)
 
-	dirPath := "schema"
-	dir, err := os.ReadDir(dirPath)
+	// Create the tables
+	_, err = parserDb.MigrateUp(context.Background())
 	suite.Require().NoError(err)
 
-	for _, fileInfo := range dir {
-		if !strings.HasSuffix(fileInfo.Name(), ".sql") {
-			continue
-		}
-
-		file, err := os.ReadFile(filepath.Join(dirPath, fileInfo.Name()))
-		suite.Require().NoError(err)
-
-		_, err = parserDb.SQL.Exec(string(file))
-		suite.Require().NoError(err)
-	}
-
 	// Create the truncate function
 	stmt := fmt.Sprintf(`
 CREATE OR REPLACE FUNCTION truncate_tables(username IN VARCHAR) RETURNS void AS $$
@@ -85,3 +75,175 @@ $$ LANGUAGE plpgsql;`, "public")
 	suite.database = parserDb
 	suite.InitDB(parserDb)
 }
+
+func (suite *DbTestSuite) TestMigrations() {
+	ctx := context.Background()
+	err := suite.database.RegisterMigrations("module", []postgresql.Migration{
+		{Version: 1, Name: "create_table", Up: `CREATE TABLE module_data (height BIGINT)`, Down: `DROP TABLE module_data`},
+		{Version: 2, Name: "add_column", Up: `ALTER TABLE module_data ADD COLUMN hash TEXT`},
+	})
+	suite.Require().NoError(err)
+
+	// The core migrations have been applied while setting up the test
+	statuses, err := suite.database.GetMigrationsStatus(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(statuses, 4)
+	suite.Require().True(statuses[0].IsApplied())
+	suite.Require().True(statuses[1].IsApplied())
+	suite.Require().False(statuses[2].IsApplied())
+	suite.Require().False(statuses[3].IsApplied())
+
+	// The indexers refuse to start with pending migrations if auto_migrate is disabled
+	suite.database.Cfg.AutoMigrate = false
+	suite.Require().Error(suite.database.MigrateOnStart(ctx))
+
+	applied, err := suite.database.MigrateUp(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(applied, 2)
+	suite.Require().NoError(suite.database.MigrateOnStart(ctx))
+
+	// Applying again is a no-op
+	applied, err = suite.database.MigrateUp(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Empty(applied)
+
+	// The last migration doesn't define a down statement
+	_, err = suite.database.MigrateDown(ctx, 1)
+	suite.Require().Error(err)
+
+	_, err = suite.database.SQL.Exec(`DELETE FROM schema_migrations WHERE source = 'module' AND version = 2`)
+	suite.Require().NoError(err)
+	reverted, err := suite.database.MigrateDown(ctx, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(reverted, 1)
+	suite.Require().Equal("create_table", reverted[0].Name)
+
+	_, err = suite.database.SQL.Exec(`SELECT * FROM module_data`)
+	suite.Require().Error(err)
+}
+
+func (suite *DbTestSuite) TestMigrationsFromLegacySchema() {
+	ctx := context.Background()
+
+	// Reset the schema and create the blocks table as defined by the previous schema.sql file
+	_, err := suite.database.SQL.Exec(`DROP SCHEMA public CASCADE; CREATE SCHEMA public;`)
+	suite.Require().NoError(err)
+	_, err = suite.database.SQL.Exec(`
+CREATE TABLE blocks
+(
+    indexer     TEXT NOT NULL,
+    chain_id    TEXT NOT NULL,
+    height      BIGINT,
+    timestamp   TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    CONSTRAINT unique_chain_block UNIQUE (indexer, chain_id, height)
+);
+INSERT INTO blocks (indexer, chain_id, height, timestamp) VALUES ('indexer', 'chain', 1, NOW());`)
+	suite.Require().NoError(err)
+
+	applied, err := suite.database.MigrateUp(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(applied, 2)
+
+	// The blocks indexed before the migrations are preserved without hashes
+	block, err := suite.database.GetIndexedBlock("indexer", "chain", 1)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(block)
+	suite.Require().Empty(block.Hash)
+	suite.Require().Empty(block.ParentHash)
+
+	// The new blocks are stored along with their hashes
+	suite.Require().NoError(suite.database.SaveIndexedBlock("indexer", "chain", database.NewIndexedBlock(2, "hash", "parent", time.Now())))
+	block, err = suite.database.GetIndexedBlock("indexer", "chain", 2)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(block)
+	suite.Require().Equal("hash", block.Hash)
+	suite.Require().Equal("parent", block.ParentHash)
+}
+
+func (suite *DbTestSuite) TestBlocksPartitions() {
+	partitionSize := suite.database.Cfg.GetPartitionSize()
+	heights := []types.Height{1, types.Height(partitionSize), types.Height(partitionSize) + 1, types.Height(3 * partitionSize)}
+
+	for i, height := range heights {
+		block := database.NewIndexedBlock(height, "hash", "parent", time.Now())
+		if i%2 == 0 {
+			suite.Require().NoError(suite.database.SaveIndexedBlock("indexer", "chain", block))
+			continue
+		}
+
+		tx, err := suite.database.BeginBlockTx(context.Background())
+		suite.Require().NoError(err)
+		suite.Require().NoError(tx.SaveIndexedBlock("indexer", "chain", block))
+		suite.Require().NoError(tx.Commit())
+	}
+
+	var partitions []string
+	err := suite.database.SQL.Select(&partitions, `
+SELECT child.relname
+FROM pg_inherits
+JOIN pg_class parent ON pg_inherits.inhparent = parent.oid
+JOIN pg_class child ON pg_inherits.inhrelid = child.oid
+WHERE parent.relname = 'blocks'
+ORDER BY child.relname`)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{
+		"blocks_0",
+		fmt.Sprintf("blocks_%d", partitionSize),
+		fmt.Sprintf("blocks_%d", 3*partitionSize),
+	}, partitions)
+
+	missing, err := suite.database.GetMissingBlocks("indexer", "chain", 1, types.Height(partitionSize)+1)
+	suite.Require().NoError(err)
+	suite.Require().Len(missing, int(partitionSize)-1)
+}
+
+func (suite *DbTestSuite) TestPrepareHeight() {
+	partitionSize := types.Height(suite.database.Cfg.GetPartitionSize())
+	suite.Require().NoError(suite.database.PrepareHeight(2*partitionSize + 1))
+
+	// The partition is visible to other connections before any block transaction begins
+	var exists bool
+	err := suite.database.SQL.Get(&exists, `SELECT EXISTS (SELECT 1 FROM pg_class WHERE relname = $1)`,
+		fmt.Sprintf("blocks_%d", 2*partitionSize))
+	suite.Require().NoError(err)
+	suite.Require().True(exists)
+}
+
+func (suite *DbTestSuite) TestBlockTxConcurrentPartitions() {
+	partitionSize := types.Height(suite.database.Cfg.GetPartitionSize())
+	tx, err := suite.database.BeginBlockTx(context.Background())
+	suite.Require().NoError(err)
+	blockTx := tx.(*postgresql.BlockTx)
+
+	// Simulate two modules that don't depend on each other creating
+	// the same partitions while sharing the block transaction
+	wg := sync.WaitGroup{}
+	errs := make([]error, 2)
+	for module := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, height := range []types.Height{1, partitionSize + 1, 2*partitionSize + 1} {
+				if err := blockTx.EnsureHeightPartition("blocks", height); err != nil {
+					errs[module] = err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	suite.Require().NoError(errs[0])
+	suite.Require().NoError(errs[1])
+	suite.Require().NoError(tx.Commit())
+
+	var partitions []string
+	err = suite.database.SQL.Select(&partitions, `
+SELECT child.relname
+FROM pg_inherits
+JOIN pg_class parent ON pg_inherits.inhparent = parent.oid
+JOIN pg_class child ON pg_inherits.inhrelid = child.oid
+WHERE parent.relname = 'blocks'
+ORDER BY child.relname`)
+	suite.Require().NoError(err)
+	suite.Require().Len(partitions, 3)
+}
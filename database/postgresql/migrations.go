@@ -0,0 +1,477 @@
+package postgresql
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/milkyway-labs/flux/database"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// CoreMigrationsSource is the source of the migrations that create
+// the tables used by the indexer to store its state.
+const CoreMigrationsSource = "flux"
+
+// migrationsLockID is the ID of the advisory lock acquired while applying or
+// reverting a migration, so that multiple processes sharing the same
+// database don't run the migrations concurrently.
+const migrationsLockID = 7_406_283_115_937_519
+
+const (
+	upMigrationSuffix   = ".up.sql"
+	downMigrationSuffix = ".down.sql"
+)
+
+// type check to ensure interface is properly implemented
+var _ database.Migrator = &Database{}
+
+// Migration represents a versioned change to the database schema.
+type Migration struct {
+	// Version identifies the migration, the migrations of the same
+	// source are applied in ascending version order.
+	Version uint64
+	Name    string
+	// Up contains the SQL statements that apply the migration.
+	Up string
+	// Down contains the SQL statements that revert the migration,
+	// empty if the migration can't be reverted.
+	Down string
+}
+
+// migrationSource represents a set of migrations registered by the same source.
+type migrationSource struct {
+	name       string
+	migrations []Migration
+}
+
+type MigrationRow struct {
+	ID        int64     `db:"id"`
+	Source    string    `db:"source"`
+	Version   uint64    `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+func (r MigrationRow) toStatus() database.MigrationStatus {
+	appliedAt := r.AppliedAt
+	return database.MigrationStatus{
+		Source:    r.Source,
+		Version:   r.Version,
+		Name:      r.Name,
+		AppliedAt: &appliedAt,
+	}
+}
+
+// LoadMigrations reads the migrations stored inside the provided directory of fsys.
+// Each migration is defined by a <version>_<name>.up.sql file containing the statements
+// that apply it and by an optional <version>_<name>.down.sql file containing the
+// statements that revert it. The returned migrations are sorted by version.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	migrations := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, ".sql") {
+			continue
+		}
+
+		baseName, isUp := strings.CutSuffix(fileName, upMigrationSuffix)
+		if !isUp {
+			var isDown bool
+			baseName, isDown = strings.CutSuffix(fileName, downMigrationSuffix)
+			if !isDown {
+				return nil, fmt.Errorf("invalid migration file %s, must end with %s or %s",
+					fileName, upMigrationSuffix, downMigrationSuffix)
+			}
+		}
+
+		rawVersion, name, _ := strings.Cut(baseName, "_")
+		version, err := strconv.ParseUint(rawVersion, 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration file %s, must start with a version greater than 0", fileName)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("read migration file %s: %w", fileName, err)
+		}
+
+		migration, found := migrations[version]
+		if !found {
+			migration = &Migration{Version: version, Name: name}
+			migrations[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, migration.Name, name)
+		}
+
+		if isUp {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if strings.TrimSpace(migration.Up) == "" {
+			return nil, fmt.Errorf("migration %d_%s doesn't have an up file", migration.Version, migration.Name)
+		}
+		result = append(result, *migration)
+	}
+	slices.SortFunc(result, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return result, nil
+}
+
+// RegisterMigrations registers the migrations defined by the provided source,
+// usually the name of the module that owns the tables created by them.
+// The migrations of each source are applied after the ones of the sources
+// registered before it, so modules should register their migrations
+// inside their builder.
+func (db *Database) RegisterMigrations(source string, migrations []Migration) error {
+	if strings.TrimSpace(source) == "" {
+		return fmt.Errorf("migrations source can't be empty")
+	}
+
+	versions := make(map[uint64]bool, len(migrations))
+	for _, migration := range migrations {
+		if migration.Version == 0 {
+			return fmt.Errorf("migration %s of %s must have a version greater than 0", migration.Name, source)
+		}
+		if strings.TrimSpace(migration.Up) == "" {
+			return fmt.Errorf("migration %d of %s must have an up statement", migration.Version, source)
+		}
+		if versions[migration.Version] {
+			return fmt.Errorf("duplicated migration %d for %s", migration.Version, source)
+		}
+		versions[migration.Version] = true
+	}
+
+	db.migrationsMu.Lock()
+	defer db.migrationsMu.Unlock()
+
+	for _, registered := range db.migrationSources {
+		if registered.name == source {
+			return fmt.Errorf("migrations of %s already registered", source)
+		}
+	}
+
+	sorted := slices.Clone(migrations)
+	slices.SortFunc(sorted, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	db.migrationSources = append(db.migrationSources, migrationSource{name: source, migrations: sorted})
+
+	return nil
+}
+
+// getMigrationSources returns a copy of the registered migration sources.
+func (db *Database) getMigrationSources() []migrationSource {
+	db.migrationsMu.Lock()
+	defer db.migrationsMu.Unlock()
+
+	return slices.Clone(db.migrationSources)
+}
+
+// getMigration returns the registered migration with the provided source and version.
+func (db *Database) getMigration(source string, version uint64) (Migration, bool) {
+	for _, migrationSource := range db.getMigrationSources() {
+		if migrationSource.name != source {
+			continue
+		}
+		for _, migration := range migrationSource.migrations {
+			if migration.Version == version {
+				return migration, true
+			}
+		}
+	}
+	return Migration{}, false
+}
+
+// GetMigrationsStatus implements database.Migrator.
+// The migrations that have been applied but are no longer registered
+// are returned after the registered ones.
+func (db *Database) GetMigrationsStatus(ctx context.Context) ([]database.MigrationStatus, error) {
+	if err := db.createMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var rows []MigrationRow
+	err := db.SQL.SelectContext(ctx, &rows, `SELECT * FROM schema_migrations ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("get applied migrations: %w", err)
+	}
+
+	type migrationKey struct {
+		source  string
+		version uint64
+	}
+	applied := make(map[migrationKey]MigrationRow, len(rows))
+	for _, row := range rows {
+		applied[migrationKey{source: row.Source, version: row.Version}] = row
+	}
+
+	var result []database.MigrationStatus
+	for _, source := range db.getMigrationSources() {
+		for _, migration := range source.migrations {
+			key := migrationKey{source: source.name, version: migration.Version}
+			row, found := applied[key]
+			if !found {
+				result = append(result, database.MigrationStatus{
+					Source:  source.name,
+					Version: migration.Version,
+					Name:    migration.Name,
+				})
+				continue
+			}
+
+			result = append(result, row.toStatus())
+			delete(applied, key)
+		}
+	}
+
+	for _, row := range rows {
+		if _, unknown := applied[migrationKey{source: row.Source, version: row.Version}]; unknown {
+			result = append(result, row.toStatus())
+		}
+	}
+
+	return result, nil
+}
+
+// MigrateUp implements database.Migrator.
+func (db *Database) MigrateUp(ctx context.Context) ([]database.MigrationStatus, error) {
+	if err := db.createMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var applied []database.MigrationStatus
+	for _, source := range db.getMigrationSources() {
+		for _, migration := range source.migrations {
+			status, err := db.applyMigration(ctx, source.name, migration)
+			if err != nil {
+				return applied, fmt.Errorf("apply migration %d_%s of %s: %w", migration.Version, migration.Name, source.name, err)
+			}
+			if status != nil {
+				db.Logger.Info().Str("source", source.name).Uint64("version", migration.Version).
+					Str("name", migration.Name).Msg("applied migration")
+				applied = append(applied, *status)
+			}
+		}
+	}
+
+	return applied, nil
+}
+
+// MigrateDown implements database.Migrator.
+// The migrations are reverted in the opposite order in which they have been applied.
+func (db *Database) MigrateDown(ctx context.Context, steps uint) ([]database.MigrationStatus, error) {
+	if err := db.createMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var reverted []database.MigrationStatus
+	for range steps {
+		status, err := db.revertLastMigration(ctx)
+		if err != nil {
+			return reverted, err
+		}
+		if status == nil {
+			// No more migrations to revert
+			break
+		}
+
+		db.Logger.Info().Str("source", status.Source).Uint64("version", status.Version).
+			Str("name", status.Name).Msg("reverted migration")
+		reverted = append(reverted, *status)
+	}
+
+	return reverted, nil
+}
+
+// MigrateOnStart implements database.Migrator.
+// The pending migrations are applied if the auto_migrate option is enabled,
+// otherwise an error is returned if there are pending migrations.
+func (db *Database) MigrateOnStart(ctx context.Context) error {
+	if db.Cfg.AutoMigrate {
+		_, err := db.MigrateUp(ctx)
+		return err
+	}
+
+	statuses, err := db.GetMigrationsStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if !status.IsApplied() {
+			pending = append(pending, fmt.Sprintf("%s/%d_%s", status.Source, status.Version, status.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("found pending migrations (%s), apply them with the migrate up command or enable auto_migrate",
+			strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// createMigrationsTable creates the table used to track the applied migrations if it doesn't exist.
+func (db *Database) createMigrationsTable(ctx context.Context) error {
+	stmt := `
+CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    -- Incremental ID used to revert the migrations in the opposite order in which they have been applied.
+    id          BIGSERIAL PRIMARY KEY,
+    -- Source that defined the migration, e.g. the module that registered it.
+    source      TEXT NOT NULL,
+    -- Version of the migration.
+    version     BIGINT NOT NULL,
+    -- Name of the migration.
+    name        TEXT NOT NULL,
+    -- Time at which the migration has been applied.
+    applied_at  TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    CONSTRAINT unique_migration UNIQUE (source, version)
+);
+`
+
+	return db.withMigrationsLock(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			return fmt.Errorf("create schema_migrations table: %w", err)
+		}
+		return nil
+	})
+}
+
+// applyMigration applies the provided migration if it has not been applied yet.
+// Returns nil if the migration was already applied.
+func (db *Database) applyMigration(ctx context.Context, source string, migration Migration) (*database.MigrationStatus, error) {
+	var status *database.MigrationStatus
+	err := db.withMigrationsLock(ctx, func(tx *sqlx.Tx) error {
+		var applied bool
+		err := tx.GetContext(ctx, &applied,
+			`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE source = $1 AND version = $2)`,
+			source, migration.Version)
+		if err != nil {
+			return err
+		}
+		if applied {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, migration.Up)
+		if err != nil {
+			return err
+		}
+
+		var row MigrationRow
+		err = tx.GetContext(ctx, &row, `
+INSERT INTO schema_migrations (source, version, name, applied_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *`, source, migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		appliedStatus := row.toStatus()
+		status = &appliedStatus
+		return nil
+	})
+
+	return status, err
+}
+
+// revertLastMigration reverts the last applied migration.
+// Returns nil if there are no applied migrations.
+func (db *Database) revertLastMigration(ctx context.Context) (*database.MigrationStatus, error) {
+	var status *database.MigrationStatus
+	err := db.withMigrationsLock(ctx, func(tx *sqlx.Tx) error {
+		var row MigrationRow
+		err := tx.GetContext(ctx, &row, `SELECT * FROM schema_migrations ORDER BY id DESC LIMIT 1`)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("get last applied migration: %w", err)
+		}
+
+		migration, found := db.getMigration(row.Source, row.Version)
+		if !found {
+			return fmt.Errorf("migration %d_%s of %s is not registered", row.Version, row.Name, row.Source)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			return fmt.Errorf("migration %d_%s of %s can't be reverted", row.Version, row.Name, row.Source)
+		}
+
+		_, err = tx.ExecContext(ctx, migration.Down)
+		if err != nil {
+			return fmt.Errorf("revert migration %d_%s of %s: %w", row.Version, row.Name, row.Source, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE id = $1`, row.ID)
+		if err != nil {
+			return err
+		}
+
+		revertedStatus := row.toStatus()
+		revertedStatus.AppliedAt = nil
+		status = &revertedStatus
+		return nil
+	})
+
+	return status, err
+}
+
+// withMigrationsLock executes the provided function inside a transaction
+// holding the migrations lock, the transaction is committed if the
+// function succeeds. Inside the transaction the partition size is available
+// through the flux.partition_size setting.
+func (db *Database) withMigrationsLock(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.SQL.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationsLockID)
+	if err != nil {
+		return fmt.Errorf("acquire migrations lock: %w", err)
+	}
+
+	// Expose the partition size to the migrations, so that they can
+	// partition the tables with the same scheme used by the driver
+	_, err = tx.ExecContext(ctx, `SELECT set_config($1, $2, true)`,
+		partitionSizeSetting, strconv.FormatInt(db.Cfg.GetPartitionSize(), 10))
+	if err != nil {
+		return fmt.Errorf("set partition size: %w", err)
+	}
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS failed_blocks;
+DROP TABLE IF EXISTS module_blocks;
+DROP TABLE IF EXISTS blocks;
@@ -0,0 +1,27 @@
+ALTER TABLE blocks RENAME TO blocks_partitioned;
+ALTER TABLE blocks_partitioned RENAME CONSTRAINT unique_chain_block TO unique_chain_block_partitioned;
+
+CREATE TABLE blocks
+(
+    -- Name of the indexer that has indexed the block.
+    indexer     TEXT NOT NULL,
+    -- ID of the chain from which the block has been fetched.
+    chain_id    TEXT NOT NULL,
+    -- Height of the indexed block.
+    height      BIGINT,
+    -- Hash of the indexed block.
+    hash        TEXT NOT NULL DEFAULT '',
+    -- Hash of the block that precedes the indexed one.
+    parent_hash TEXT NOT NULL DEFAULT '',
+    -- Time at which the indexed block has been produced by the chain.
+    timestamp   TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    CONSTRAINT unique_chain_block UNIQUE (indexer, chain_id, height)
+);
+
+INSERT INTO blocks (indexer, chain_id, height, hash, parent_hash, timestamp)
+SELECT indexer, chain_id, height, hash, parent_hash, timestamp
+FROM blocks_partitioned;
+
+-- Dropping the partitioned table drops also its partitions
+DROP TABLE blocks_partitioned;
+DROP FUNCTION IF EXISTS create_height_partition(TEXT, BIGINT, BIGINT);
@@ -0,0 +1,53 @@
+-- Creates the partition of the provided table that contains the given height, if it doesn't exist.
+-- The table must be partitioned by range on its height column, and each partition contains
+-- partition_size heights starting from a multiple of partition_size.
+CREATE OR REPLACE FUNCTION create_height_partition(table_name TEXT, height BIGINT, partition_size BIGINT) RETURNS TEXT AS $$
+DECLARE
+    partition_from BIGINT := height / partition_size * partition_size;
+    partition_name TEXT := format('%s_%s', table_name, partition_from);
+BEGIN
+    -- Serialize the creation of the same partition by concurrent transactions
+    PERFORM pg_advisory_xact_lock(hashtext(partition_name));
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%s) TO (%s)',
+        partition_name, table_name, partition_from, partition_from + partition_size
+    );
+    RETURN partition_name;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE blocks RENAME TO blocks_unpartitioned;
+ALTER TABLE blocks_unpartitioned RENAME CONSTRAINT unique_chain_block TO unique_chain_block_unpartitioned;
+
+CREATE TABLE blocks
+(
+    -- Name of the indexer that has indexed the block.
+    indexer     TEXT NOT NULL,
+    -- ID of the chain from which the block has been fetched.
+    chain_id    TEXT NOT NULL,
+    -- Height of the indexed block, used to partition the table.
+    height      BIGINT NOT NULL,
+    -- Hash of the indexed block.
+    hash        TEXT NOT NULL DEFAULT '',
+    -- Hash of the block that precedes the indexed one.
+    parent_hash TEXT NOT NULL DEFAULT '',
+    -- Time at which the indexed block has been produced by the chain.
+    timestamp   TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    CONSTRAINT unique_chain_block UNIQUE (indexer, chain_id, height)
+) PARTITION BY RANGE (height);
+
+-- Move the blocks indexed before the table was partitioned, the partition size
+-- is provided by the driver while applying the migrations.
+SELECT create_height_partition('blocks', partition_height, current_setting('flux.partition_size')::BIGINT)
+FROM (
+    SELECT DISTINCT height / current_setting('flux.partition_size')::BIGINT * current_setting('flux.partition_size')::BIGINT AS partition_height
+    FROM blocks_unpartitioned
+    WHERE height IS NOT NULL
+) AS partitions;
+
+INSERT INTO blocks (indexer, chain_id, height, hash, parent_hash, timestamp)
+SELECT indexer, chain_id, height, hash, parent_hash, timestamp
+FROM blocks_unpartitioned
+WHERE height IS NOT NULL;
+
+DROP TABLE blocks_unpartitioned;
@@ -0,0 +1,102 @@
+package postgresql_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database/postgresql"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	testCases := []struct {
+		name      string
+		files     fstest.MapFS
+		shouldErr bool
+		expected  []postgresql.Migration
+	}{
+		{
+			name: "valid migrations are sorted by version",
+			files: fstest.MapFS{
+				"migrations/0010_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+				"migrations/0002_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"migrations/0002_create_table.down.sql": {Data: []byte("DROP TABLE")},
+				"migrations/README.md":                  {Data: []byte("ignored")},
+			},
+			expected: []postgresql.Migration{
+				{Version: 2, Name: "create_table", Up: "CREATE TABLE", Down: "DROP TABLE"},
+				{Version: 10, Name: "add_index", Up: "CREATE INDEX"},
+			},
+		},
+		{
+			name: "missing up file returns error",
+			files: fstest.MapFS{
+				"migrations/0001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "invalid version returns error",
+			files: fstest.MapFS{
+				"migrations/first_create_table.up.sql": {Data: []byte("CREATE TABLE")},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "zero version returns error",
+			files: fstest.MapFS{
+				"migrations/0_create_table.up.sql": {Data: []byte("CREATE TABLE")},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "invalid suffix returns error",
+			files: fstest.MapFS{
+				"migrations/0001_create_table.sql": {Data: []byte("CREATE TABLE")},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "different names for the same version returns error",
+			files: fstest.MapFS{
+				"migrations/0001_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"migrations/0001_create_other.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			migrations, err := postgresql.LoadMigrations(tc.files, "migrations")
+			if tc.shouldErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, migrations)
+			}
+		})
+	}
+}
+
+func TestDatabase_RegisterMigrations(t *testing.T) {
+	cfg := postgresql.DefaultConfig()
+	db, err := postgresql.NewDatabase(zerolog.Nop(), &cfg)
+	require.NoError(t, err)
+
+	migrations := []postgresql.Migration{{Version: 1, Name: "create_table", Up: "CREATE TABLE"}}
+	require.NoError(t, db.RegisterMigrations("module", migrations))
+	// Sources can be registered only once
+	require.Error(t, db.RegisterMigrations("module", migrations))
+	require.Error(t, db.RegisterMigrations(postgresql.CoreMigrationsSource, migrations))
+
+	require.Error(t, db.RegisterMigrations("", migrations))
+	require.Error(t, db.RegisterMigrations("invalid", []postgresql.Migration{{Version: 0, Up: "CREATE TABLE"}}))
+	require.Error(t, db.RegisterMigrations("invalid", []postgresql.Migration{{Version: 1}}))
+	require.Error(t, db.RegisterMigrations("invalid", []postgresql.Migration{
+		{Version: 1, Up: "CREATE TABLE"},
+		{Version: 1, Up: "CREATE TABLE"},
+	}))
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// type check to ensure interface is properly implemented
+var _ database.HeightPreparer = &Database{}
+
+// partitionSizeSetting is the name of the setting that contains the partition
+// size while the migrations are applied, it can be read inside the migrations
+// with current_setting('flux.partition_size')::BIGINT.
+const partitionSizeSetting = "flux.partition_size"
+
+// blocksTable is the name of the partitioned table that contains the indexed blocks.
+const blocksTable = "blocks"
+
+// partitionKey identifies a partition of a table partitioned by height.
+type partitionKey struct {
+	table string
+	from  int64
+}
+
+// GetPartitionRange returns the range of heights [from, to) contained in the
+// partition that includes the provided height.
+func (c Config) GetPartitionRange(height types.Height) (int64, int64) {
+	partitionSize := c.GetPartitionSize()
+	from := int64(height) / partitionSize * partitionSize
+	return from, from + partitionSize
+}
+
+// EnsureHeightPartition creates the partition of the provided table that contains
+// the given height, if it doesn't exist. The table must be partitioned by range on
+// its height column, so that modules can partition their tables with the same
+// scheme used for the blocks table:
+//
+//	CREATE TABLE my_table (height BIGINT NOT NULL, ...) PARTITION BY RANGE (height);
+//
+// The created partitions are cached, so this can be called before each write.
+func (db *Database) EnsureHeightPartition(table string, height types.Height) error {
+	key := db.getPartitionKey(table, height)
+	if db.isPartitionCreated(key) {
+		return nil
+	}
+
+	err := createHeightPartition(db.SQL, key, db.Cfg.GetPartitionSize())
+	if err != nil {
+		return err
+	}
+
+	db.partitions.Store(key, true)
+	return nil
+}
+
+// PrepareHeight implements database.HeightPreparer.
+// The partition of the blocks table that contains the provided height is created outside
+// of the block transaction, so that the lock taken on the blocks table is released right
+// away instead of being held until all the modules have processed the block.
+func (db *Database) PrepareHeight(height types.Height) error {
+	err := db.EnsureHeightPartition(blocksTable, height)
+	if err != nil {
+		return fmt.Errorf("create blocks partition: %w", err)
+	}
+	return nil
+}
+
+func (db *Database) getPartitionKey(table string, height types.Height) partitionKey {
+	from, _ := db.Cfg.GetPartitionRange(height)
+	return partitionKey{table: table, from: from}
+}
+
+func (db *Database) isPartitionCreated(key partitionKey) bool {
+	_, created := db.partitions.Load(key)
+	return created
+}
+
+func createHeightPartition(execer sqlx.Execer, key partitionKey, partitionSize int64) error {
+	_, err := execer.Exec(`SELECT create_height_partition($1, $2, $3)`, key.table, key.from, partitionSize)
+	return err
+}
@@ -0,0 +1,133 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// type check to ensure interface is properly implemented
+var _ database.BlockTx = &BlockTx{}
+
+// BlockTx implements database.BlockTx using a SQL transaction.
+// Modules can obtain the transaction used to index the current block
+// with the GetBlockTx function and perform their writes through its SQL field.
+type BlockTx struct {
+	SQL *sqlx.Tx
+
+	db *Database
+	// Mutex used to serialize the creation of the partitions, since the modules
+	// that don't depend on each other share the transaction concurrently.
+	partitionsMu sync.Mutex
+	// Partitions created inside the transaction, cached once it's committed.
+	createdPartitions []partitionKey
+}
+
+func NewBlockTx(db *Database, tx *sqlx.Tx) *BlockTx {
+	return &BlockTx{
+		SQL: tx,
+		db:  db,
+	}
+}
+
+// GetBlockTx gets the BlockTx used to index the current block from the provided context.
+// Returns false if the context doesn't contain a BlockTx created by a postgres Database.
+func GetBlockTx(ctx context.Context) (*BlockTx, bool) {
+	tx, ok := database.GetBlockTx(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	postgresTx, ok := tx.(*BlockTx)
+	return postgresTx, ok
+}
+
+// EnsureHeightPartition creates inside the transaction the partition of the provided
+// table that contains the given height, if it doesn't exist. The lock taken on the table
+// is held until the transaction terminates, blocking the other transactions writing to
+// the table, so Database.EnsureHeightPartition should be preferred unless the table has
+// been created inside the same transaction.
+// See Database.EnsureHeightPartition for the requirements of the table.
+// This is safe to be called concurrently by the modules sharing the transaction.
+func (tx *BlockTx) EnsureHeightPartition(table string, height types.Height) error {
+	tx.partitionsMu.Lock()
+	defer tx.partitionsMu.Unlock()
+
+	key := tx.db.getPartitionKey(table, height)
+	if tx.db.isPartitionCreated(key) || slices.Contains(tx.createdPartitions, key) {
+		return nil
+	}
+
+	err := createHeightPartition(tx.SQL, key, tx.db.Cfg.GetPartitionSize())
+	if err != nil {
+		return fmt.Errorf("create %s partition: %w", table, err)
+	}
+
+	tx.createdPartitions = append(tx.createdPartitions, key)
+	return nil
+}
+
+// SaveIndexedBlock implements database.BlockTx.
+func (tx *BlockTx) SaveIndexedBlock(indexer string, chainID string, block database.IndexedBlock) error {
+	// The partition is usually created by PrepareHeight before the transaction starts,
+	// creating it inside the transaction is only a fallback
+	err := tx.EnsureHeightPartition(blocksTable, block.Height)
+	if err != nil {
+		return err
+	}
+
+	return saveIndexedBlock(tx.SQL, indexer, chainID, block)
+}
+
+// SaveModuleIndexedBlock implements database.BlockTx.
+func (tx *BlockTx) SaveModuleIndexedBlock(indexer string, chainID string, module string, height types.Height) error {
+	return saveModuleIndexedBlock(tx.SQL, indexer, chainID, module, height)
+}
+
+// DeleteIndexedBlocks implements database.BlockTx.
+func (tx *BlockTx) DeleteIndexedBlocks(indexer string, chainID string, from types.Height) error {
+	return deleteIndexedBlocks(tx.SQL, indexer, chainID, from)
+}
+
+// DeleteFailedBlocks implements database.BlockTx.
+func (tx *BlockTx) DeleteFailedBlocks(indexer string, chainID string, height types.Height) error {
+	return deleteFailedBlocks(tx.SQL, indexer, chainID, height)
+}
+
+// DeleteModuleFailedBlock implements database.BlockTx.
+func (tx *BlockTx) DeleteModuleFailedBlock(indexer string, chainID string, module string, height types.Height) error {
+	return deleteModuleFailedBlock(tx.SQL, indexer, chainID, module, height)
+}
+
+// Commit implements database.BlockTx.
+func (tx *BlockTx) Commit() error {
+	err := tx.SQL.Commit()
+	if err != nil {
+		return err
+	}
+
+	// The partitions exist only once the transaction is committed
+	tx.partitionsMu.Lock()
+	defer tx.partitionsMu.Unlock()
+	for _, key := range tx.createdPartitions {
+		tx.db.partitions.Store(key, true)
+	}
+	return nil
+}
+
+// Rollback implements database.BlockTx.
+func (tx *BlockTx) Rollback() error {
+	err := tx.SQL.Rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
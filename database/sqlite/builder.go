@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+const DatabaseType = "sqlite"
+
+func DatabaseBuilder(
+	ctx context.Context,
+	_ string,
+	rawConfig []byte,
+) (database.Database, error) {
+	var config Config
+	err := yaml.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal sqlite db config: %w", err)
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid sqlite db config: %w", err)
+	}
+
+	indexerCtx := types.GetIndexerContext(ctx)
+
+	return NewDatabase(indexerCtx.Logger, &config)
+}
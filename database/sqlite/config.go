@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"fmt"
+)
+
+// MemoryPath is the path used to store the database in memory, the data
+// are lost once the database is closed.
+const MemoryPath = ":memory:"
+
+type Config struct {
+	// Path of the database file, or MemoryPath to store the database in memory.
+	// An in-memory database uses a single connection, see the README for the
+	// implications on the modules.
+	Path string `yaml:"path"`
+	// BusyTimeout represents the amount of milliseconds to wait for a
+	// locked database to be released before failing.
+	BusyTimeout uint32 `yaml:"busy_timeout"`
+}
+
+func NewConfig(path string, busyTimeout uint32) Config {
+	return Config{
+		Path:        path,
+		BusyTimeout: busyTimeout,
+	}
+}
+
+func (c Config) WithPath(path string) Config {
+	c.Path = path
+	return c
+}
+
+func (c Config) IsMemory() bool {
+	return c.Path == MemoryPath
+}
+
+func (c Config) Validate() error {
+	if c.Path == "" {
+		return fmt.Errorf("path can't be empty")
+	}
+
+	return nil
+}
+
+func DefaultConfig() Config {
+	return NewConfig("flux.db", 5000)
+}
+
+// Implements the Unmarshaler interface of the yaml pkg.
+func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
+	// Local type to avoid recursion during the unmarshal
+	type privateCfg Config
+	config := privateCfg(DefaultConfig())
+	err := unmarshal(&config)
+	if err != nil {
+		return err
+	}
+
+	*c = Config(config)
+	return nil
+}
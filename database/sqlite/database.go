@@ -0,0 +1,409 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	_ "modernc.org/sqlite"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+//go:embed schema/schema.sql
+var schema string
+
+// type check to ensure interface is properly implemented
+var _ database.Database = &Database{}
+
+// missingRangesStmt is the query used to find the gaps between the heights returned by the
+// provided sub-query in the [?1, ?2] range. Each height is paired with the following one, so
+// that the query returns only a row for each gap instead of a row for each missing height.
+// The heights right outside the range are added to find the gaps at its boundaries.
+const missingRangesStmt = `
+SELECT gap_from AS "from", gap_to AS "to"
+FROM (
+	SELECT height + 1 AS gap_from, LEAD(height) OVER (ORDER BY height) - 1 AS gap_to
+	FROM (
+		%s
+		UNION ALL SELECT ?1 - 1
+		UNION ALL SELECT ?2 + 1
+	) AS heights
+) AS gaps
+WHERE gap_from <= gap_to
+ORDER BY gap_from
+`
+
+// Database defines a wrapper around a SQLite database and implements functionality
+// for data aggregation and exporting.
+type Database struct {
+	Logger zerolog.Logger
+	Cfg    *Config
+	SQL    *sqlx.DB
+}
+
+type BlockRow struct {
+	Indexer    string       `db:"indexer"`
+	ChainID    string       `db:"chain_id"`
+	Height     types.Height `db:"height"`
+	Hash       string       `db:"hash"`
+	ParentHash string       `db:"parent_hash"`
+	Timestamp  time.Time    `db:"timestamp"`
+}
+
+type FailedBlockRow struct {
+	Indexer   string       `db:"indexer"`
+	ChainID   string       `db:"chain_id"`
+	Height    types.Height `db:"height"`
+	Module    string       `db:"module"`
+	Error     string       `db:"error"`
+	Attempts  uint32       `db:"attempts"`
+	Timestamp time.Time    `db:"timestamp"`
+}
+
+// NewDatabase opens the SQLite database defined by the provided config,
+// creating the tables used by the indexer if they don't exist.
+func NewDatabase(logger zerolog.Logger, cfg *Config) (*Database, error) {
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout))
+	// Acquire the write lock when the transaction starts, so that the concurrent
+	// transactions wait for each other instead of failing
+	params.Set("_txlock", "immediate")
+	if !cfg.IsMemory() {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+
+	sqliteDB, err := sqlx.Open("sqlite", fmt.Sprintf("file:%s?%s", cfg.Path, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Each connection to an in-memory database has its own data,
+	// use a single connection to share them. While a block is being indexed
+	// the connection is held by its BlockTx, so the modules must perform
+	// their queries through it instead of the Database
+	if cfg.IsMemory() {
+		sqliteDB.SetMaxOpenConns(1)
+	}
+
+	_, err = sqliteDB.Exec(schema)
+	if err != nil {
+		sqliteDB.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	return &Database{
+		Logger: logger.With().Str("component", "database").Logger(),
+		Cfg:    cfg,
+		SQL:    sqliteDB,
+	}, nil
+}
+
+// GetLowestBlock implements database.Database.
+func (db *Database) GetLowestBlock(indexer string, chainID string) (*types.Height, error) {
+	stmt := `
+	SELECT height
+	FROM blocks
+	WHERE indexer = ? AND chain_id = ?
+	ORDER BY height ASC LIMIT 1
+`
+
+	var height types.Height
+	err := db.SQL.QueryRow(stmt, indexer, chainID).Scan(&height)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &height, nil
+}
+
+// GetMissingBlocks implements database.Database.
+func (db *Database) GetMissingBlocks(indexer string, chainID string, from types.Height, to types.Height) ([]types.Height, error) {
+	missingRanges, err := db.GetMissingRanges(indexer, chainID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return database.ExpandHeightRanges(missingRanges), nil
+}
+
+// GetModuleMissingBlocks implements database.Database.
+func (db *Database) GetModuleMissingBlocks(
+	indexer string,
+	chainID string,
+	module string,
+	from types.Height,
+	to types.Height,
+) ([]types.Height, error) {
+	missingRanges, err := db.GetModuleMissingRanges(indexer, chainID, module, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return database.ExpandHeightRanges(missingRanges), nil
+}
+
+// GetMissingRanges implements database.Database.
+func (db *Database) GetMissingRanges(indexer string, chainID string, from types.Height, to types.Height) ([]database.HeightRange, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range, from(%d) must not be greater than to(%d)", from, to)
+	}
+
+	stmt := fmt.Sprintf(missingRangesStmt, `
+		SELECT height
+		FROM blocks
+		WHERE indexer = ?3 AND chain_id = ?4 AND height BETWEEN ?1 AND ?2`)
+
+	var result []database.HeightRange
+	err := db.SQL.Select(&result, stmt, from, to, indexer, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetModuleMissingRanges implements database.Database.
+func (db *Database) GetModuleMissingRanges(
+	indexer string,
+	chainID string,
+	module string,
+	from types.Height,
+	to types.Height,
+) ([]database.HeightRange, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range, from(%d) must not be greater than to(%d)", from, to)
+	}
+
+	stmt := fmt.Sprintf(missingRangesStmt, `
+		SELECT height
+		FROM module_blocks
+		WHERE indexer = ?3 AND chain_id = ?4 AND module = ?5 AND height BETWEEN ?1 AND ?2`)
+
+	var result []database.HeightRange
+	err := db.SQL.Select(&result, stmt, from, to, indexer, chainID, module)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetIndexedBlock implements database.Database.
+func (db *Database) GetIndexedBlock(indexer string, chainID string, height types.Height) (*database.IndexedBlock, error) {
+	stmt := `
+	SELECT *
+	FROM blocks
+	WHERE indexer = ? AND chain_id = ? AND height = ?
+`
+
+	var row BlockRow
+	err := db.SQL.Get(&row, stmt, indexer, chainID, height)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	block := database.NewIndexedBlock(row.Height, row.Hash, row.ParentHash, row.Timestamp)
+	return &block, nil
+}
+
+// SaveIndexedBlock implements database.Database.
+func (db *Database) SaveIndexedBlock(indexer string, chainID string, block database.IndexedBlock) error {
+	return saveIndexedBlock(db.SQL, indexer, chainID, block)
+}
+
+// DeleteIndexedBlocks implements database.Database.
+func (db *Database) DeleteIndexedBlocks(indexer string, chainID string, from types.Height) error {
+	tx, err := db.SQL.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = deleteIndexedBlocks(tx, indexer, chainID, from)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// InitModulesProgress implements database.Database.
+func (db *Database) InitModulesProgress(indexer string, chainID string, modules []string) (bool, error) {
+	tx, err := db.SQL.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var hasProgress bool
+	err = tx.Get(&hasProgress, `SELECT EXISTS(SELECT 1 FROM module_blocks WHERE indexer = ?1 AND chain_id = ?2)`, indexer, chainID)
+	if err != nil {
+		return false, err
+	}
+	if hasProgress {
+		return false, nil
+	}
+
+	stmt := `
+INSERT INTO module_blocks (indexer, chain_id, module, height)
+SELECT indexer, chain_id, ?3, height
+FROM blocks
+WHERE indexer = ?1 AND chain_id = ?2
+ON CONFLICT (indexer, chain_id, module, height) DO NOTHING
+`
+	initialized := false
+	for _, module := range modules {
+		result, err := tx.Exec(stmt, indexer, chainID, module)
+		if err != nil {
+			return false, err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return false, err
+		}
+		initialized = initialized || rows > 0
+	}
+
+	return initialized, tx.Commit()
+}
+
+// SaveFailedBlock implements database.Database.
+func (db *Database) SaveFailedBlock(indexer string, chainID string, block database.FailedBlock) error {
+	stmt := `
+INSERT INTO failed_blocks (indexer, chain_id, height, module, error, attempts, timestamp)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (indexer, chain_id, height, module) DO UPDATE
+	SET error = excluded.error,
+		attempts = excluded.attempts,
+		timestamp = excluded.timestamp
+`
+
+	_, err := db.SQL.Exec(stmt,
+		indexer,
+		chainID,
+		block.Height,
+		block.Module,
+		block.Error,
+		block.Attempts,
+		block.Timestamp.UTC(),
+	)
+	return err
+}
+
+// GetFailedBlocks implements database.Database.
+func (db *Database) GetFailedBlocks(indexer string, chainID string) ([]database.FailedBlock, error) {
+	stmt := `
+	SELECT *
+	FROM failed_blocks
+	WHERE indexer = ? AND chain_id = ?
+	ORDER BY height, module
+`
+
+	var rows []FailedBlockRow
+	err := db.SQL.Select(&rows, stmt, indexer, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []database.FailedBlock
+	for _, row := range rows {
+		result = append(result, database.NewFailedBlock(row.Height, row.Module, row.Error, row.Attempts, row.Timestamp))
+	}
+
+	return result, nil
+}
+
+// DeleteFailedBlocks implements database.Database.
+func (db *Database) DeleteFailedBlocks(indexer string, chainID string, height types.Height) error {
+	return deleteFailedBlocks(db.SQL, indexer, chainID, height)
+}
+
+// BeginBlockTx implements database.Database.
+func (db *Database) BeginBlockTx(ctx context.Context) (database.BlockTx, error) {
+	tx, err := db.SQL.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBlockTx(tx), nil
+}
+
+// Close closes the database.
+func (db *Database) Close() error {
+	return db.SQL.Close()
+}
+
+func saveIndexedBlock(execer sqlx.Execer, indexer string, chainID string, block database.IndexedBlock) error {
+	stmt := `
+INSERT INTO blocks (indexer, chain_id, height, hash, parent_hash, timestamp)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (indexer, chain_id, height) DO UPDATE
+	SET hash = excluded.hash,
+		parent_hash = excluded.parent_hash,
+		timestamp = excluded.timestamp
+`
+
+	_, err := execer.Exec(stmt,
+		indexer,
+		chainID,
+		block.Height,
+		block.Hash,
+		block.ParentHash,
+		block.Timestamp.UTC(),
+	)
+	return err
+}
+
+func saveModuleIndexedBlock(execer sqlx.Execer, indexer string, chainID string, module string, height types.Height) error {
+	stmt := `
+INSERT INTO module_blocks (indexer, chain_id, module, height)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (indexer, chain_id, module, height) DO NOTHING
+`
+
+	_, err := execer.Exec(stmt, indexer, chainID, module, height)
+	return err
+}
+
+func deleteIndexedBlocks(execer sqlx.Execer, indexer string, chainID string, from types.Height) error {
+	stmt := `DELETE FROM blocks WHERE indexer = ? AND chain_id = ? AND height >= ?`
+	_, err := execer.Exec(stmt, indexer, chainID, from)
+	if err != nil {
+		return err
+	}
+
+	stmt = `DELETE FROM module_blocks WHERE indexer = ? AND chain_id = ? AND height >= ?`
+	_, err = execer.Exec(stmt, indexer, chainID, from)
+	if err != nil {
+		return err
+	}
+
+	stmt = `DELETE FROM failed_blocks WHERE indexer = ? AND chain_id = ? AND height >= ?`
+	_, err = execer.Exec(stmt, indexer, chainID, from)
+	return err
+}
+
+func deleteFailedBlocks(execer sqlx.Execer, indexer string, chainID string, height types.Height) error {
+	stmt := `DELETE FROM failed_blocks WHERE indexer = ? AND chain_id = ? AND height = ?`
+	_, err := execer.Exec(stmt, indexer, chainID, height)
+	return err
+}
+
+func deleteModuleFailedBlock(execer sqlx.Execer, indexer string, chainID string, module string, height types.Height) error {
+	stmt := `DELETE FROM failed_blocks WHERE indexer = ? AND chain_id = ? AND height = ? AND module = ?`
+	_, err := execer.Exec(stmt, indexer, chainID, height, module)
+	return err
+}
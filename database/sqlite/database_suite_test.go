@@ -0,0 +1,38 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milkyway-labs/flux/database/sqlite"
+	dbsuite "github.com/milkyway-labs/flux/database/suite"
+)
+
+func TestDatabaseTestSuite(t *testing.T) {
+	testSuite := new(DbTestSuite)
+	testSuite.WithBeforeTestHook(testSuite.SetupTest)
+	suite.Run(t, testSuite)
+}
+
+type DbTestSuite struct {
+	dbsuite.Suite
+
+	database *sqlite.Database
+}
+
+func (suite *DbTestSuite) SetupTest() {
+	// Close the database used by the previous test, discarding its data
+	if suite.database != nil {
+		suite.Require().NoError(suite.database.Close())
+	}
+
+	// Build the database
+	dbCfg := sqlite.DefaultConfig().WithPath(sqlite.MemoryPath)
+	parserDb, err := sqlite.NewDatabase(log.Logger, &dbCfg)
+	suite.Require().NoError(err)
+
+	suite.database = parserDb
+	suite.InitDB(parserDb)
+}
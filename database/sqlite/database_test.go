@@ -0,0 +1,50 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/database/sqlite"
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestDatabase_ConcurrentBlockTxs(t *testing.T) {
+	cfg := sqlite.DefaultConfig().WithPath(filepath.Join(t.TempDir(), "flux.db"))
+	db, err := sqlite.NewDatabase(zerolog.Nop(), &cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	wg := sync.WaitGroup{}
+	for height := types.Height(1); height <= 20; height++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := db.BeginBlockTx(context.Background())
+			require.NoError(t, err)
+			require.NoError(t, tx.SaveModuleIndexedBlock("indexer", "test", "module", height))
+			require.NoError(t, tx.SaveIndexedBlock("indexer", "test", database.NewIndexedBlock(height, "", "", time.Now())))
+			require.NoError(t, tx.Commit())
+		}()
+	}
+	wg.Wait()
+
+	missing, err := db.GetMissingBlocks("indexer", "test", 1, 21)
+	require.NoError(t, err)
+	require.Equal(t, []types.Height{21}, missing)
+
+	// The schema creation is idempotent
+	reopened, err := sqlite.NewDatabase(zerolog.Nop(), &cfg)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	lowest, err := reopened.GetLowestBlock("indexer", "test")
+	require.NoError(t, err)
+	require.Equal(t, types.Height(1), *lowest)
+}
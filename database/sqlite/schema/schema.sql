@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS blocks
+(
+    -- Name of the indexer that has indexed the block.
+    indexer     TEXT NOT NULL,
+    -- ID of the chain from which the block has been fetched.
+    chain_id    TEXT NOT NULL,
+    -- Height of the indexed block.
+    height      BIGINT NOT NULL,
+    -- Hash of the indexed block.
+    hash        TEXT NOT NULL DEFAULT '',
+    -- Hash of the block that precedes the indexed one.
+    parent_hash TEXT NOT NULL DEFAULT '',
+    -- Time at which the indexed block has been produced by the chain.
+    timestamp   TIMESTAMP NOT NULL,
+    CONSTRAINT unique_chain_block UNIQUE (indexer, chain_id, height)
+);
+
+CREATE TABLE IF NOT EXISTS module_blocks
+(
+    -- Name of the indexer that owns the module.
+    indexer     TEXT NOT NULL,
+    -- ID of the chain from which the block has been fetched.
+    chain_id    TEXT NOT NULL,
+    -- Name of the module that has processed the block.
+    module      TEXT NOT NULL,
+    -- Height of the processed block.
+    height      BIGINT NOT NULL,
+    CONSTRAINT unique_module_block UNIQUE (indexer, chain_id, module, height)
+);
+
+CREATE TABLE IF NOT EXISTS failed_blocks
+(
+    -- Name of the indexer that failed to index the block.
+    indexer     TEXT NOT NULL,
+    -- ID of the chain from which the block has been fetched.
+    chain_id    TEXT NOT NULL,
+    -- Height of the failed block.
+    height      BIGINT NOT NULL,
+    -- Name of the module that failed to process the block, empty if the
+    -- failure is not related to a module.
+    module      TEXT NOT NULL DEFAULT '',
+    -- Error that caused the failure.
+    error       TEXT NOT NULL,
+    -- Number of attempts performed to index the block.
+    attempts    INTEGER NOT NULL,
+    -- Time at which the block has been marked as failed.
+    timestamp   TIMESTAMP NOT NULL,
+    CONSTRAINT unique_failed_block UNIQUE (indexer, chain_id, height, module)
+);
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// type check to ensure interface is properly implemented
+var _ database.BlockTx = &BlockTx{}
+
+// BlockTx implements database.BlockTx using a SQL transaction.
+// Modules can obtain the transaction used to index the current block
+// with the GetBlockTx function and perform their writes through its SQL field.
+type BlockTx struct {
+	SQL *sqlx.Tx
+}
+
+func NewBlockTx(tx *sqlx.Tx) *BlockTx {
+	return &BlockTx{
+		SQL: tx,
+	}
+}
+
+// GetBlockTx gets the BlockTx used to index the current block from the provided context.
+// Returns false if the context doesn't contain a BlockTx created by a sqlite Database.
+func GetBlockTx(ctx context.Context) (*BlockTx, bool) {
+	tx, ok := database.GetBlockTx(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	sqliteTx, ok := tx.(*BlockTx)
+	return sqliteTx, ok
+}
+
+// SaveIndexedBlock implements database.BlockTx.
+func (tx *BlockTx) SaveIndexedBlock(indexer string, chainID string, block database.IndexedBlock) error {
+	return saveIndexedBlock(tx.SQL, indexer, chainID, block)
+}
+
+// SaveModuleIndexedBlock implements database.BlockTx.
+func (tx *BlockTx) SaveModuleIndexedBlock(indexer string, chainID string, module string, height types.Height) error {
+	return saveModuleIndexedBlock(tx.SQL, indexer, chainID, module, height)
+}
+
+// DeleteIndexedBlocks implements database.BlockTx.
+func (tx *BlockTx) DeleteIndexedBlocks(indexer string, chainID string, from types.Height) error {
+	return deleteIndexedBlocks(tx.SQL, indexer, chainID, from)
+}
+
+// DeleteFailedBlocks implements database.BlockTx.
+func (tx *BlockTx) DeleteFailedBlocks(indexer string, chainID string, height types.Height) error {
+	return deleteFailedBlocks(tx.SQL, indexer, chainID, height)
+}
+
+// DeleteModuleFailedBlock implements database.BlockTx.
+func (tx *BlockTx) DeleteModuleFailedBlock(indexer string, chainID string, module string, height types.Height) error {
+	return deleteModuleFailedBlock(tx.SQL, indexer, chainID, module, height)
+}
+
+// Commit implements database.BlockTx.
+func (tx *BlockTx) Commit() error {
+	return tx.SQL.Commit()
+}
+
+// Rollback implements database.BlockTx.
+func (tx *BlockTx) Rollback() error {
+	err := tx.SQL.Rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
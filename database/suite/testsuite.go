@@ -1,6 +1,7 @@
 package suite
 
 import (
+	"context"
 	"time"
 
 	"github.com/stretchr/testify/suite"
@@ -59,9 +60,9 @@ func (s *Suite) TestGetLowestBlock() {
 		{
 			name: "return the correct height",
 			setup: func() {
-				s.database.SaveIndexedBlock(testIndexerName, "test", 9, time.Now())
-				s.database.SaveIndexedBlock(testIndexerName, "test", 12, time.Now())
-				s.database.SaveIndexedBlock(testIndexerName, "test", 11, time.Now())
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(9, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(12, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "", "", time.Now()))
 			},
 			shouldErr:      false,
 			indexer:        testIndexerName,
@@ -132,8 +133,8 @@ func (s *Suite) TestGetMissingBlocks() {
 		{
 			name: "chain id is handled correctly",
 			setup: func() {
-				s.database.SaveIndexedBlock(testIndexerName, "test", 12, time.Now())
-				s.database.SaveIndexedBlock(testIndexerName, "test", 11, time.Now())
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(12, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "", "", time.Now()))
 			},
 			shouldErr:       false,
 			indexer:         testIndexerName,
@@ -145,8 +146,8 @@ func (s *Suite) TestGetMissingBlocks() {
 		{
 			name: "return the correct heights",
 			setup: func() {
-				s.database.SaveIndexedBlock(testIndexerName, "test", 12, time.Now())
-				s.database.SaveIndexedBlock(testIndexerName, "test", 11, time.Now())
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(12, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "", "", time.Now()))
 			},
 			shouldErr:       false,
 			indexer:         testIndexerName,
@@ -181,8 +182,7 @@ func (s *Suite) TestSaveIndexedBlock() {
 		setup     func()
 		indexer   string
 		chainID   string
-		height    types.Height
-		timestamp time.Time
+		block     database.IndexedBlock
 		shouldErr bool
 		check     func()
 	}{
@@ -190,8 +190,7 @@ func (s *Suite) TestSaveIndexedBlock() {
 			name:      "save successfully indexed blocks",
 			indexer:   testIndexerName,
 			chainID:   "test",
-			height:    11,
-			timestamp: time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC),
+			block:     database.NewIndexedBlock(11, "", "", time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC)),
 			shouldErr: false,
 			check: func() {
 				heights, err := s.database.GetMissingBlocks(testIndexerName, "test", 11, 11)
@@ -199,6 +198,23 @@ func (s *Suite) TestSaveIndexedBlock() {
 				s.Require().Empty(heights)
 			},
 		},
+		{
+			name: "save overrides an already indexed block",
+			setup: func() {
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "A", "B", time.Now()))
+			},
+			indexer:   testIndexerName,
+			chainID:   "test",
+			block:     database.NewIndexedBlock(11, "C", "D", time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC)),
+			shouldErr: false,
+			check: func() {
+				block, err := s.database.GetIndexedBlock(testIndexerName, "test", 11)
+				s.Require().NoError(err)
+				s.Require().NotNil(block)
+				s.Require().Equal("C", block.Hash)
+				s.Require().Equal("D", block.ParentHash)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -208,7 +224,7 @@ func (s *Suite) TestSaveIndexedBlock() {
 				tc.setup()
 			}
 
-			err := s.database.SaveIndexedBlock(tc.indexer, tc.chainID, tc.height, tc.timestamp)
+			err := s.database.SaveIndexedBlock(tc.indexer, tc.chainID, tc.block)
 			if tc.shouldErr {
 				s.Require().Error(err)
 			} else {
@@ -220,3 +236,579 @@ func (s *Suite) TestSaveIndexedBlock() {
 		})
 	}
 }
+
+func (s *Suite) TestGetIndexedBlock() {
+	testTimestamp := time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		setup         func()
+		indexer       string
+		chainID       string
+		height        types.Height
+		expectedBlock *database.IndexedBlock
+	}{
+		{
+			name:          "not indexed block returns nil",
+			indexer:       testIndexerName,
+			chainID:       "test",
+			height:        10,
+			expectedBlock: nil,
+		},
+		{
+			name: "chain id is handled correctly",
+			setup: func() {
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(10, "A", "B", testTimestamp))
+			},
+			indexer:       testIndexerName,
+			chainID:       "empty",
+			height:        10,
+			expectedBlock: nil,
+		},
+		{
+			name: "return the correct block",
+			setup: func() {
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(10, "A", "B", testTimestamp))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "C", "A", testTimestamp))
+			},
+			indexer: testIndexerName,
+			chainID: "test",
+			height:  11,
+			expectedBlock: &database.IndexedBlock{
+				Height:     11,
+				Hash:       "C",
+				ParentHash: "A",
+				Timestamp:  testTimestamp,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.executeBeforeTestHook()
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			result, err := s.database.GetIndexedBlock(tc.indexer, tc.chainID, tc.height)
+			s.Require().NoError(err)
+			if tc.expectedBlock == nil {
+				s.Require().Nil(result)
+			} else {
+				s.Require().NotNil(result)
+				s.Require().Equal(tc.expectedBlock.Height, result.Height)
+				s.Require().Equal(tc.expectedBlock.Hash, result.Hash)
+				s.Require().Equal(tc.expectedBlock.ParentHash, result.ParentHash)
+				s.Require().True(tc.expectedBlock.Timestamp.Equal(result.Timestamp))
+			}
+		})
+	}
+}
+
+func (s *Suite) TestDeleteIndexedBlocks() {
+	testCases := []struct {
+		name            string
+		setup           func()
+		indexer         string
+		chainID         string
+		from            types.Height
+		expectedMissing []types.Height
+		expectedFailed  []types.Height
+	}{
+		{
+			name: "delete the blocks from the provided height",
+			setup: func() {
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(10, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(12, "", "", time.Now()))
+				s.database.SaveFailedBlock(testIndexerName, "test", database.NewFailedBlock(10, "module", "error", 1, time.Now()))
+				s.database.SaveFailedBlock(testIndexerName, "test", database.NewFailedBlock(13, "", "error", 1, time.Now()))
+			},
+			indexer:         testIndexerName,
+			chainID:         "test",
+			from:            11,
+			expectedMissing: []types.Height{11, 12},
+			expectedFailed:  []types.Height{10},
+		},
+		{
+			name: "chain id is handled correctly",
+			setup: func() {
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(10, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "", "", time.Now()))
+				s.database.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(12, "", "", time.Now()))
+				s.database.SaveFailedBlock(testIndexerName, "test", database.NewFailedBlock(13, "", "error", 1, time.Now()))
+			},
+			indexer:         testIndexerName,
+			chainID:         "empty",
+			from:            10,
+			expectedMissing: nil,
+			expectedFailed:  []types.Height{13},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.executeBeforeTestHook()
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			err := s.database.DeleteIndexedBlocks(tc.indexer, tc.chainID, tc.from)
+			s.Require().NoError(err)
+
+			missing, err := s.database.GetMissingBlocks(testIndexerName, "test", 10, 12)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expectedMissing, missing)
+
+			// The failures of the deleted blocks are removed too
+			failedBlocks, err := s.database.GetFailedBlocks(testIndexerName, "test")
+			s.Require().NoError(err)
+			var failedHeights []types.Height
+			for _, failedBlock := range failedBlocks {
+				failedHeights = append(failedHeights, failedBlock.Height)
+			}
+			s.Require().Equal(tc.expectedFailed, failedHeights)
+		})
+	}
+}
+
+func (s *Suite) TestBeginBlockTx() {
+	testCases := []struct {
+		name            string
+		run             func(tx database.BlockTx) error
+		expectedMissing []types.Height
+	}{
+		{
+			name: "committed writes are stored",
+			run: func(tx database.BlockTx) error {
+				err := tx.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(10, "", "", time.Now()))
+				s.Require().NoError(err)
+				return tx.Commit()
+			},
+			expectedMissing: []types.Height{11},
+		},
+		{
+			name: "rolled back writes are discarded",
+			run: func(tx database.BlockTx) error {
+				err := tx.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(10, "", "", time.Now()))
+				s.Require().NoError(err)
+				return tx.Rollback()
+			},
+			expectedMissing: []types.Height{10, 11},
+		},
+		{
+			name: "rollback after commit has no effect",
+			run: func(tx database.BlockTx) error {
+				err := tx.SaveIndexedBlock(testIndexerName, "test", database.NewIndexedBlock(11, "", "", time.Now()))
+				s.Require().NoError(err)
+				s.Require().NoError(tx.Commit())
+				return tx.Rollback()
+			},
+			expectedMissing: []types.Height{10},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.executeBeforeTestHook()
+
+			tx, err := s.database.BeginBlockTx(context.Background())
+			s.Require().NoError(err)
+			s.Require().NoError(tc.run(tx))
+
+			missing, err := s.database.GetMissingBlocks(testIndexerName, "test", 10, 11)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expectedMissing, missing)
+		})
+	}
+}
+
+func (s *Suite) TestGetModuleMissingBlocks() {
+	saveModuleBlocks := func(module string, heights ...types.Height) {
+		tx, err := s.database.BeginBlockTx(context.Background())
+		s.Require().NoError(err)
+		for _, height := range heights {
+			s.Require().NoError(tx.SaveModuleIndexedBlock(testIndexerName, "test", module, height))
+		}
+		s.Require().NoError(tx.Commit())
+	}
+
+	testCases := []struct {
+		name            string
+		setup           func()
+		shouldErr       bool
+		module          string
+		from            types.Height
+		to              types.Height
+		expectedHeigths []types.Height
+	}{
+		{
+			name:      "if from is higher then to fails",
+			shouldErr: true,
+			module:    "module",
+			from:      3,
+			to:        2,
+		},
+		{
+			name:            "empty database return all heights",
+			module:          "module",
+			from:            1,
+			to:              3,
+			expectedHeigths: []types.Height{1, 2, 3},
+		},
+		{
+			name: "module name is handled correctly",
+			setup: func() {
+				saveModuleBlocks("other", 11, 12)
+			},
+			module:          "module",
+			from:            10,
+			to:              13,
+			expectedHeigths: []types.Height{10, 11, 12, 13},
+		},
+		{
+			name: "return the correct heights",
+			setup: func() {
+				saveModuleBlocks("module", 11, 12)
+			},
+			module:          "module",
+			from:            10,
+			to:              13,
+			expectedHeigths: []types.Height{10, 13},
+		},
+		{
+			name: "deleted blocks are missing",
+			setup: func() {
+				saveModuleBlocks("module", 10, 11, 12, 13)
+				s.Require().NoError(s.database.DeleteIndexedBlocks(testIndexerName, "test", 12))
+			},
+			module:          "module",
+			from:            10,
+			to:              13,
+			expectedHeigths: []types.Height{12, 13},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.executeBeforeTestHook()
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			result, err := s.database.GetModuleMissingBlocks(testIndexerName, "test", tc.module, tc.from, tc.to)
+			if tc.shouldErr {
+				s.Require().Error(err)
+			} else {
+				s.Require().NoError(err)
+				s.Require().Equal(tc.expectedHeigths, result)
+			}
+		})
+	}
+}
+
+func (s *Suite) TestGetMissingRanges() {
+	saveBlocks := func(chainID string, heights ...types.Height) {
+		for _, height := range heights {
+			err := s.database.SaveIndexedBlock(testIndexerName, chainID, database.NewIndexedBlock(height, "", "", time.Now()))
+			s.Require().NoError(err)
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		setup          func()
+		shouldErr      bool
+		chainID        string
+		from           types.Height
+		to             types.Height
+		expectedRanges []database.HeightRange
+	}{
+		{
+			name:      "if from is higher then to fails",
+			shouldErr: true,
+			chainID:   "test",
+			from:      3,
+			to:        2,
+		},
+		{
+			name:           "empty database return the whole range",
+			chainID:        "test",
+			from:           0,
+			to:             3,
+			expectedRanges: []database.HeightRange{{From: 0, To: 3}},
+		},
+		{
+			name: "chain id is handled correctly",
+			setup: func() {
+				saveBlocks("test", 11, 12)
+			},
+			chainID:        "empty",
+			from:           10,
+			to:             13,
+			expectedRanges: []database.HeightRange{{From: 10, To: 13}},
+		},
+		{
+			name: "return the gaps between the indexed blocks",
+			setup: func() {
+				saveBlocks("test", 3, 4, 7, 10, 11, 20)
+			},
+			chainID: "test",
+			from:    1,
+			to:      12,
+			expectedRanges: []database.HeightRange{
+				{From: 1, To: 2},
+				{From: 5, To: 6},
+				{From: 8, To: 9},
+				{From: 12, To: 12},
+			},
+		},
+		{
+			name: "fully indexed range returns no gaps",
+			setup: func() {
+				saveBlocks("test", 3, 4, 5)
+			},
+			chainID: "test",
+			from:    3,
+			to:      5,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.executeBeforeTestHook()
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			result, err := s.database.GetMissingRanges(testIndexerName, tc.chainID, tc.from, tc.to)
+			if tc.shouldErr {
+				s.Require().Error(err)
+			} else {
+				s.Require().NoError(err)
+				s.Require().Equal(tc.expectedRanges, result)
+			}
+		})
+	}
+}
+
+func (s *Suite) TestGetModuleMissingRanges() {
+	s.executeBeforeTestHook()
+
+	tx, err := s.database.BeginBlockTx(context.Background())
+	s.Require().NoError(err)
+	for _, height := range []types.Height{2, 3, 6} {
+		s.Require().NoError(tx.SaveModuleIndexedBlock(testIndexerName, "test", "module", height))
+	}
+	s.Require().NoError(tx.SaveModuleIndexedBlock(testIndexerName, "test", "other", 4))
+	s.Require().NoError(tx.Commit())
+
+	result, err := s.database.GetModuleMissingRanges(testIndexerName, "test", "module", 1, 7)
+	s.Require().NoError(err)
+	s.Require().Equal([]database.HeightRange{{From: 1, To: 1}, {From: 4, To: 5}, {From: 7, To: 7}}, result)
+
+	_, err = s.database.GetModuleMissingRanges(testIndexerName, "test", "module", 7, 1)
+	s.Require().Error(err)
+}
+
+func (s *Suite) TestInitModulesProgress() {
+	testTimestamp := time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC)
+	saveBlocks := func(heights ...types.Height) {
+		for _, height := range heights {
+			s.Require().NoError(s.database.SaveIndexedBlock(testIndexerName, "test",
+				database.NewIndexedBlock(height, "", "", testTimestamp)))
+		}
+	}
+
+	testCases := []struct {
+		name                string
+		setup               func()
+		modules             []string
+		expectedInitialized bool
+		expectedMissing     map[string][]database.HeightRange
+	}{
+		{
+			name:                "empty database is not initialized",
+			modules:             []string{"module"},
+			expectedInitialized: false,
+			expectedMissing: map[string][]database.HeightRange{
+				"module": {{From: 1, To: 5}},
+			},
+		},
+		{
+			name: "indexed blocks are stored as processed by all the modules",
+			setup: func() {
+				saveBlocks(2, 3, 5)
+			},
+			modules:             []string{"module", "other"},
+			expectedInitialized: true,
+			expectedMissing: map[string][]database.HeightRange{
+				"module": {{From: 1, To: 1}, {From: 4, To: 4}},
+				"other":  {{From: 1, To: 1}, {From: 4, To: 4}},
+			},
+		},
+		{
+			name: "existing progress is not changed",
+			setup: func() {
+				saveBlocks(2, 3, 5)
+				tx, err := s.database.BeginBlockTx(context.Background())
+				s.Require().NoError(err)
+				s.Require().NoError(tx.SaveModuleIndexedBlock(testIndexerName, "test", "module", 3))
+				s.Require().NoError(tx.Commit())
+			},
+			modules:             []string{"module", "other"},
+			expectedInitialized: false,
+			expectedMissing: map[string][]database.HeightRange{
+				"module": {{From: 1, To: 2}, {From: 4, To: 5}},
+				"other":  {{From: 1, To: 5}},
+			},
+		},
+		{
+			name: "progress of another indexer is ignored",
+			setup: func() {
+				saveBlocks(2)
+				s.Require().NoError(s.database.SaveIndexedBlock("other", "test",
+					database.NewIndexedBlock(3, "", "", testTimestamp)))
+				tx, err := s.database.BeginBlockTx(context.Background())
+				s.Require().NoError(err)
+				s.Require().NoError(tx.SaveModuleIndexedBlock("other", "test", "module", 3))
+				s.Require().NoError(tx.Commit())
+			},
+			modules:             []string{"module"},
+			expectedInitialized: true,
+			expectedMissing: map[string][]database.HeightRange{
+				"module": {{From: 1, To: 1}, {From: 3, To: 5}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.executeBeforeTestHook()
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			initialized, err := s.database.InitModulesProgress(testIndexerName, "test", tc.modules)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expectedInitialized, initialized)
+
+			for module, expected := range tc.expectedMissing {
+				missing, err := s.database.GetModuleMissingRanges(testIndexerName, "test", module, 1, 5)
+				s.Require().NoError(err)
+				s.Require().Equal(expected, missing, module)
+			}
+		})
+	}
+}
+
+func (s *Suite) TestFailedBlocks() {
+	testTimestamp := time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		setup          func()
+		chainID        string
+		expectedBlocks []database.FailedBlock
+	}{
+		{
+			name:           "empty database return no blocks",
+			chainID:        "test",
+			expectedBlocks: nil,
+		},
+		{
+			name: "chain id is handled correctly",
+			setup: func() {
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "", "error", 5, testTimestamp)))
+			},
+			chainID:        "empty",
+			expectedBlocks: nil,
+		},
+		{
+			name: "return the blocks ordered by height",
+			setup: func() {
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(12, "", "error", 5, testTimestamp)))
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module2", "error 2", 5, testTimestamp)))
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module1", "error 1", 5, testTimestamp)))
+			},
+			chainID: "test",
+			expectedBlocks: []database.FailedBlock{
+				database.NewFailedBlock(10, "module1", "error 1", 5, testTimestamp),
+				database.NewFailedBlock(10, "module2", "error 2", 5, testTimestamp),
+				database.NewFailedBlock(12, "", "error", 5, testTimestamp),
+			},
+		},
+		{
+			name: "save overrides the previous failure",
+			setup: func() {
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module", "error", 5, testTimestamp)))
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module", "new error", 3, testTimestamp)))
+			},
+			chainID: "test",
+			expectedBlocks: []database.FailedBlock{
+				database.NewFailedBlock(10, "module", "new error", 3, testTimestamp),
+			},
+		},
+		{
+			name: "delete removes all the failures of a height",
+			setup: func() {
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module1", "error", 5, testTimestamp)))
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module2", "error", 5, testTimestamp)))
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(11, "", "error", 5, testTimestamp)))
+				s.Require().NoError(s.database.DeleteFailedBlocks(testIndexerName, "test", 10))
+			},
+			chainID: "test",
+			expectedBlocks: []database.FailedBlock{
+				database.NewFailedBlock(11, "", "error", 5, testTimestamp),
+			},
+		},
+		{
+			name: "delete module failure removes only the module failure",
+			setup: func() {
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "", "error", 5, testTimestamp)))
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module1", "error", 5, testTimestamp)))
+				s.Require().NoError(s.database.SaveFailedBlock(testIndexerName, "test",
+					database.NewFailedBlock(10, "module2", "error", 5, testTimestamp)))
+
+				tx, err := s.database.BeginBlockTx(context.Background())
+				s.Require().NoError(err)
+				s.Require().NoError(tx.DeleteModuleFailedBlock(testIndexerName, "test", "", 10))
+				s.Require().NoError(tx.DeleteModuleFailedBlock(testIndexerName, "test", "module1", 10))
+				s.Require().NoError(tx.Commit())
+			},
+			chainID: "test",
+			expectedBlocks: []database.FailedBlock{
+				database.NewFailedBlock(10, "module2", "error", 5, testTimestamp),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.executeBeforeTestHook()
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			result, err := s.database.GetFailedBlocks(testIndexerName, tc.chainID)
+			s.Require().NoError(err)
+			s.Require().Len(result, len(tc.expectedBlocks))
+			for i, expected := range tc.expectedBlocks {
+				s.Require().Equal(expected.Height, result[i].Height)
+				s.Require().Equal(expected.Module, result[i].Module)
+				s.Require().Equal(expected.Error, result[i].Error)
+				s.Require().Equal(expected.Attempts, result[i].Attempts)
+				s.Require().True(expected.Timestamp.Equal(result[i].Timestamp))
+			}
+		})
+	}
+}
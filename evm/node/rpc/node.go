@@ -0,0 +1,210 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	evmtypes "github.com/milkyway-labs/flux/evm/types"
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+var (
+	_ node.Node                    = &Node{}
+	_ node.FinalizedHeightProvider = &Node{}
+)
+
+type Node struct {
+	cfg     Config
+	logger  zerolog.Logger
+	client  *jsonrpc2.Client
+	chainID string
+}
+
+func NewNode(ctx context.Context, logger zerolog.Logger, cfg Config) (*Node, error) {
+	logger = logger.With().Str("evm-node", cfg.URL).Logger()
+
+	client, err := jsonrpc2.NewClient(cfg.URL, &http.Client{
+		Timeout: cfg.RequestTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create rpc client: %w", err)
+	}
+	client.WithMaxRequestsPerSecond(cfg.MaxRequestsPerSecond).
+		WithMaxConcurrentRequests(cfg.MaxConcurrentRequests)
+
+	var chainID Quantity
+	if err := client.Call(ctx, "eth_chainId", []any{}, &chainID); err != nil {
+		return nil, fmt.Errorf("get chain id: %w", err)
+	}
+
+	return &Node{
+		cfg:     cfg,
+		logger:  logger,
+		client:  client,
+		chainID: strconv.FormatUint(uint64(chainID), 10),
+	}, nil
+}
+
+// GetChainID implements node.Node.
+func (n *Node) GetChainID() string {
+	return n.chainID
+}
+
+// GetCurrentHeight implements node.Node.
+func (n *Node) GetCurrentHeight(ctx context.Context) (types.Height, error) {
+	var height Quantity
+	if err := n.client.Call(ctx, "eth_blockNumber", []any{}, &height); err != nil {
+		return 0, fmt.Errorf("call eth_blockNumber: %w", err)
+	}
+
+	return types.Height(height), nil
+}
+
+// GetFinalizedHeight implements node.FinalizedHeightProvider.
+func (n *Node) GetFinalizedHeight(ctx context.Context) (types.Height, error) {
+	return n.getBlockHeight(ctx, BlockNumberFinalized)
+}
+
+// GetLowestHeight implements node.Node.
+func (n *Node) GetLowestHeight(ctx context.Context) (types.Height, error) {
+	return n.getBlockHeight(ctx, BlockNumberEarliest)
+}
+
+// getBlockHeight gets the height of the block with the provided tag.
+func (n *Node) getBlockHeight(ctx context.Context, blockNumber BlockNumber) (types.Height, error) {
+	var block *Block
+	if err := n.client.Call(ctx, "eth_getBlockByNumber", []any{blockNumber, false}, &block); err != nil {
+		return 0, fmt.Errorf("call eth_getBlockByNumber: %w", err)
+	}
+	if block == nil {
+		return 0, fmt.Errorf("%s block not found", blockNumber)
+	}
+
+	return types.Height(block.Number), nil
+}
+
+// GetBlock implements node.Node.
+func (n *Node) GetBlock(ctx context.Context, height types.Height) (types.Block, error) {
+	block, receipts, err := n.getBlockWithReceipts(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptsByHash := make(map[string]*Receipt, len(receipts))
+	for i := range receipts {
+		receiptsByHash[receipts[i].TransactionHash] = &receipts[i]
+	}
+
+	txs := make([]evmtypes.Tx, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		receipt, found := receiptsByHash[tx.Hash]
+		if !found {
+			return nil, fmt.Errorf("receipt of tx %s not found (height %d)", tx.Hash, height)
+		}
+
+		logs := make([]evmtypes.Log, len(receipt.Logs))
+		for j, log := range receipt.Logs {
+			logs[j] = log.ToLog()
+		}
+
+		txs[i] = evmtypes.Tx{
+			Hash:              tx.Hash,
+			Index:             uint32(tx.TransactionIndex),
+			Type:              uint64(tx.Type),
+			From:              tx.From,
+			To:                tx.To,
+			Nonce:             uint64(tx.Nonce),
+			Value:             tx.Value.ToBigInt(),
+			Input:             tx.Input,
+			Gas:               uint64(tx.Gas),
+			GasPrice:          tx.GasPrice.ToBigInt(),
+			Status:            uint64(receipt.Status),
+			GasUsed:           uint64(receipt.GasUsed),
+			EffectiveGasPrice: receipt.EffectiveGasPrice.ToBigInt(),
+			ContractAddress:   receipt.ContractAddress,
+			Logs:              logs,
+		}
+	}
+
+	header := evmtypes.NewBlockHeader(
+		n.chainID,
+		types.Height(block.Number),
+		time.Unix(int64(block.Timestamp), 0).UTC(),
+		block.Hash,
+		block.ParentHash,
+	)
+	header.Miner = block.Miner
+	header.GasLimit = uint64(block.GasLimit)
+	header.GasUsed = uint64(block.GasUsed)
+	header.BaseFeePerGas = block.BaseFeePerGas.ToBigInt()
+	header.StateRoot = block.StateRoot
+	header.TransactionsRoot = block.TransactionsRoot
+	header.ReceiptsRoot = block.ReceiptsRoot
+
+	return evmtypes.NewBlock(header, txs), nil
+}
+
+// getBlockWithReceipts fetches the block at the provided height along with
+// the receipts of its transactions.
+func (n *Node) getBlockWithReceipts(ctx context.Context, height types.Height) (*Block, []Receipt, error) {
+	blockNumber := NewBlockNumber(height)
+
+	var block *Block
+	var receipts []Receipt
+	if n.cfg.BlockReceipts {
+		// Fetch the block and its receipts with a single request
+		batch := []jsonrpc2.BatchElem{
+			jsonrpc2.NewBatchElem("eth_getBlockByNumber", []any{blockNumber, true}, &block),
+			jsonrpc2.NewBatchElem("eth_getBlockReceipts", []any{blockNumber}, &receipts),
+		}
+		if err := n.client.BatchCall(ctx, batch); err != nil {
+			return nil, nil, fmt.Errorf("batch call: %w", err)
+		}
+		if err := batch[0].Error; err != nil {
+			return nil, nil, fmt.Errorf("call eth_getBlockByNumber: %w", err)
+		}
+		if err := batch[1].Error; err != nil {
+			return nil, nil, fmt.Errorf("call eth_getBlockReceipts: %w", err)
+		}
+	} else {
+		err := n.client.Call(ctx, "eth_getBlockByNumber", []any{blockNumber, true}, &block)
+		if err != nil {
+			return nil, nil, fmt.Errorf("call eth_getBlockByNumber: %w", err)
+		}
+	}
+
+	if block == nil {
+		return nil, nil, fmt.Errorf("block %d not found", height)
+	}
+
+	if !n.cfg.BlockReceipts && len(block.Transactions) > 0 {
+		receipts = make([]Receipt, len(block.Transactions))
+		batch := make([]jsonrpc2.BatchElem, len(block.Transactions))
+		for i, tx := range block.Transactions {
+			batch[i] = jsonrpc2.NewBatchElem("eth_getTransactionReceipt", []any{tx.Hash}, &receipts[i])
+		}
+		if err := n.client.BatchCall(ctx, batch); err != nil {
+			return nil, nil, fmt.Errorf("batch call: %w", err)
+		}
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, nil, fmt.Errorf("call eth_getTransactionReceipt (tx %s): %w",
+					block.Transactions[i].Hash, elem.Error)
+			}
+		}
+	}
+
+	return block, receipts, nil
+}
+
+// Config gets the Node configuration.
+func (n *Node) Config() Config {
+	return n.cfg
+}
@@ -0,0 +1,211 @@
+package rpc_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/evm/node/rpc"
+	evmtypes "github.com/milkyway-labs/flux/evm/types"
+	"github.com/milkyway-labs/flux/modules/adapter"
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+const (
+	testTxHash    = "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+	testTransfer  = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	testFromTopic = "0x000000000000000000000000a7d9ddbe1f17865597fbd27ec712455208b6b76d"
+	testToTopic   = "0x000000000000000000000000f02c1c8e6114b1dbe8937a39260b5b0a374432bb"
+)
+
+var testResults = map[string]string{
+	"eth_chainId":     `"0x1"`,
+	"eth_blockNumber": `"0x14"`,
+	"eth_getBlockByNumber": `{
+		"number": "0xa",
+		"hash": "0xaa",
+		"parentHash": "0xbb",
+		"timestamp": "0x65920080",
+		"miner": "0x4838b106fce9647bdf1e7877bf73ce8b0bad5f97",
+		"gasLimit": "0x1c9c380",
+		"gasUsed": "0x5208",
+		"baseFeePerGas": "0x3b9aca00",
+		"transactions": [{
+			"hash": "` + testTxHash + `",
+			"transactionIndex": "0x0",
+			"type": "0x2",
+			"from": "0xa7d9ddbe1f17865597fbd27ec712455208b6b76d",
+			"to": "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			"nonce": "0x15",
+			"value": "0xde0b6b3a7640000",
+			"input": "0xa9059cbb",
+			"gas": "0x5208",
+			"gasPrice": "0x3b9aca00"
+		}]
+	}`,
+	"eth_getTransactionReceipt": `{
+		"transactionHash": "` + testTxHash + `",
+		"status": "0x1",
+		"gasUsed": "0x5208",
+		"effectiveGasPrice": "0x3b9aca00",
+		"contractAddress": null,
+		"logs": [{
+			"address": "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			"topics": ["` + testTransfer + `", "` + testFromTopic + `", "` + testToTopic + `"],
+			"data": "0x0000000000000000000000000000000000000000000000000000000000000064",
+			"logIndex": "0x3"
+		}]
+	}`,
+}
+
+// newTestServer creates a server that replies to the JSON-RPC calls with the
+// testResults, the methods called are sent to the provided channel.
+func newTestServer(t *testing.T, calledMethods chan<- string) *httptest.Server {
+	results := map[string]string{}
+	for method, result := range testResults {
+		results[method] = result
+	}
+	results["eth_getBlockReceipts"] = "[" + testResults["eth_getTransactionReceipt"] + "]"
+
+	reply := func(req jsonrpc2.Request) jsonrpc2.Response {
+		if calledMethods != nil {
+			calledMethods <- req.Method
+		}
+		return jsonrpc2.Response{
+			JSONRPC: jsonrpc2.ProtocolVersion,
+			ID:      req.ID,
+			Result:  json.RawMessage(results[req.Method]),
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		if bytes.HasPrefix(body, []byte("[")) {
+			var reqs []jsonrpc2.Request
+			require.NoError(t, json.Unmarshal(body, &reqs))
+			resps := make([]jsonrpc2.Response, len(reqs))
+			for i, req := range reqs {
+				resps[i] = reply(req)
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(resps))
+			return
+		}
+
+		var req jsonrpc2.Request
+		require.NoError(t, json.Unmarshal(body, &req))
+		require.NoError(t, json.NewEncoder(w).Encode(reply(req)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestNode(t *testing.T, blockReceipts bool, calledMethods chan<- string) *rpc.Node {
+	cfg := rpc.NewConfig(newTestServer(t, calledMethods).URL, time.Second, blockReceipts)
+	node, err := rpc.NewNode(context.Background(), zerolog.Nop(), cfg)
+	require.NoError(t, err)
+	return node
+}
+
+func TestNode_GetChainInfo(t *testing.T) {
+	node := newTestNode(t, true, nil)
+	require.Equal(t, "1", node.GetChainID())
+
+	height, err := node.GetCurrentHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(20), height)
+
+	height, err = node.GetFinalizedHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(10), height)
+}
+
+func TestNode_GetBlock(t *testing.T) {
+	for _, blockReceipts := range []bool{true, false} {
+		calledMethods := make(chan string, 10)
+		node := newTestNode(t, blockReceipts, calledMethods)
+
+		block, err := node.GetBlock(context.Background(), 10)
+		require.NoError(t, err)
+
+		evmBlock, ok := block.(*evmtypes.Block)
+		require.True(t, ok)
+		require.Equal(t, "1", evmBlock.GetChainID())
+		require.Equal(t, types.Height(10), evmBlock.GetHeight())
+		require.Equal(t, "0xaa", evmBlock.GetHash())
+		require.Equal(t, "0xbb", evmBlock.GetParentHash())
+		require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), evmBlock.GetTimeStamp())
+		require.Equal(t, uint64(21000), evmBlock.Header.GasUsed)
+		require.Equal(t, big.NewInt(1_000_000_000), evmBlock.Header.BaseFeePerGas)
+
+		require.Len(t, evmBlock.Txs, 1)
+		tx := evmBlock.Txs[0]
+		require.Equal(t, testTxHash, tx.GetHash())
+		require.True(t, tx.IsSuccessful())
+		require.Equal(t, "0xdac17f958d2ee523a2206206994597c13d831ec7", tx.To)
+		require.Equal(t, big.NewInt(1_000_000_000_000_000_000), tx.Value)
+		require.Equal(t, []byte{0xa9, 0x05, 0x9c, 0xbb}, tx.Input)
+		require.Empty(t, tx.ContractAddress)
+
+		events := tx.GetEvents()
+		require.Len(t, events, 1)
+		require.Equal(t, testTransfer, events[0].GetType())
+		value, found := events[0].GetAttributeValue("topic2")
+		require.True(t, found)
+		require.Equal(t, testToTopic, value)
+
+		log := tx.Logs[0]
+		require.Equal(t, uint32(3), log.Index)
+		from, err := log.GetTopicAddress(1)
+		require.NoError(t, err)
+		require.Equal(t, "0xa7d9ddbe1f17865597fbd27ec712455208b6b76d", from)
+		_, err = log.GetTopicAddress(3)
+		require.Error(t, err)
+
+		close(calledMethods)
+		var methods []string
+		for method := range calledMethods {
+			methods = append(methods, method)
+		}
+		if blockReceipts {
+			require.Equal(t, []string{"eth_chainId", "eth_getBlockByNumber", "eth_getBlockReceipts"}, methods)
+		} else {
+			require.Equal(t, []string{"eth_chainId", "eth_getBlockByNumber", "eth_getTransactionReceipt"}, methods)
+		}
+	}
+}
+
+type testBlockModule struct {
+	blocks []*evmtypes.Block
+}
+
+func (m *testBlockModule) GetName() string {
+	return "test"
+}
+
+func (m *testBlockModule) HandleBlock(_ context.Context, block *evmtypes.Block) error {
+	m.blocks = append(m.blocks, block)
+	return nil
+}
+
+func TestNode_BlockHandleAdapter(t *testing.T) {
+	node := newTestNode(t, true, nil)
+	block, err := node.GetBlock(context.Background(), 10)
+	require.NoError(t, err)
+
+	module := &testBlockModule{}
+	require.NoError(t, adapter.NewBlockHandleAdapter[*evmtypes.Block](module).HandleBlock(context.Background(), block))
+	require.Len(t, module.blocks, 1)
+}
@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	evmtypes "github.com/milkyway-labs/flux/evm/types"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// ---------------------------------------------------------------------------
+// ---- Hex encoding
+// ---------------------------------------------------------------------------
+
+// unmarshalHexString unmarshals a JSON string and removes its 0x prefix.
+func unmarshalHexString(data []byte) (string, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", err
+	}
+
+	value, found := strings.CutPrefix(s, "0x")
+	if !found {
+		return "", fmt.Errorf("hex string without 0x prefix: %s", s)
+	}
+	return value, nil
+}
+
+// Quantity represents an unsigned integer encoded as a 0x prefixed hex string.
+type Quantity uint64
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0x" + strconv.FormatUint(uint64(q), 16))
+}
+
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalHexString(data)
+	if err != nil {
+		return err
+	}
+
+	value, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity: %w", err)
+	}
+	*q = Quantity(value)
+	return nil
+}
+
+// BigQuantity represents an arbitrary large unsigned integer encoded as a 0x prefixed hex string.
+type BigQuantity big.Int
+
+func (q *BigQuantity) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalHexString(data)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := (*big.Int)(q).SetString(s, 16); !ok {
+		return fmt.Errorf("invalid quantity: %s", s)
+	}
+	return nil
+}
+
+// ToBigInt converts the quantity to a big.Int, returns nil if the quantity is nil.
+func (q *BigQuantity) ToBigInt() *big.Int {
+	if q == nil {
+		return nil
+	}
+	return new(big.Int).Set((*big.Int)(q))
+}
+
+// Data represents a byte array encoded as a 0x prefixed hex string.
+type Data []byte
+
+func (d *Data) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalHexString(data)
+	if err != nil {
+		return err
+	}
+
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid data: %w", err)
+	}
+	*d = bz
+	return nil
+}
+
+// BlockNumber represents the number of a block, or one of the block tags
+// (e.g. latest or finalized), used as parameter of the JSON-RPC calls.
+type BlockNumber string
+
+const (
+	BlockNumberEarliest  BlockNumber = "earliest"
+	BlockNumberLatest    BlockNumber = "latest"
+	BlockNumberFinalized BlockNumber = "finalized"
+)
+
+// NewBlockNumber creates a new BlockNumber referring to the provided height.
+func NewBlockNumber(height types.Height) BlockNumber {
+	return BlockNumber("0x" + strconv.FormatUint(uint64(height), 16))
+}
+
+// ---------------------------------------------------------------------------
+// ---- JSON-RPC responses
+// ---------------------------------------------------------------------------
+
+type Block struct {
+	Number           Quantity      `json:"number"`
+	Hash             string        `json:"hash"`
+	ParentHash       string        `json:"parentHash"`
+	Timestamp        Quantity      `json:"timestamp"`
+	Miner            string        `json:"miner"`
+	GasLimit         Quantity      `json:"gasLimit"`
+	GasUsed          Quantity      `json:"gasUsed"`
+	BaseFeePerGas    *BigQuantity  `json:"baseFeePerGas"`
+	StateRoot        string        `json:"stateRoot"`
+	TransactionsRoot string        `json:"transactionsRoot"`
+	ReceiptsRoot     string        `json:"receiptsRoot"`
+	Transactions     []Transaction `json:"transactions"`
+}
+
+type Transaction struct {
+	Hash             string       `json:"hash"`
+	TransactionIndex Quantity     `json:"transactionIndex"`
+	Type             Quantity     `json:"type"`
+	From             string       `json:"from"`
+	To               string       `json:"to"`
+	Nonce            Quantity     `json:"nonce"`
+	Value            *BigQuantity `json:"value"`
+	Input            Data         `json:"input"`
+	Gas              Quantity     `json:"gas"`
+	GasPrice         *BigQuantity `json:"gasPrice"`
+}
+
+type Receipt struct {
+	TransactionHash   string       `json:"transactionHash"`
+	Status            Quantity     `json:"status"`
+	GasUsed           Quantity     `json:"gasUsed"`
+	EffectiveGasPrice *BigQuantity `json:"effectiveGasPrice"`
+	ContractAddress   string       `json:"contractAddress"`
+	Logs              []Log        `json:"logs"`
+}
+
+type Log struct {
+	Address  string   `json:"address"`
+	Topics   []string `json:"topics"`
+	Data     Data     `json:"data"`
+	LogIndex Quantity `json:"logIndex"`
+}
+
+// ToLog converts the log to an evmtypes.Log.
+func (l Log) ToLog() evmtypes.Log {
+	return evmtypes.Log{
+		Address: l.Address,
+		Topics:  l.Topics,
+		Data:    l.Data,
+		Index:   uint32(l.LogIndex),
+	}
+}
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+// ----------------------------------------------------------------------------
+// -- Block related data structures
+// ----------------------------------------------------------------------------
+
+type BlockHeader struct {
+	ChainID    string
+	Height     types.Height
+	Time       time.Time
+	Hash       string
+	ParentHash string
+	// Address of the account that received the block rewards.
+	Miner    string
+	GasLimit uint64
+	GasUsed  uint64
+	// BaseFeePerGas is nil for the blocks produced before the London fork.
+	BaseFeePerGas    *big.Int
+	StateRoot        string
+	TransactionsRoot string
+	ReceiptsRoot     string
+}
+
+func NewBlockHeader(
+	chainID string,
+	height types.Height,
+	time time.Time,
+	hash string,
+	parentHash string,
+) BlockHeader {
+	return BlockHeader{
+		ChainID:    chainID,
+		Height:     height,
+		Time:       time,
+		Hash:       hash,
+		ParentHash: parentHash,
+	}
+}
+
+type Block struct {
+	Header BlockHeader
+	Txs    []Tx
+}
+
+var _ types.Block = &Block{}
+
+func NewBlock(header BlockHeader, txs []Tx) *Block {
+	return &Block{
+		Header: header,
+		Txs:    txs,
+	}
+}
+
+// GetChainID implements types.Block.
+func (b *Block) GetChainID() string {
+	return b.Header.ChainID
+}
+
+// GetHeight implements types.Block.
+func (b *Block) GetHeight() types.Height {
+	return b.Header.Height
+}
+
+// GetHash implements types.Block.
+func (b *Block) GetHash() string {
+	return b.Header.Hash
+}
+
+// GetParentHash implements types.Block.
+func (b *Block) GetParentHash() string {
+	return b.Header.ParentHash
+}
+
+// GetTimeStamp implements types.Block.
+func (b *Block) GetTimeStamp() time.Time {
+	return b.Header.Time
+}
+
+// GetTxs implements types.Block.
+func (b *Block) GetTxs() []types.Tx {
+	result := make([]types.Tx, len(b.Txs))
+	for i := range b.Txs {
+		result[i] = &b.Txs[i]
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// -- Tx related data structures
+// ----------------------------------------------------------------------------
+
+// TxStatusSuccessful is the status of the receipt of a tx executed without errors.
+const TxStatusSuccessful = 1
+
+var _ types.EventsTx = &Tx{}
+
+type Tx struct {
+	Hash string
+	// Index of the tx inside the block.
+	Index uint32
+	Type  uint64
+	From  string
+	// To is empty for the txs that create a contract.
+	To       string
+	Nonce    uint64
+	Value    *big.Int
+	Input    []byte
+	Gas      uint64
+	GasPrice *big.Int
+	// Data obtained from the tx receipt.
+	Status            uint64
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	// ContractAddress is the address of the created contract, if any.
+	ContractAddress string
+	Logs            []Log
+}
+
+// GetHash implements types.Tx.
+func (t *Tx) GetHash() string {
+	return t.Hash
+}
+
+// IsSuccessful implements types.Tx.
+func (t *Tx) IsSuccessful() bool {
+	return t.Status == TxStatusSuccessful
+}
+
+// GetEvents implements types.EventsTx.
+func (t *Tx) GetEvents() []types.Event {
+	events := make([]types.Event, len(t.Logs))
+	for i := range t.Logs {
+		events[i] = &t.Logs[i]
+	}
+	return events
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+const (
+	// LogAttributeAddress is the attribute that contains the address of the contract that emitted the log.
+	LogAttributeAddress = "address"
+	// LogAttributeData is the attribute that contains the hex encoded non-indexed data of the log.
+	LogAttributeData = "data"
+	// LogAttributeTopicPrefix is the prefix of the attributes that contain the indexed
+	// topics of the log, e.g. topic1 is the first indexed argument of the event.
+	LogAttributeTopicPrefix = "topic"
+)
+
+var _ types.Event = &Log{}
+
+// Log represents a log emitted by a contract while executing a tx.
+type Log struct {
+	// Address of the contract that emitted the log.
+	Address string
+	// Topics contains the hex encoded topics, the first one is the hash of the
+	// event signature unless the event is anonymous.
+	Topics []string
+	Data   []byte
+	// Index of the log inside the block.
+	Index uint32
+}
+
+// GetType implements types.Event.
+// The type of a log is the hash of the event signature (e.g. the hash of Transfer(address,address,uint256)).
+func (l *Log) GetType() string {
+	if len(l.Topics) == 0 {
+		return ""
+	}
+	return l.Topics[0]
+}
+
+// GetAttributeValue implements types.Event.
+// The supported keys are address, data and topicN, where N is the index of the topic.
+func (l *Log) GetAttributeValue(key string) (string, bool) {
+	switch key {
+	case LogAttributeAddress:
+		return l.Address, true
+	case LogAttributeData:
+		return "0x" + hex.EncodeToString(l.Data), true
+	}
+
+	if rawIndex, found := strings.CutPrefix(key, LogAttributeTopicPrefix); found {
+		index, err := strconv.Atoi(rawIndex)
+		if err == nil {
+			return l.GetTopic(index)
+		}
+	}
+
+	return "", false
+}
+
+// GetTopic gets the topic at the provided index.
+func (l *Log) GetTopic(index int) (string, bool) {
+	if index < 0 || index >= len(l.Topics) {
+		return "", false
+	}
+	return l.Topics[index], true
+}
+
+// GetTopicAddress decodes the topic at the provided index as an address.
+func (l *Log) GetTopicAddress(index int) (string, error) {
+	topic, err := l.getTopicBytes(index)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(topic[12:]), nil
+}
+
+// GetTopicBigInt decodes the topic at the provided index as an unsigned integer.
+func (l *Log) GetTopicBigInt(index int) (*big.Int, error) {
+	topic, err := l.getTopicBytes(index)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(topic), nil
+}
+
+func (l *Log) getTopicBytes(index int) ([]byte, error) {
+	topic, found := l.GetTopic(index)
+	if !found {
+		return nil, fmt.Errorf("topic %d not found", index)
+	}
+
+	bz, err := hex.DecodeString(strings.TrimPrefix(topic, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decode topic %d: %w", index, err)
+	}
+	if len(bz) != 32 {
+		return nil, fmt.Errorf("invalid topic %d length: %d", index, len(bz))
+	}
+
+	return bz, nil
+}
@@ -1,23 +1,34 @@
 package main
 
 import (
+	btcrpc "github.com/milkyway-labs/flux/bitcoin/node/rpc"
 	"github.com/milkyway-labs/flux/cli"
 	"github.com/milkyway-labs/flux/cli/types"
 	"github.com/milkyway-labs/flux/cosmos/node/rpc"
+	"github.com/milkyway-labs/flux/database/inmemory"
 	"github.com/milkyway-labs/flux/database/postgresql"
+	"github.com/milkyway-labs/flux/database/sqlite"
+	evmrpc "github.com/milkyway-labs/flux/evm/node/rpc"
 	"github.com/milkyway-labs/flux/example/modules"
+	"github.com/milkyway-labs/flux/node/composite"
 )
 
 func main() {
 	ctx := types.NewCliContext("example")
 	// Database types
 	ctx.DatabasesManager.RegisterDatabase(postgresql.DatabaseType, postgresql.DatabaseBuilder)
+	ctx.DatabasesManager.RegisterDatabase(sqlite.DatabaseType, sqlite.DatabaseBuilder)
+	ctx.DatabasesManager.RegisterDatabase(inmemory.DatabaseType, inmemory.DatabaseBuilder)
 
 	// Nodes types
 	ctx.NodesManager.RegisterNode(rpc.NodeType, rpc.NodeBuilder)
+	ctx.NodesManager.RegisterNode(evmrpc.NodeType, evmrpc.NodeBuilder)
+	ctx.NodesManager.RegisterNode(btcrpc.NodeType, btcrpc.NodeBuilder)
+	ctx.NodesManager.RegisterNode(composite.NodeType, composite.NewNodeBuilder(ctx.NodesManager))
 
 	// Modules
 	ctx.ModulesManager.RegisterModule("example", modules.ExampleBlockBuilder)
+	ctx.ModulesManager.RegisterModule("transfers", modules.TransfersEventBuilder)
 
 	err := cli.NewDefaultIndexerCLI(ctx).Execute()
 	if err != nil {
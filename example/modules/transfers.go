@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"github.com/milkyway-labs/flux/cosmos/types"
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/modules/adapter"
+	"github.com/milkyway-labs/flux/node"
+	indexertypes "github.com/milkyway-labs/flux/types"
+)
+
+var _ adapter.EventHandleModule[*types.Block, *types.Tx, *types.ABCIEvent] = &TransfersModule{}
+
+// TransfersModule logs the transfer events emitted by the chain transactions.
+type TransfersModule struct {
+	logger zerolog.Logger
+}
+
+func TransfersEventBuilder(ctx context.Context, _ database.Database, _ node.Node, _ []byte) (modules.Module, error) {
+	indexerCtx := indexertypes.GetIndexerContext(ctx)
+	return adapter.NewEventHandleAdapter(&TransfersModule{
+		logger: indexerCtx.Logger.With().Str("module", "transfers").Logger(),
+	}), nil
+}
+
+// GetName implements modules.EventHandleModule.
+func (t *TransfersModule) GetName() string {
+	return "transfers"
+}
+
+// GetEventFilters implements modules.EventHandleModule.
+func (t *TransfersModule) GetEventFilters() []modules.EventFilter {
+	return []modules.EventFilter{
+		modules.NewEventTypeFilter("transfer").
+			WithAttribute("sender", "").
+			WithAttribute("recipient", "").
+			WithAttribute("amount", ""),
+	}
+}
+
+// HandleEvent implements modules.EventHandleModule.
+func (t *TransfersModule) HandleEvent(_ context.Context, block *types.Block, tx *types.Tx, event *types.ABCIEvent) error {
+	// Ignore the transfers performed outside of the transactions, like the fees distribution
+	if tx == nil {
+		return nil
+	}
+
+	from, _ := event.GetAttributeValue("sender")
+	to, _ := event.GetAttributeValue("recipient")
+	amount, _ := event.GetAttributeValue("amount")
+	t.logger.Info().
+		Uint64("height", uint64(block.GetHeight())).
+		Str("tx", tx.GetHash()).
+		Str("from", from).
+		Str("to", to).
+		Str("amount", amount).
+		Msg("got transfer event")
+
+	return nil
+}
@@ -69,6 +69,9 @@ func (b *IndexersBuilder) BuildAll(ctx context.Context, cfg *types.Config) ([]in
 		if err != nil {
 			return nil, fmt.Errorf("build node for indexer %s: %w", indexerCfg.Name, err)
 		}
+		if err := validateNode(&indexerCfg, indexerNode); err != nil {
+			return nil, fmt.Errorf("invalid node for indexer %s: %w", indexerCfg.Name, err)
+		}
 
 		// Build the indexer's modules
 		indexerModules, err := b.buildModules(ctx, cfg, indexerDB, indexerNode, &indexerCfg)
@@ -76,8 +79,14 @@ func (b *IndexersBuilder) BuildAll(ctx context.Context, cfg *types.Config) ([]in
 			return nil, fmt.Errorf("build modules for indexer %s: %w", indexerCfg.Name, err)
 		}
 
+		modulesErrorPolicies, err := getModulesErrorPolicies(cfg, &indexerCfg)
+		if err != nil {
+			return nil, fmt.Errorf("get modules error policies for indexer %s: %w", indexerCfg.Name, err)
+		}
+
 		// Build the indexer
 		indexers[i] = indexer.NewIndexer(&indexerCfg, logger, indexerDB, indexerNode, indexerModules)
+		indexers[i].WithModulesErrorPolicies(modulesErrorPolicies)
 	}
 
 	return indexers, nil
@@ -116,6 +125,9 @@ func (b *IndexersBuilder) BuildByName(ctx context.Context, cfg *types.Config, na
 	if err != nil {
 		return indexer.Indexer{}, fmt.Errorf("build node for indexer %s: %w", indexerCfg.Name, err)
 	}
+	if err := validateNode(indexerCfg, indexerNode); err != nil {
+		return indexer.Indexer{}, fmt.Errorf("invalid node for indexer %s: %w", indexerCfg.Name, err)
+	}
 
 	// Build the indexer's modules
 	indexerModules, err := b.buildModules(ctx, cfg, indexerDB, indexerNode, indexerCfg)
@@ -123,8 +135,15 @@ func (b *IndexersBuilder) BuildByName(ctx context.Context, cfg *types.Config, na
 		return indexer.Indexer{}, fmt.Errorf("build modules for indexer %s: %w", indexerCfg.Name, err)
 	}
 
+	modulesErrorPolicies, err := getModulesErrorPolicies(cfg, indexerCfg)
+	if err != nil {
+		return indexer.Indexer{}, fmt.Errorf("get modules error policies for indexer %s: %w", indexerCfg.Name, err)
+	}
+
 	// Build the indexer
-	return indexer.NewIndexer(indexerCfg, logger, indexerDB, indexerNode, indexerModules), nil
+	indexerInstance := indexer.NewIndexer(indexerCfg, logger, indexerDB, indexerNode, indexerModules)
+	indexerInstance.WithModulesErrorPolicies(modulesErrorPolicies)
+	return indexerInstance, nil
 }
 
 // WithGlobalObject adds an object that can be accessed from all the modules
@@ -164,22 +183,34 @@ func (b *IndexersBuilder) buildDatabase(
 }
 
 func (b *IndexersBuilder) buildNode(ctx context.Context, cfg *types.Config, nodeID string) (node.Node, error) {
-	nodeCfg, nodeCfgFound := cfg.Nodes[nodeID]
-	if !nodeCfgFound {
-		return nil, fmt.Errorf("node %s not found", nodeID)
-	}
+	return b.nodesManager.BuildNode(ctx, cfg, nodeID)
+}
 
-	nodeType, foundNodeType := nodeCfg["type"].(string)
-	if !foundNodeType {
-		return nil, fmt.Errorf("can't find 'type' field in node %s", nodeID)
+// validateNode ensures that the node supports the features required by
+// the indexer configuration.
+func validateNode(indexerCfg *types.IndexerConfig, indexerNode node.Node) error {
+	if indexerCfg.FinalityMode == types.FinalityModeFinalized {
+		if !node.SupportsFinalizedHeight(indexerNode) {
+			return fmt.Errorf("node %s doesn't support the `%s` finality_mode", indexerCfg.NodeID, types.FinalityModeFinalized)
+		}
 	}
 
-	rawConfig, err := yaml.Marshal(nodeCfg)
-	if err != nil {
-		return nil, fmt.Errorf("marshal node %s config", nodeID)
+	return nil
+}
+
+// getModulesErrorPolicies gets the policy used by the indexer to handle the
+// errors returned by each of its modules.
+func getModulesErrorPolicies(cfg *types.Config, indexerCfg *types.IndexerConfig) (map[string]types.ModuleErrorPolicy, error) {
+	policies := make(map[string]types.ModuleErrorPolicy, len(indexerCfg.Modules))
+	for _, moduleName := range indexerCfg.Modules {
+		policy, err := cfg.GetModuleErrorPolicy(indexerCfg, moduleName)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", moduleName, err)
+		}
+		policies[moduleName] = policy
 	}
 
-	return b.nodesManager.GetNode(ctx, nodeType, nodeID, rawConfig)
+	return policies, nil
 }
 
 func (b *IndexersBuilder) buildModules(
@@ -189,7 +220,7 @@ func (b *IndexersBuilder) buildModules(
 	node node.Node,
 	indexerCfg *types.IndexerConfig,
 ) ([]modules.Module, error) {
-	modules := make([]modules.Module, len(indexerCfg.Modules))
+	indexerModules := make([]modules.Module, len(indexerCfg.Modules))
 
 	for i, moduleName := range indexerCfg.Modules {
 		moduleCfg, foundModuleCfg := cfg.Modules[moduleName]
@@ -218,8 +249,14 @@ func (b *IndexersBuilder) buildModules(
 		if err != nil {
 			return nil, fmt.Errorf("build module `%s` for indexer `%s`: %w", moduleName, indexerCfg.Name, err)
 		}
-		modules[i] = module
+		indexerModules[i] = module
+	}
+
+	// Make sure the modules dependencies can be satisfied
+	err := modules.ValidateDependencies(indexerModules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modules dependencies for indexer `%s`: %w", indexerCfg.Name, err)
 	}
 
-	return modules, nil
+	return indexerModules, nil
 }
@@ -0,0 +1,190 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// validateModulesErrorPolicies ensures that the modules that fail the whole
+// block don't depend on modules that handle their errors on their own, since
+// the latter process the block only after it has been stored.
+func (i *Indexer) validateModulesErrorPolicies() error {
+	for _, module := range i.modules {
+		policy := i.getModuleErrorPolicy(module.GetName())
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("module %s: %w", module.GetName(), err)
+		}
+		if policy != types.ModuleErrorPolicyFail {
+			continue
+		}
+
+		for _, dependency := range modules.GetDependencies(module) {
+			dependencyPolicy := i.getModuleErrorPolicy(dependency)
+			if dependencyPolicy != types.ModuleErrorPolicyFail {
+				return fmt.Errorf("module %s with %s `%s` can't depend on module %s with %s `%s`",
+					module.GetName(), types.ModuleErrorPolicyKey, policy,
+					dependency, types.ModuleErrorPolicyKey, dependencyPolicy)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getModuleErrorPolicy gets the policy used to handle the errors returned by
+// the module with the provided name.
+func (i *Indexer) getModuleErrorPolicy(module string) types.ModuleErrorPolicy {
+	policy, found := i.modulesErrorPolicies[module]
+	if !found {
+		return types.ModuleErrorPolicyFail
+	}
+	return policy
+}
+
+// processIsolatedModules passes the provided block to the modules that handle
+// their errors on their own. Each module stores its data inside a dedicated
+// BlockTx, so that its failure doesn't affect the other modules.
+// In ordered mode the failed modules are retried in place, so that they don't
+// receive the following blocks before the provided one.
+func (w *Worker) processIsolatedModules(ctx context.Context, indexHeight IndexerHeight, b types.Block) {
+	selected := w.selectModules(indexHeight, true)
+	// Modules whose errors have already been handled while retrying them in place
+	handled := make([]bool, len(w.modules))
+	errs, _ := w.runModules(ctx, selected, false, func(ctx context.Context, index int, m modules.Module) error {
+		if !w.cfg.Ordered {
+			return w.processIsolatedModule(ctx, m, b)
+		}
+
+		handled[index] = true
+		return w.retryIsolatedModule(ctx, index, indexHeight, b)
+	})
+
+	for index, err := range errs {
+		if err != nil && !handled[index] {
+			w.handleModuleError(ctx, index, indexHeight, err)
+		}
+	}
+}
+
+// retryIsolatedModule passes the provided block to the module at the given index,
+// retrying it in place following the module's policy until it succeeds or its
+// failure is stored. Returns the error of the last attempt, if it failed.
+func (w *Worker) retryIsolatedModule(ctx context.Context, index int, indexHeight IndexerHeight, b types.Block) error {
+	module := w.modules[index]
+	moduleHeight := NewModulesIndexerHeight(indexHeight.Height, []string{module.GetName()})
+	moduleHeight.Attempts = indexHeight.Attempts
+
+	for {
+		err := w.processIsolatedModule(ctx, module, b)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		w.log.Err(err).Str("module", module.GetName()).Uint64("height", uint64(indexHeight.Height)).Msg("module failed to process block")
+
+		// Store the failure so that the block can be processed again later
+		if !w.shouldRetryModule(index, err) {
+			moduleHeight.Attempts += 1
+			w.saveFailedBlock(moduleHeight, err)
+			return err
+		}
+
+		if countsAsAttempt(err) {
+			moduleHeight.Attempts += 1
+		}
+		if w.giveUpBlock(moduleHeight, err) {
+			return err
+		}
+
+		delay := w.retryPolicy.GetDelay(moduleHeight.Attempts, err)
+		w.log.Info().
+			Str("module", module.GetName()).
+			Uint64("height", uint64(indexHeight.Height)).
+			Dur("delay", delay).
+			Msg("retry module")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// processIsolatedModule passes the provided block to the given module, storing
+// the data extracted by the module inside a dedicated BlockTx.
+func (w *Worker) processIsolatedModule(ctx context.Context, m modules.Module, b types.Block) error {
+	tx, err := w.db.BeginBlockTx(ctx)
+	if err != nil {
+		return NewModuleError(m.GetName(), fmt.Errorf("begin module %s tx: %w", m.GetName(), err))
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			w.log.Err(err).Str("module", m.GetName()).Uint64("height", uint64(b.GetHeight())).Msg("rollback module tx")
+		}
+	}()
+
+	err = w.processModule(database.InjectBlockTx(ctx, tx), m, b)
+	if err != nil {
+		return err
+	}
+
+	err = tx.SaveModuleIndexedBlock(w.cfg.Name, w.node.GetChainID(), m.GetName(), b.GetHeight())
+	if err != nil {
+		return NewModuleError(m.GetName(), fmt.Errorf("save block as processed by module %s: %w", m.GetName(), err))
+	}
+
+	err = tx.DeleteModuleFailedBlock(w.cfg.Name, w.node.GetChainID(), m.GetName(), b.GetHeight())
+	if err != nil {
+		return NewModuleError(m.GetName(), fmt.Errorf("delete module %s failure: %w", m.GetName(), err))
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewModuleError(m.GetName(), fmt.Errorf("commit module %s tx: %w", m.GetName(), err))
+	}
+
+	return nil
+}
+
+// handleModuleError handles the error returned by the module at the provided
+// index while processing the given height, following the module's policy.
+func (w *Worker) handleModuleError(ctx context.Context, index int, indexHeight IndexerHeight, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Keep the attempts so that the retries of the module stop after max_attempts
+	module := w.modules[index].GetName()
+	moduleHeight := NewModulesIndexerHeight(indexHeight.Height, []string{module})
+	moduleHeight.Attempts = indexHeight.Attempts
+	w.log.Err(err).Str("module", module).Uint64("height", uint64(indexHeight.Height)).Msg("module failed to process block")
+
+	// In ordered mode the module can't be re-enqueued, since it would receive the
+	// block after the following ones, so the failure is stored
+	if w.cfg.Ordered || !w.shouldRetryModule(index, err) {
+		// Store the failure so that the block can be processed again later
+		moduleHeight.Attempts += 1
+		w.saveFailedBlock(moduleHeight, err)
+		return
+	}
+
+	w.reEnqueueBlock(ctx, moduleHeight, err)
+}
+
+// shouldRetryModule checks if the module at the provided index must process
+// again the block for which it returned the given error, following its policy.
+func (w *Worker) shouldRetryModule(index int, err error) bool {
+	switch w.errorPolicies[index] {
+	case types.ModuleErrorPolicySkip:
+		// Retry the transient errors, since they are expected to be fixed by retrying
+		return isTransient(err)
+	case types.ModuleErrorPolicyRetryModuleOnly:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,168 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestWorkerSkipPolicy(t *testing.T) {
+	db := newTestDatabase()
+	core := newTestModule("core")
+	analytics := newTestModule("analytics")
+	analytics.fail = func(types.Height) error {
+		return fmt.Errorf("analytics error")
+	}
+	queue := NewQueue[IndexerHeight](10)
+	policies := map[string]types.ModuleErrorPolicy{analytics.GetName(): types.ModuleErrorPolicySkip}
+	stop := startTestWorkers(newTestIndexerConfig(), 2, queue, db, newTestNode(10), []modules.Module{core, analytics}, policies)
+	defer stop()
+
+	enqueueHeights(queue, 1, 3)
+
+	// The blocks are indexed, while the heights of the failing module stay missing
+	requireMissingRanges(t, db, 1, 3, nil)
+	requireModuleMissingRanges(t, db, core.GetName(), 1, 3, nil)
+	require.Eventually(t, func() bool {
+		failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+		return err == nil && len(failedBlocks) == 3
+	}, 5*time.Second, 5*time.Millisecond)
+
+	missing, err := db.GetModuleMissingRanges(testIndexerName, testChainID, analytics.GetName(), 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, []database.HeightRange{database.NewHeightRange(1, 3)}, missing)
+
+	// The data written by the failing module are discarded
+	missing, err = db.GetModuleMissingRanges(testIndexerName, testChainID, analytics.dataKey(), 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, []database.HeightRange{database.NewHeightRange(1, 3)}, missing)
+
+	failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+	require.NoError(t, err)
+	for index, failedBlock := range failedBlocks {
+		require.Equal(t, types.Height(index+1), failedBlock.Height)
+		require.Equal(t, analytics.GetName(), failedBlock.Module)
+		require.Equal(t, uint32(1), failedBlock.Attempts)
+	}
+
+	// Neither of the modules is retried
+	for height := types.Height(1); height <= 3; height++ {
+		require.Equal(t, 1, core.getHandled(height))
+		require.Equal(t, 1, analytics.getHandled(height))
+	}
+}
+
+func TestWorkerRetryModuleOnlyPolicy(t *testing.T) {
+	db := newTestDatabase()
+	core := newTestModule("core")
+	analytics := newTestModule("analytics")
+	// Fail the first two attempts of each block
+	analytics.fail = func(height types.Height) error {
+		if analytics.getHandled(height) <= 2 {
+			return fmt.Errorf("analytics error")
+		}
+		return nil
+	}
+	queue := NewQueue[IndexerHeight](10)
+	policies := map[string]types.ModuleErrorPolicy{analytics.GetName(): types.ModuleErrorPolicyRetryModuleOnly}
+	stop := startTestWorkers(newTestIndexerConfig(), 2, queue, db, newTestNode(10), []modules.Module{core, analytics}, policies)
+	defer stop()
+
+	enqueueHeights(queue, 1, 3)
+
+	requireMissingRanges(t, db, 1, 3, nil)
+	requireModuleMissingRanges(t, db, core.GetName(), 1, 3, nil)
+	requireModuleMissingRanges(t, db, analytics.GetName(), 1, 3, nil)
+
+	// Only the failing module has processed the blocks again
+	for height := types.Height(1); height <= 3; height++ {
+		require.Equal(t, 1, core.getHandled(height))
+		require.Equal(t, 3, analytics.getHandled(height))
+	}
+
+	failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+}
+
+func TestWorkerRetryModuleOnlyPolicyMaxAttempts(t *testing.T) {
+	db := newTestDatabase()
+	core := newTestModule("core")
+	analytics := newTestModule("analytics")
+	analytics.fail = func(types.Height) error {
+		return fmt.Errorf("analytics error")
+	}
+	cfg := newTestIndexerConfig()
+	queue := NewQueue[IndexerHeight](10)
+	policies := map[string]types.ModuleErrorPolicy{analytics.GetName(): types.ModuleErrorPolicyRetryModuleOnly}
+	stop := startTestWorkers(cfg, 2, queue, db, newTestNode(10), []modules.Module{core, analytics}, policies)
+	defer stop()
+
+	queue.Enqueue(NewIndexerHeight(5))
+
+	var failedBlocks []database.FailedBlock
+	require.Eventually(t, func() bool {
+		var err error
+		failedBlocks, err = db.GetFailedBlocks(testIndexerName, testChainID)
+		return err == nil && len(failedBlocks) > 0
+	}, 5*time.Second, 5*time.Millisecond)
+
+	require.Len(t, failedBlocks, 1)
+	require.Equal(t, analytics.GetName(), failedBlocks[0].Module)
+	require.Equal(t, cfg.MaxAttempts, failedBlocks[0].Attempts)
+	require.Equal(t, 1, core.getHandled(5))
+	require.Equal(t, int(cfg.MaxAttempts), analytics.getHandled(5))
+
+	block, err := db.GetIndexedBlock(testIndexerName, testChainID, 5)
+	require.NoError(t, err)
+	require.NotNil(t, block)
+}
+
+func TestOrderedIndexerRetriesModuleInPlace(t *testing.T) {
+	db := newTestDatabase()
+	core := newTestModule("core")
+	stateful := newTestModule("stateful")
+	mu := sync.Mutex{}
+	var received []types.Height
+	failures := 0
+	stateful.fail = func(height types.Height) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, height)
+		if height == 3 && failures < 2 {
+			failures++
+			return fmt.Errorf("stateful error")
+		}
+		return nil
+	}
+
+	cfg := newTestIndexerConfig()
+	cfg.Ordered = true
+	cfg.Workers = 3
+	indexer := NewIndexer(cfg, zerolog.Nop(), db, newTestNode(10), []modules.Module{core, stateful})
+	indexer.WithCustomHeightProducer(NewRangeHeightProducer(1, 5))
+	indexer.WithModulesErrorPolicies(map[string]types.ModuleErrorPolicy{stateful.GetName(): types.ModuleErrorPolicyRetryModuleOnly})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	wg := sync.WaitGroup{}
+	require.NoError(t, indexer.Start(ctx, &wg))
+	wg.Wait()
+	require.NoError(t, ctx.Err())
+
+	// The failed block is retried before the module receives the following ones
+	require.Equal(t, []types.Height{1, 2, 3, 3, 3, 4, 5}, received)
+	requireModuleMissingRanges(t, db, stateful.GetName(), 1, 5, nil)
+	failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+}
@@ -0,0 +1,23 @@
+package indexer
+
+// ModuleError represents an error returned by a module while processing a block.
+type ModuleError struct {
+	// Module is the name of the module that returned the error.
+	Module string
+	Err    error
+}
+
+func NewModuleError(module string, err error) *ModuleError {
+	return &ModuleError{
+		Module: module,
+		Err:    err,
+	}
+}
+
+func (e *ModuleError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ModuleError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,70 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// FinalityTracker represents the component used to determine which of the
+// blocks produced by a node can be considered final.
+type FinalityTracker struct {
+	node          node.Node
+	mode          types.FinalityMode
+	confirmations uint32
+}
+
+// NewFinalityTracker creates a new FinalityTracker instance.
+func NewFinalityTracker(node node.Node, mode types.FinalityMode, confirmations uint32) *FinalityTracker {
+	return &FinalityTracker{
+		node:          node,
+		mode:          mode,
+		confirmations: confirmations,
+	}
+}
+
+// GetHeights gets the current node height and the height of the latest
+// block that can be considered final.
+func (f *FinalityTracker) GetHeights(ctx context.Context) (types.Height, types.Height, error) {
+	currentHeight, err := f.node.GetCurrentHeight(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("get current node height: %w", err)
+	}
+
+	finalHeight, err := f.GetFinalHeight(ctx, currentHeight)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return currentHeight, finalHeight, nil
+}
+
+// GetFinalHeight gets the height of the latest block that can be considered
+// final given the provided current node height.
+func (f *FinalityTracker) GetFinalHeight(ctx context.Context, currentHeight types.Height) (types.Height, error) {
+	switch f.mode {
+	case types.FinalityModeFinalized:
+		provider, ok := f.node.(node.FinalizedHeightProvider)
+		if !ok || !node.SupportsFinalizedHeight(f.node) {
+			return 0, fmt.Errorf("node doesn't support the `%s` finality mode", types.FinalityModeFinalized)
+		}
+
+		finalizedHeight, err := provider.GetFinalizedHeight(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("get finalized height: %w", err)
+		}
+
+		// Prevent the finalized height to be higher than the current height in case
+		// the two values have been obtained from different nodes.
+		return min(finalizedHeight, currentHeight), nil
+
+	default:
+		confirmations := types.Height(f.confirmations)
+		if currentHeight < confirmations {
+			return 0, nil
+		}
+		return currentHeight - confirmations, nil
+	}
+}
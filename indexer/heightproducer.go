@@ -2,10 +2,14 @@ package indexer
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/rs/zerolog"
 
+	"github.com/milkyway-labs/flux/database"
 	"github.com/milkyway-labs/flux/node"
 	"github.com/milkyway-labs/flux/types"
 	"github.com/milkyway-labs/flux/utils"
@@ -16,6 +20,9 @@ import (
 type IndexerHeight struct {
 	Height   types.Height
 	Attempts uint32
+	// Modules contains the names of the modules that should process the
+	// block at this height. If empty, the block is processed by all the modules.
+	Modules []string
 }
 
 func NewIndexerHeight(height types.Height) IndexerHeight {
@@ -25,6 +32,22 @@ func NewIndexerHeight(height types.Height) IndexerHeight {
 	}
 }
 
+// NewModulesIndexerHeight creates a new IndexerHeight whose block will be
+// processed only by the modules with the provided names.
+func NewModulesIndexerHeight(height types.Height, modules []string) IndexerHeight {
+	return IndexerHeight{
+		Height:   height,
+		Attempts: 0,
+		Modules:  modules,
+	}
+}
+
+// ShouldProcess returns true if the block at this height should be processed by
+// the module with the provided name.
+func (h IndexerHeight) ShouldProcess(module string) bool {
+	return len(h.Modules) == 0 || slices.Contains(h.Modules, module)
+}
+
 // HeightProducer represents a generic component capable of
 // providing heights to be fetched and parsed by a Worker.
 type HeightProducer interface {
@@ -80,8 +103,9 @@ var _ HeightProducer = &RangeHeightProducer{}
 // RangeHeightProducer is a HeightProducer that produces all the heights
 // from the specified `from` height to the `to` height, inclusive.
 type RangeHeightProducer struct {
-	from types.Height
-	to   types.Height
+	from    types.Height
+	to      types.Height
+	modules []string
 }
 
 // NewRangeHeightProducer creates a new HeightProducer instance.
@@ -92,9 +116,16 @@ func NewRangeHeightProducer(from types.Height, to types.Height) *RangeHeightProd
 	}
 }
 
+// WithModules allows to define the names of the modules that will process
+// the produced heights. If not set, the heights are processed by all the modules.
+func (r *RangeHeightProducer) WithModules(modules []string) *RangeHeightProducer {
+	r.modules = modules
+	return r
+}
+
 func (r *RangeHeightProducer) EnqueueHeights(ctx context.Context, queue *Queue[IndexerHeight]) error {
 	for i := r.from; i <= r.to; i++ {
-		if !queue.EnqueueWithContext(ctx, NewIndexerHeight(i)) {
+		if !queue.EnqueueWithContext(ctx, NewModulesIndexerHeight(i, r.modules)) {
 			break
 		}
 	}
@@ -111,11 +142,22 @@ var _ HeightProducer = &ListHeightProducer{}
 // ListHeightProducer is a HeightProducer that produces the heights from a list
 // provided by the user.
 type ListHeightProducer struct {
-	heights []types.Height
+	heights []IndexerHeight
 }
 
 // NewListHeightProducer creates a new ListHeightProducer instance.
 func NewListHeightProducer(heights []types.Height) *ListHeightProducer {
+	indexerHeights := make([]IndexerHeight, len(heights))
+	for i, height := range heights {
+		indexerHeights[i] = NewIndexerHeight(height)
+	}
+
+	return NewIndexerHeightsListProducer(indexerHeights)
+}
+
+// NewIndexerHeightsListProducer creates a new ListHeightProducer instance that
+// produces the provided IndexerHeights.
+func NewIndexerHeightsListProducer(heights []IndexerHeight) *ListHeightProducer {
 	return &ListHeightProducer{
 		heights: heights,
 	}
@@ -123,7 +165,151 @@ func NewListHeightProducer(heights []types.Height) *ListHeightProducer {
 
 func (r *ListHeightProducer) EnqueueHeights(ctx context.Context, queue *Queue[IndexerHeight]) error {
 	for _, h := range r.heights {
-		if !queue.EnqueueWithContext(ctx, NewIndexerHeight(h)) {
+		if !queue.EnqueueWithContext(ctx, h) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// ---- Missing heights producer
+// ----------------------------------------------------------------------------
+
+var _ HeightProducer = &MissingHeightsProducer{}
+
+// MissingHeightsProducer is a HeightProducer that produces, sorted by height, the heights
+// of the blocks that have not been indexed, to be processed by all the modules, and the
+// heights of the blocks that have been indexed but not processed by some of the modules,
+// to be processed only by them.
+// The heights are produced from ranges of missing heights, so the memory used depends
+// on the number of gaps instead of the number of missing heights.
+type MissingHeightsProducer struct {
+	missing        []database.HeightRange
+	modules        []string
+	modulesMissing [][]database.HeightRange
+}
+
+// NewMissingHeightsProducer creates a new MissingHeightsProducer instance that produces
+// the heights of the provided ranges of blocks that have not been indexed.
+func NewMissingHeightsProducer(missing []database.HeightRange) *MissingHeightsProducer {
+	return &MissingHeightsProducer{
+		missing: missing,
+	}
+}
+
+// WithModuleMissing adds the ranges of the heights that have not been processed by the
+// module with the provided name. The heights that are also in the ranges of the
+// blocks that have not been indexed are processed by all the modules.
+func (m *MissingHeightsProducer) WithModuleMissing(module string, missing []database.HeightRange) *MissingHeightsProducer {
+	m.modules = append(m.modules, module)
+	m.modulesMissing = append(m.modulesMissing, missing)
+	return m
+}
+
+func (m *MissingHeightsProducer) EnqueueHeights(ctx context.Context, queue *Queue[IndexerHeight]) error {
+	// Keep an index to the current range of each list, the first list contains
+	// the blocks missing for all the modules
+	lists := append([][]database.HeightRange{m.missing}, m.modulesMissing...)
+	indexes := make([]int, len(lists))
+
+	var height types.Height
+	for {
+		// Find the lowest missing height that is not lower than the current one
+		found := false
+		next := types.MaxHeight
+		for i, list := range lists {
+			for indexes[i] < len(list) && list[indexes[i]].To < height {
+				indexes[i]++
+			}
+			if indexes[i] < len(list) {
+				found = true
+				next = min(next, max(list[indexes[i]].From, height))
+			}
+		}
+		if !found {
+			return nil
+		}
+		height = next
+
+		if !queue.EnqueueWithContext(ctx, m.getIndexerHeight(lists, indexes, height)) {
+			return nil
+		}
+
+		// Avoid the overflow when the max height has been produced
+		if height == types.MaxHeight {
+			return nil
+		}
+		height++
+	}
+}
+
+// getIndexerHeight builds the IndexerHeight of the provided missing height, including
+// the modules that have to process it.
+func (m *MissingHeightsProducer) getIndexerHeight(lists [][]database.HeightRange, indexes []int, height types.Height) IndexerHeight {
+	if indexes[0] < len(lists[0]) && lists[0][indexes[0]].From <= height {
+		return NewIndexerHeight(height)
+	}
+
+	var modules []string
+	for i, module := range m.modules {
+		list := lists[i+1]
+		if indexes[i+1] < len(list) && list[indexes[i+1]].From <= height {
+			modules = append(modules, module)
+		}
+	}
+	return NewModulesIndexerHeight(height, modules)
+}
+
+// ----------------------------------------------------------------------------
+// ---- Failed blocks height producer
+// ----------------------------------------------------------------------------
+
+var _ HeightProducer = &FailedBlocksHeightProducer{}
+
+// FailedBlocksHeightProducer is a HeightProducer that produces the heights of the
+// blocks that an indexer failed to index, as stored in the database.
+// If a block has been indexed but some modules failed to process it, the height is
+// produced to be processed only by those modules.
+type FailedBlocksHeightProducer struct {
+	db      database.Database
+	indexer string
+	chainID string
+}
+
+// NewFailedBlocksHeightProducer creates a new FailedBlocksHeightProducer instance.
+func NewFailedBlocksHeightProducer(db database.Database, indexer string, chainID string) *FailedBlocksHeightProducer {
+	return &FailedBlocksHeightProducer{
+		db:      db,
+		indexer: indexer,
+		chainID: chainID,
+	}
+}
+
+func (f *FailedBlocksHeightProducer) EnqueueHeights(ctx context.Context, queue *Queue[IndexerHeight]) error {
+	failedBlocks, err := f.db.GetFailedBlocks(f.indexer, f.chainID)
+	if err != nil {
+		return fmt.Errorf("get failed blocks: %w", err)
+	}
+
+	// Group the failures by height, since each module can fail independently
+	var heights []types.Height
+	failuresByHeight := make(map[types.Height][]database.FailedBlock)
+	for _, failedBlock := range failedBlocks {
+		if _, found := failuresByHeight[failedBlock.Height]; !found {
+			heights = append(heights, failedBlock.Height)
+		}
+		failuresByHeight[failedBlock.Height] = append(failuresByHeight[failedBlock.Height], failedBlock)
+	}
+
+	for _, height := range heights {
+		modules, err := f.getModulesToRetry(height, failuresByHeight[height])
+		if err != nil {
+			return err
+		}
+
+		if !queue.EnqueueWithContext(ctx, NewModulesIndexerHeight(height, modules)) {
 			break
 		}
 	}
@@ -131,20 +317,55 @@ func (r *ListHeightProducer) EnqueueHeights(ctx context.Context, queue *Queue[In
 	return nil
 }
 
+// getModulesToRetry returns the names of the modules that need to process again
+// the block at the provided height. If the block has not been indexed, all the
+// modules need to process it and a nil slice is returned.
+func (f *FailedBlocksHeightProducer) getModulesToRetry(height types.Height, failures []database.FailedBlock) ([]string, error) {
+	indexedBlock, err := f.db.GetIndexedBlock(f.indexer, f.chainID, height)
+	if err != nil {
+		return nil, fmt.Errorf("get indexed block %d: %w", height, err)
+	}
+	if indexedBlock == nil {
+		return nil, nil
+	}
+
+	var modules []string
+	for _, failure := range failures {
+		if failure.Module == "" {
+			return nil, nil
+		}
+		modules = append(modules, failure.Module)
+	}
+
+	return modules, nil
+}
+
 // ----------------------------------------------------------------------------
 // ---- NodeHeightProducer
 // ----------------------------------------------------------------------------
 
 var _ HeightProducer = &NodeHeightProducer{}
 
+// subscriptionRetryInterval represents the interval after which the NodeHeightProducer
+// tries again to subscribe to the new heights after a failure.
+const subscriptionRetryInterval = 10 * time.Second
+
 // NodeHeightProducer is a HeightProducer that produces heights by monitoring
 // newly produced blocks by a node after the configured height.
-// The node is monitored by querying its latest available block at the user-provided polling interval.
+// The node is monitored by querying its latest available block at the user-provided polling interval,
+// or, if the node implements the node.HeightsSubscriber interface, by subscribing to the new heights.
+// In this case the node is polled only while the subscription is not active.
+// Only the heights of the blocks that are considered final by the FinalityTracker are produced,
+// optionally the heights of the blocks that are not final yet can be
+// produced into a separate pending queue.
 type NodeHeightProducer struct {
 	logger          zerolog.Logger
 	from            types.Height
 	pollingInterval time.Duration
 	node            node.Node
+	finality        *FinalityTracker
+	pendingQueue    *Queue[types.Height]
+	subscriber      node.HeightsSubscriber
 }
 
 // NewNodeHeightProducer creates a new NodeHeightProducer instance.
@@ -164,40 +385,101 @@ func NewNodeHeightProducer(
 		node:            node,
 		pollingInterval: pollingInterval,
 		from:            from,
+		finality:        NewFinalityTracker(node, types.FinalityModeLatest, 0),
 	}
 }
 
+// WithHeightsSubscriber allows to define the node.HeightsSubscriber used to be
+// notified of the new heights instead of polling the node.
+func (n *NodeHeightProducer) WithHeightsSubscriber(subscriber node.HeightsSubscriber) *NodeHeightProducer {
+	n.subscriber = subscriber
+	return n
+}
+
+// WithFinalityTracker allows to define the FinalityTracker used to determine
+// which of the heights produced by the node are final.
+func (n *NodeHeightProducer) WithFinalityTracker(tracker *FinalityTracker) *NodeHeightProducer {
+	n.finality = tracker
+	return n
+}
+
+// WithPendingQueue allows to define a queue where will be produced the heights
+// of the blocks that have been produced by the node but are not final yet.
+func (n *NodeHeightProducer) WithPendingQueue(queue *Queue[types.Height]) *NodeHeightProducer {
+	n.pendingQueue = queue
+	return n
+}
+
 func (n *NodeHeightProducer) EnqueueHeights(ctx context.Context, queue *Queue[IndexerHeight]) error {
 	defer func() {
 		n.logger.Info().Msg("stopping node monitoring loop")
 	}()
 
 	toFetchHeight := n.from
+	pendingHeight := n.from
 	n.logger.Info().
 		Uint64("start height", uint64(toFetchHeight)).
 		Msg("start node monitoring loop")
+
+	subscriber := n.subscriber
+	var subscription <-chan types.Height
+	var lastSubscriptionAttempt time.Time
 	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			if !utils.SleepContext(ctx, n.pollingInterval) {
-				continue
+		// Subscribe to the new heights, retrying periodically if the subscription fails
+		if subscriber != nil && subscription == nil && time.Since(lastSubscriptionAttempt) >= subscriptionRetryInterval {
+			lastSubscriptionAttempt = time.Now()
+			newSubscription, err := subscriber.SubscribeHeights(ctx)
+			switch {
+			case errors.Is(err, node.ErrSubscriptionNotSupported):
+				subscriber = nil
+			case err != nil:
+				n.logger.Warn().Err(err).Msg("subscribe to new heights, falling back to polling")
+			default:
+				n.logger.Info().Msg("subscribed to new heights")
+				subscription = newSubscription
 			}
-			// Get the block from the node
-			currentNodeHeight, err := n.node.GetCurrentHeight(ctx)
-			if err != nil {
-				n.logger.Err(err).Msg("get current node height")
-				continue
+		}
+
+		var currentNodeHeight, finalizedHeight types.Height
+		var err error
+		if subscription != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case height, ok := <-subscription:
+				if !ok {
+					n.logger.Warn().Msg("heights subscription terminated, falling back to polling")
+					subscription = nil
+					continue
+				}
+				currentNodeHeight = height
+				finalizedHeight, err = n.finality.GetFinalHeight(ctx, height)
 			}
-			// Prevent enqueue of an already indexed block
-			if toFetchHeight > currentNodeHeight {
-				continue
+		} else {
+			if !utils.SleepContext(ctx, n.pollingInterval) {
+				return nil
 			}
-			for ; toFetchHeight <= currentNodeHeight; toFetchHeight++ {
-				queue.EnqueueWithContext(ctx, NewIndexerHeight(toFetchHeight))
+			// Get the current and finalized heights from the node
+			currentNodeHeight, finalizedHeight, err = n.finality.GetHeights(ctx)
+		}
+		if err != nil {
+			n.logger.Err(err).Msg("get current node height")
+			continue
+		}
+
+		// Enqueue all the heights up to the finalized one, this also fills the
+		// gaps caused by missed notifications
+		for ; toFetchHeight <= finalizedHeight; toFetchHeight++ {
+			queue.EnqueueWithContext(ctx, NewIndexerHeight(toFetchHeight))
+		}
+		// After the loop, toFetchHeight is finalizedHeight + 1 now
+
+		// Produce the heights of the blocks that are not final yet
+		if n.pendingQueue != nil {
+			pendingHeight = max(pendingHeight, toFetchHeight)
+			for ; pendingHeight <= currentNodeHeight; pendingHeight++ {
+				n.pendingQueue.EnqueueWithContext(ctx, pendingHeight)
 			}
-			// After the loop, toFetchHeight is currentNodeHeight + 1 now
 		}
 	}
 }
@@ -0,0 +1,153 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/types"
+)
+
+var (
+	_ node.Node              = &subscriberNode{}
+	_ node.HeightsSubscriber = &subscriberNode{}
+)
+
+// subscriberNode is a node that notifies the heights sent through its channel.
+type subscriberNode struct {
+	currentHeight types.Height
+	heights       chan types.Height
+}
+
+func (n *subscriberNode) GetChainID() string {
+	return "test-1"
+}
+
+func (n *subscriberNode) GetBlock(context.Context, types.Height) (types.Block, error) {
+	return nil, nil
+}
+
+func (n *subscriberNode) GetLowestHeight(context.Context) (types.Height, error) {
+	return 1, nil
+}
+
+func (n *subscriberNode) GetCurrentHeight(context.Context) (types.Height, error) {
+	return n.currentHeight, nil
+}
+
+func (n *subscriberNode) SubscribeHeights(context.Context) (<-chan types.Height, error) {
+	return n.heights, nil
+}
+
+func dequeueHeights(t *testing.T, queue *Queue[IndexerHeight], count int) []types.Height {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	heights := make([]types.Height, count)
+	for i := range heights {
+		height, ok := queue.ContextDequeue(ctx)
+		require.True(t, ok)
+		heights[i] = height.Height
+	}
+	return heights
+}
+
+func TestNodeHeightProducerSubscription(t *testing.T) {
+	testNode := &subscriberNode{currentHeight: 10, heights: make(chan types.Height)}
+	producer := NewNodeHeightProducer(zerolog.Nop(), testNode, 10*time.Millisecond, 1).
+		WithHeightsSubscriber(testNode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := NewQueue[IndexerHeight](100)
+	go func() {
+		_ = producer.EnqueueHeights(ctx, queue)
+	}()
+
+	// The notified heights are enqueued, filling the gaps
+	testNode.heights <- 2
+	require.Equal(t, []types.Height{1, 2}, dequeueHeights(t, queue, 2))
+	testNode.heights <- 5
+	require.Equal(t, []types.Height{3, 4, 5}, dequeueHeights(t, queue, 3))
+
+	// Once the subscription terminates the node is polled
+	close(testNode.heights)
+	require.Equal(t, []types.Height{6, 7, 8, 9, 10}, dequeueHeights(t, queue, 5))
+}
+
+func TestMissingHeightsProducer(t *testing.T) {
+	producer := NewMissingHeightsProducer([]database.HeightRange{{From: 2, To: 3}, {From: 8, To: 8}}).
+		WithModuleMissing("module1", []database.HeightRange{{From: 1, To: 4}}).
+		WithModuleMissing("module2", []database.HeightRange{{From: 4, To: 5}, {From: 8, To: 9}})
+
+	queue := NewQueue[IndexerHeight](100)
+	require.NoError(t, producer.EnqueueHeights(context.Background(), queue))
+	queue.Close()
+
+	expected := []IndexerHeight{
+		NewModulesIndexerHeight(1, []string{"module1"}),
+		NewIndexerHeight(2),
+		NewIndexerHeight(3),
+		NewModulesIndexerHeight(4, []string{"module1", "module2"}),
+		NewModulesIndexerHeight(5, []string{"module2"}),
+		NewIndexerHeight(8),
+		NewModulesIndexerHeight(9, []string{"module2"}),
+	}
+	for _, expectedHeight := range expected {
+		height, ok := queue.Dequeue()
+		require.True(t, ok)
+		require.Equal(t, expectedHeight, height)
+	}
+	_, ok := queue.Dequeue()
+	require.False(t, ok)
+}
+
+func TestMissingHeightsProducer_MaxHeight(t *testing.T) {
+	producer := NewMissingHeightsProducer([]database.HeightRange{{From: types.MaxHeight - 1, To: types.MaxHeight}})
+
+	queue := NewQueue[IndexerHeight](100)
+	require.NoError(t, producer.EnqueueHeights(context.Background(), queue))
+	queue.Close()
+	require.Equal(t, []types.Height{types.MaxHeight - 1, types.MaxHeight}, dequeueHeights(t, queue, 2))
+	_, ok := queue.Dequeue()
+	require.False(t, ok)
+}
+
+func TestFailedBlocksHeightProducer(t *testing.T) {
+	db := newTestDatabase()
+	testTimestamp := time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC)
+
+	// Block 3 has not been indexed, blocks 5 and 7 have been indexed but
+	// only some of the modules failed to process them
+	require.NoError(t, db.SaveFailedBlock(testIndexerName, testChainID, database.NewFailedBlock(3, "module1", "error", 3, testTimestamp)))
+	for _, height := range []types.Height{5, 7} {
+		require.NoError(t, db.SaveIndexedBlock(testIndexerName, testChainID, database.NewIndexedBlock(height, "", "", testTimestamp)))
+	}
+	require.NoError(t, db.SaveFailedBlock(testIndexerName, testChainID, database.NewFailedBlock(5, "module1", "error", 3, testTimestamp)))
+	require.NoError(t, db.SaveFailedBlock(testIndexerName, testChainID, database.NewFailedBlock(5, "module2", "error", 3, testTimestamp)))
+	require.NoError(t, db.SaveFailedBlock(testIndexerName, testChainID, database.NewFailedBlock(7, "", "error", 3, testTimestamp)))
+	require.NoError(t, db.SaveFailedBlock(testIndexerName, testChainID, database.NewFailedBlock(7, "module1", "error", 3, testTimestamp)))
+
+	queue := NewQueue[IndexerHeight](100)
+	producer := NewFailedBlocksHeightProducer(db, testIndexerName, testChainID)
+	require.NoError(t, producer.EnqueueHeights(context.Background(), queue))
+	queue.Close()
+
+	expected := []IndexerHeight{
+		NewIndexerHeight(3),
+		NewModulesIndexerHeight(5, []string{"module1", "module2"}),
+		NewIndexerHeight(7),
+	}
+	for _, expectedHeight := range expected {
+		height, ok := queue.Dequeue()
+		require.True(t, ok)
+		require.Equal(t, expectedHeight, height)
+	}
+	_, ok := queue.Dequeue()
+	require.False(t, ok)
+}
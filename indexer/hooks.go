@@ -0,0 +1,68 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/milkyway-labs/flux/modules"
+)
+
+// hooksLoop runs the modules' periodic hooks while the indexer is running and
+// calls the modules' stop hooks once all the indexer's goroutines, tracked
+// by indexerWg, have terminated.
+func (i *Indexer) hooksLoop(ctx context.Context, wg *sync.WaitGroup, indexerWg *sync.WaitGroup) {
+	defer wg.Done()
+
+	// Stop the periodic hooks once the indexer has stopped processing blocks,
+	// this can happen also without canceling the context, e.g. when parsing a range.
+	periodicCtx, stopPeriodic := context.WithCancel(ctx)
+	defer stopPeriodic()
+
+	periodicWg := &sync.WaitGroup{}
+	for _, module := range i.modules {
+		if periodicHook, ok := modules.As[modules.PeriodicHook](module); ok && periodicHook.GetTickInterval() > 0 {
+			periodicWg.Add(1)
+			go i.periodicHookLoop(periodicCtx, periodicWg, module.GetName(), periodicHook)
+		}
+	}
+
+	indexerWg.Wait()
+	stopPeriodic()
+	periodicWg.Wait()
+
+	// Call the stop hooks with a context that is not canceled, so that
+	// the modules can flush their data
+	stopCtx := context.WithoutCancel(ctx)
+	for _, module := range i.modules {
+		if stopHook, ok := modules.As[modules.IndexerStopHook](module); ok {
+			err := stopHook.OnIndexerStop(stopCtx)
+			if err != nil {
+				i.log.Err(err).Str("module", module.GetName()).Msg("stop module")
+			}
+		}
+	}
+
+	i.log.Info().Msg("indexer stopped")
+}
+
+// periodicHookLoop calls the provided hook every tick interval until
+// the context is canceled.
+func (i *Indexer) periodicHookLoop(ctx context.Context, wg *sync.WaitGroup, moduleName string, hook modules.PeriodicHook) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(hook.GetTickInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := hook.OnTick(ctx)
+			if err != nil {
+				i.log.Err(err).Str("module", moduleName).Msg("module periodic hook")
+			}
+		}
+	}
+}
@@ -30,12 +30,24 @@ type Indexer struct {
 	// blocks to index.
 	heightsQueue *Queue[IndexerHeight]
 
+	// Queue used to retrieve the height of the blocks that are not
+	// final yet.
+	pendingQueue *Queue[types.Height]
+
 	// List of modules that will be used by the indexer to index data from
 	// the chain.
 	modules []modules.Module
 
 	// Instance of HeightProducer that will provide the blocks to parse.
 	heightProducer HeightProducer
+
+	// Policy used to handle the errors returned by each module, the modules
+	// without a policy fail the whole block.
+	modulesErrorPolicies map[string]types.ModuleErrorPolicy
+
+	// Lock used by the workers to serialize the handling of the
+	// chain reorganizations.
+	reorgLock *sync.Mutex
 }
 
 func NewIndexer(
@@ -55,7 +67,9 @@ func NewIndexer(
 		db:           db,
 		node:         node,
 		heightsQueue: NewQueue[IndexerHeight](cfg.HeightQueueSize),
+		pendingQueue: NewQueue[types.Height](cfg.HeightQueueSize),
 		modules:      modules,
+		reorgLock:    &sync.Mutex{},
 	}
 }
 
@@ -64,6 +78,16 @@ func (i *Indexer) GetName() string {
 	return i.cfg.Name
 }
 
+// GetChainID gets the ID of the chain indexed by the indexer.
+func (i *Indexer) GetChainID() string {
+	return i.node.GetChainID()
+}
+
+// GetDatabase gets the database used by the indexer to store its state.
+func (i *Indexer) GetDatabase() database.Database {
+	return i.db
+}
+
 // IsDisabled returns true if the indexer is disabled.
 func (i *Indexer) IsDisabled() bool {
 	return i.cfg.Disabled
@@ -71,30 +95,67 @@ func (i *Indexer) IsDisabled() bool {
 
 // Start starts the indexer.
 func (i *Indexer) Start(ctx context.Context, wg *sync.WaitGroup) error {
+	// Make sure the modules can be executed following their dependencies
+	err := modules.ValidateDependencies(i.modules)
+	if err != nil {
+		return fmt.Errorf("invalid modules dependencies: %w", err)
+	}
+	err = i.validateModulesErrorPolicies()
+	if err != nil {
+		return fmt.Errorf("invalid modules error policies: %w", err)
+	}
+
+	// WaitGroup used to track the indexer's goroutines, the stop hooks are
+	// called only once all of them have terminated
+	indexerWg := &sync.WaitGroup{}
+
 	heightProducer := i.heightProducer
 
 	// If we don't have a height producer, we build the default one.
 	if heightProducer == nil {
-		producer, err := i.buildDefaultHeightProducer(ctx)
+		// Get the modules that want to receive the not yet final blocks
+		var pendingModules []modules.PendingBlockHandleModule
+		for _, module := range i.modules {
+			if pendingModule, ok := modules.As[modules.PendingBlockHandleModule](module); ok {
+				pendingModules = append(pendingModules, pendingModule)
+			}
+		}
+
+		var pendingQueue *Queue[types.Height]
+		if len(pendingModules) > 0 {
+			pendingQueue = i.pendingQueue
+		}
+
+		producer, err := i.buildDefaultHeightProducer(ctx, pendingQueue)
 		if err != nil {
 			return fmt.Errorf("build default height producer: %w", err)
 		}
 		heightProducer = producer
+
+		// Start the loop that delivers the pending blocks to the modules
+		if len(pendingModules) > 0 {
+			indexerWg.Add(1)
+			go i.pendingBlocksLoop(ctx, indexerWg, pendingModules)
+		}
 	}
 
 	// Start the worker that produces the heights to be fetched by the workers.
-	wg.Add(1)
-	go i.enqueueHeightsLoop(ctx, wg, heightProducer)
+	indexerWg.Add(1)
+	go i.enqueueHeightsLoop(ctx, indexerWg, heightProducer)
 
 	// Starts the indexing workers
-	for index := int64(0); index < int64(i.cfg.Workers); index++ {
-		worker := NewWorker(i.cfg, i.log, i.heightsQueue, i.db, i.node, i.modules)
-		worker.Start(ctx, wg)
+	if i.cfg.Ordered {
+		i.startOrderedWorkers(ctx, indexerWg)
+	} else {
+		for index := int64(0); index < int64(i.cfg.Workers); index++ {
+			worker := NewWorker(i.cfg, i.log, i.heightsQueue, i.db, i.node, i.modules, i.modulesErrorPolicies, i.reorgLock)
+			worker.Start(ctx, indexerWg)
+		}
 	}
 
 	// Call the module's start hook
 	for _, module := range i.modules {
-		if moduleStartHook, ok := module.(modules.IndexerStartHook); ok {
+		if moduleStartHook, ok := modules.As[modules.IndexerStartHook](module); ok {
 			err := moduleStartHook.OnIndexerStart(ctx)
 			if err != nil {
 				return fmt.Errorf("start module %s: %w", module.GetName(), err)
@@ -102,6 +163,10 @@ func (i *Indexer) Start(ctx context.Context, wg *sync.WaitGroup) error {
 		}
 	}
 
+	// Start the periodic hooks and call the stop hooks once the indexer terminates
+	wg.Add(1)
+	go i.hooksLoop(ctx, wg, indexerWg)
+
 	return nil
 }
 
@@ -112,6 +177,13 @@ func (i *Indexer) WithCustomHeightProducer(producer HeightProducer) *Indexer {
 	return i
 }
 
+// WithModulesErrorPolicies allows to define the policy used to handle the errors
+// returned by each module.
+func (i *Indexer) WithModulesErrorPolicies(policies map[string]types.ModuleErrorPolicy) *Indexer {
+	i.modulesErrorPolicies = policies
+	return i
+}
+
 // GetModule returns the module with the given name.
 // If the module is not found, an error is returned.
 func (i *Indexer) GetModule(moduleName string) (modules.Module, error) {
@@ -127,9 +199,14 @@ func (i *Indexer) GetModule(moduleName string) (modules.Module, error) {
 
 // buildDefaultHeightProducer builds the default height producer, this producer
 // will produce the height of the un-indexed blocks from first indexed block or the configured start height
-// in case is the first start to the current node height and starts monitor the node for new blocks.
-func (i *Indexer) buildDefaultHeightProducer(ctx context.Context) (HeightProducer, error) {
-	currentNodeHeight, err := i.node.GetCurrentHeight(ctx)
+// in case is the first start to the current finalized node height and starts monitor the node for new blocks.
+// If pendingQueue is not nil, the heights of the blocks that are not final yet are
+// enqueued into it.
+func (i *Indexer) buildDefaultHeightProducer(ctx context.Context, pendingQueue *Queue[types.Height]) (HeightProducer, error) {
+	finalityTracker := NewFinalityTracker(i.node, i.cfg.FinalityMode, i.cfg.Confirmations)
+
+	// Consider only the final blocks as the current node height
+	_, currentNodeHeight, err := finalityTracker.GetHeights(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get current node height: %w", err)
 	}
@@ -149,7 +226,7 @@ func (i *Indexer) buildDefaultHeightProducer(ctx context.Context) (HeightProduce
 		// height check the missing block from this height
 		if lowestAvailableBlock != nil && *lowestAvailableBlock < currentNodeHeight {
 			missingBlockStartHeight = *lowestAvailableBlock
-		} else {
+		} else if currentNodeHeight > 0 {
 			// We don't have any indexed block or the current node height is lower
 			// than the lowest indexed block, which is wired. In those cases
 			// start looking for un-indexed block from the current node height - 1.
@@ -157,26 +234,87 @@ func (i *Indexer) buildDefaultHeightProducer(ctx context.Context) (HeightProduce
 		}
 	}
 
-	var missingBlocks []types.Height
+	err = i.initModulesProgress()
+	if err != nil {
+		return nil, err
+	}
+
+	var missingBlocksProducer HeightProducer
 	if i.cfg.ForceReparseOldBlocks && i.cfg.StartHeight != nil {
-		for height := *i.cfg.StartHeight; height <= currentNodeHeight; height++ {
-			missingBlocks = append(missingBlocks, height)
-		}
-	} else {
+		missingBlocksProducer = NewRangeHeightProducer(*i.cfg.StartHeight, currentNodeHeight)
+	} else if currentNodeHeight > 0 {
 		// Get the blocks that are missing and we need to index
-		missingBlocksFromDB, err := i.db.GetMissingBlocks(i.GetName(), i.node.GetChainID(), missingBlockStartHeight, currentNodeHeight-1)
+		missingBlocksProducer, err = i.buildMissingHeightsProducer(missingBlockStartHeight, currentNodeHeight-1)
 		if err != nil {
 			return nil, fmt.Errorf("get missing blocks: %w", err)
 		}
-		missingBlocks = missingBlocksFromDB
+	} else {
+		// None of the blocks is final yet, they will be produced
+		// by the node height producer once they become final
+		missingBlocksProducer = NewMissingHeightsProducer(nil)
+	}
+
+	nodeHeightProducer := NewNodeHeightProducer(i.log, i.node, i.cfg.NodePollingInterval, currentNodeHeight).
+		WithFinalityTracker(finalityTracker)
+	if pendingQueue != nil {
+		nodeHeightProducer.WithPendingQueue(pendingQueue)
+	}
+	if subscriber, ok := i.node.(node.HeightsSubscriber); ok {
+		nodeHeightProducer.WithHeightsSubscriber(subscriber)
 	}
 
 	return NewCombinedHeightProducer(
-		NewListHeightProducer(missingBlocks),
-		NewNodeHeightProducer(i.log, i.node, i.cfg.NodePollingInterval, currentNodeHeight),
+		missingBlocksProducer,
+		nodeHeightProducer,
 	), nil
 }
 
+// initModulesProgress stores the blocks indexed before the progress of each module
+// was tracked as processed by all the modules, otherwise they would be considered
+// missing and processed again by all the modules.
+func (i *Indexer) initModulesProgress() error {
+	moduleNames := make([]string, len(i.modules))
+	for index, module := range i.modules {
+		moduleNames[index] = module.GetName()
+	}
+
+	initialized, err := i.db.InitModulesProgress(i.GetName(), i.node.GetChainID(), moduleNames)
+	if err != nil {
+		return fmt.Errorf("init modules progress: %w", err)
+	}
+	if initialized {
+		i.log.Info().Strs("modules", moduleNames).Msg("stored the indexed blocks as processed by the modules")
+	}
+
+	return nil
+}
+
+// buildMissingHeightsProducer builds the producer of the heights in the provided range that
+// need to be indexed, along with the modules that still have to process them.
+func (i *Indexer) buildMissingHeightsProducer(from types.Height, to types.Height) (*MissingHeightsProducer, error) {
+	chainID := i.node.GetChainID()
+
+	// Get the blocks that have not been indexed, those must be processed
+	// by all the modules
+	missingRanges, err := i.db.GetMissingRanges(i.GetName(), chainID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	producer := NewMissingHeightsProducer(missingRanges)
+
+	// Get the blocks that have been indexed but have not been processed by
+	// some of the modules, this happens when a module is added to an existing indexer.
+	for _, module := range i.modules {
+		moduleMissingRanges, err := i.db.GetModuleMissingRanges(i.GetName(), chainID, module.GetName(), from, to)
+		if err != nil {
+			return nil, fmt.Errorf("get module %s missing blocks: %w", module.GetName(), err)
+		}
+		producer.WithModuleMissing(module.GetName(), moduleMissingRanges)
+	}
+
+	return producer, nil
+}
+
 func (i *Indexer) enqueueHeightsLoop(
 	ctx context.Context,
 	wg *sync.WaitGroup,
@@ -184,8 +322,12 @@ func (i *Indexer) enqueueHeightsLoop(
 ) {
 	defer func() {
 		i.heightsQueue.Close()
+		i.pendingQueue.Close()
 		wg.Done()
 	}()
 
-	heightProducer.EnqueueHeights(ctx, i.heightsQueue)
+	err := heightProducer.EnqueueHeights(ctx, i.heightsQueue)
+	if err != nil {
+		i.log.Err(err).Msg("enqueue heights")
+	}
 }
@@ -0,0 +1,189 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// indexMissingHeights indexes, using two workers, the heights produced by the
+// missing heights producer of the indexer in the [from, to] range.
+func indexMissingHeights(t *testing.T, indexer *Indexer, from types.Height, to types.Height) {
+	producer, err := indexer.buildMissingHeightsProducer(from, to)
+	require.NoError(t, err)
+
+	queue := NewQueue[IndexerHeight](100)
+	require.NoError(t, producer.EnqueueHeights(context.Background(), queue))
+	stop := startTestWorkers(indexer.cfg, 2, queue, indexer.db, indexer.node, indexer.modules, nil)
+	defer stop()
+
+	// Wait until all the heights have been processed
+	for _, module := range indexer.modules {
+		requireModuleMissingRanges(t, indexer.db, module.GetName(), from, to, nil)
+	}
+}
+
+func TestIndexerBackfillsNewModule(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	first := newTestModule("first")
+	second := newTestModule("second")
+
+	// Index the blocks with the first module only
+	oldIndexer := NewIndexer(newTestIndexerConfig(), zerolog.Nop(), db, testNode, []modules.Module{first})
+	indexMissingHeights(t, &oldIndexer, 1, 5)
+
+	// Add the second module, the blocks must be processed only by it
+	indexer := NewIndexer(newTestIndexerConfig(), zerolog.Nop(), db, testNode, []modules.Module{first, second})
+	require.NoError(t, indexer.initModulesProgress())
+	indexMissingHeights(t, &indexer, 1, 6)
+
+	for height := types.Height(1); height <= 5; height++ {
+		require.Equal(t, 1, first.getHandled(height))
+		require.Equal(t, 1, second.getHandled(height))
+	}
+	require.Equal(t, 1, first.getHandled(6))
+	require.Equal(t, 1, second.getHandled(6))
+}
+
+func TestIndexerInitModulesProgressFromIndexedBlocks(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	first := newTestModule("first")
+	second := newTestModule("second")
+
+	// Store the blocks as indexed without tracking the modules progress,
+	// like the indexers did before the progress was tracked
+	for height := types.Height(1); height <= 5; height++ {
+		block, err := testNode.GetBlock(context.Background(), height)
+		require.NoError(t, err)
+		require.NoError(t, db.SaveIndexedBlock(testIndexerName, testChainID,
+			database.NewIndexedBlock(height, block.GetHash(), block.GetParentHash(), time.Unix(int64(height), 0))))
+	}
+
+	// The indexed blocks must not be processed again by the modules
+	indexer := NewIndexer(newTestIndexerConfig(), zerolog.Nop(), db, testNode, []modules.Module{first, second})
+	require.NoError(t, indexer.initModulesProgress())
+	indexMissingHeights(t, &indexer, 1, 6)
+
+	for height := types.Height(1); height <= 5; height++ {
+		require.Equal(t, 0, first.getHandled(height))
+		require.Equal(t, 0, second.getHandled(height))
+	}
+	require.Equal(t, 1, first.getHandled(6))
+	require.Equal(t, 1, second.getHandled(6))
+
+	// Once the modules have a progress, the new modules are backfilled
+	third := newTestModule("third")
+	indexer = NewIndexer(newTestIndexerConfig(), zerolog.Nop(), db, testNode, []modules.Module{first, second, third})
+	require.NoError(t, indexer.initModulesProgress())
+	indexMissingHeights(t, &indexer, 1, 6)
+	for height := types.Height(1); height <= 6; height++ {
+		require.Equal(t, 1, third.getHandled(height))
+	}
+}
+
+func TestIndexerRetriesFailedBlocks(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	first := newTestModule("first")
+	second := newTestModule("second")
+	testTimestamp := time.Date(2021, 11, 22, 14, 0, 0, 0, time.UTC)
+
+	// The block 5 has not been indexed, while the block 7 has been indexed
+	// but the second module failed to process it
+	require.NoError(t, db.SaveFailedBlock(testIndexerName, testChainID,
+		database.NewFailedBlock(5, "", "fetch error", 3, testTimestamp)))
+	worker := newTestWorker(NewQueue[IndexerHeight](10), db, testNode, []modules.Module{first, second}, nil)
+	require.NoError(t, worker.fetchAndProcessBlock(context.Background(), NewModulesIndexerHeight(7, []string{first.GetName()})))
+	require.NoError(t, db.SaveFailedBlock(testIndexerName, testChainID,
+		database.NewFailedBlock(7, second.GetName(), "module error", 3, testTimestamp)))
+
+	// Retry the failed blocks like the parse failed command
+	cfg := newTestIndexerConfig()
+	cfg.Workers = 2
+	indexer := NewIndexer(cfg, zerolog.Nop(), db, testNode, []modules.Module{first, second})
+	indexer.WithCustomHeightProducer(NewFailedBlocksHeightProducer(db, testIndexerName, testChainID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	wg := sync.WaitGroup{}
+	require.NoError(t, indexer.Start(ctx, &wg))
+	wg.Wait()
+	require.NoError(t, ctx.Err())
+
+	// The failures are removed once the blocks have been processed
+	failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+
+	require.Equal(t, 1, first.getHandled(5))
+	require.Equal(t, 1, second.getHandled(5))
+	require.Equal(t, 1, first.getHandled(7))
+	require.Equal(t, 1, second.getHandled(7))
+	for _, module := range []string{first.GetName(), second.GetName()} {
+		missing, err := db.GetModuleMissingRanges(testIndexerName, testChainID, module, 5, 7)
+		require.NoError(t, err)
+		require.Equal(t, []database.HeightRange{database.NewHeightRange(6, 6)}, missing)
+	}
+}
+
+func TestIndexerFailedBlockIsClearedAfterSuccess(t *testing.T) {
+	db := newTestDatabase()
+	module := newTestModule("module")
+	failing := atomic.Bool{}
+	failing.Store(true)
+	module.fail = func(types.Height) error {
+		if failing.Load() {
+			return fmt.Errorf("module error")
+		}
+		return nil
+	}
+
+	// Exhaust the attempts of the block
+	cfg := newTestIndexerConfig()
+	queue := NewQueue[IndexerHeight](10)
+	stop := startTestWorkers(cfg, 1, queue, db, newTestNode(10), []modules.Module{module}, nil)
+	queue.Enqueue(NewIndexerHeight(5))
+	require.Eventually(t, func() bool {
+		failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+		return err == nil && len(failedBlocks) == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	stop()
+
+	// Index the block successfully, the failure must be removed along with the block being stored
+	failing.Store(false)
+	worker := newTestWorker(NewQueue[IndexerHeight](10), db, newTestNode(10), []modules.Module{module}, nil)
+	require.NoError(t, worker.fetchAndProcessBlock(context.Background(), NewIndexerHeight(5)))
+
+	failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+	require.NoError(t, err)
+	require.Empty(t, failedBlocks)
+	block, err := db.GetIndexedBlock(testIndexerName, testChainID, 5)
+	require.NoError(t, err)
+	require.NotNil(t, block)
+}
+
+func TestIndexerNoFinalBlocks(t *testing.T) {
+	cfg := newTestIndexerConfig()
+	cfg.Confirmations = 10
+
+	// The chain is shorter than the required confirmations, so none of its blocks is final
+	indexer := NewIndexer(cfg, zerolog.Nop(), newTestDatabase(), newTestNode(3), nil)
+	producer, err := indexer.buildDefaultHeightProducer(context.Background(), nil)
+	require.NoError(t, err)
+
+	missingProducer, ok := producer.(*CombinedHeightProducer).producers[0].(*MissingHeightsProducer)
+	require.True(t, ok)
+	require.Empty(t, missingProducer.missing)
+}
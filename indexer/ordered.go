@@ -0,0 +1,219 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/milkyway-labs/flux/prometheus"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// sequencedHeight represents a height to fetch along with the position
+// reserved for its block inside the ReorderBuffer.
+type sequencedHeight struct {
+	IndexerHeight
+	seq uint64
+}
+
+// fetchedBlock represents the result of fetching the block at a given height.
+type fetchedBlock struct {
+	indexHeight IndexerHeight
+	block       types.Block
+	err         error
+}
+
+// startOrderedWorkers starts the workers used when the indexer is configured
+// to deliver the blocks to the modules strictly ordered by height.
+// The blocks are fetched in parallel by the configured number of workers,
+// then a single worker passes them to the modules following the order in
+// which their heights have been produced.
+func (i *Indexer) startOrderedWorkers(ctx context.Context, wg *sync.WaitGroup) {
+	fetchQueue := NewQueue[sequencedHeight](i.cfg.HeightQueueSize)
+	buffer := NewReorderBuffer[fetchedBlock](i.cfg.HeightQueueSize)
+
+	// Start the loop that assigns to each height its position
+	wg.Add(1)
+	go i.sequenceHeightsLoop(ctx, wg, fetchQueue, buffer)
+
+	// Start the workers that fetch the blocks
+	fetchersWg := &sync.WaitGroup{}
+	for index := int64(0); index < int64(i.cfg.Workers); index++ {
+		worker := NewWorker(i.cfg, i.log, i.heightsQueue, i.db, i.node, i.modules, i.modulesErrorPolicies, i.reorgLock)
+		fetchersWg.Add(1)
+		go worker.fetchLoop(ctx, fetchersWg, fetchQueue, buffer)
+	}
+
+	// Close the buffer once all the fetched blocks have been pushed
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fetchersWg.Wait()
+		buffer.Close()
+	}()
+
+	// Start the worker that processes the blocks
+	worker := NewWorker(i.cfg, i.log, i.heightsQueue, i.db, i.node, i.modules, i.modulesErrorPolicies, i.reorgLock)
+	wg.Add(1)
+	go worker.processOrderedLoop(ctx, wg, buffer)
+}
+
+// sequenceHeightsLoop reserves a position inside the buffer for each produced
+// height and enqueues it to be fetched by the workers.
+func (i *Indexer) sequenceHeightsLoop(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	fetchQueue *Queue[sequencedHeight],
+	buffer *ReorderBuffer[fetchedBlock],
+) {
+	defer func() {
+		fetchQueue.Close()
+		wg.Done()
+	}()
+
+	for {
+		indexHeight, ok := i.heightsQueue.ContextDequeue(ctx)
+		if !ok {
+			return
+		}
+
+		seq, ok := buffer.Reserve(ctx)
+		if !ok {
+			return
+		}
+
+		if !fetchQueue.EnqueueWithContext(ctx, sequencedHeight{IndexerHeight: indexHeight, seq: seq}) {
+			return
+		}
+	}
+}
+
+// fetchLoop fetches the blocks at the heights read from the fetchQueue and
+// pushes them inside the buffer, at the position reserved for them.
+func (w *Worker) fetchLoop(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	fetchQueue *Queue[sequencedHeight],
+	buffer *ReorderBuffer[fetchedBlock],
+) {
+	defer func() {
+		wg.Done()
+		prometheus.WorkersCount.WithLabelValues(w.cfg.Name).Dec()
+		w.log.Info().Msg("stopping fetch loop")
+	}()
+	w.log.Info().Msg("started fetch worker")
+	prometheus.WorkersCount.WithLabelValues(w.cfg.Name).Inc()
+
+	for {
+		height, ok := fetchQueue.ContextDequeue(ctx)
+		if !ok {
+			return
+		}
+
+		// Push the block also in case of error, the processing worker
+		// will take care of retrying to fetch it.
+		block, err := w.fetchBlock(ctx, height.Height)
+		buffer.Push(height.seq, fetchedBlock{
+			indexHeight: height.IndexerHeight,
+			block:       block,
+			err:         err,
+		})
+	}
+}
+
+// processOrderedLoop passes the blocks to the modules in the order
+// in which they are popped from the buffer.
+func (w *Worker) processOrderedLoop(ctx context.Context, wg *sync.WaitGroup, buffer *ReorderBuffer[fetchedBlock]) {
+	defer func() {
+		wg.Done()
+		w.log.Info().Msg("stopping ordered indexing loop")
+	}()
+	w.log.Info().Msg("started ordered worker")
+
+	for {
+		fetched, ok := buffer.Pop(ctx)
+		if !ok {
+			return
+		}
+
+		w.processOrderedBlock(ctx, fetched.indexHeight, fetched.block, fetched.err)
+	}
+}
+
+// processOrderedBlock indexes the provided block. In case of failure the block is
+// retried in place, so that the following blocks are not processed before it.
+// The provided fetchErr is the error returned while fetching the block, if any.
+func (w *Worker) processOrderedBlock(ctx context.Context, indexHeight IndexerHeight, block types.Block, fetchErr error) {
+	// Heights above the block rolled back by a reorganization, indexed again
+	// once the block has been processed to preserve the ordering
+	var rolledBackHeights []types.Height
+	defer func() {
+		for _, h := range rolledBackHeights {
+			if ctx.Err() != nil {
+				return
+			}
+			rolledBackBlock, err := w.fetchBlock(ctx, h)
+			w.processOrderedBlock(ctx, NewIndexerHeight(h), rolledBackBlock, err)
+		}
+	}()
+
+	err := fetchErr
+	for {
+		if err == nil {
+			var heights []types.Height
+			heights, err = w.indexOrderedBlock(ctx, indexHeight, block)
+			rolledBackHeights = append(rolledBackHeights, heights...)
+			if err == nil {
+				return
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.log.Err(err).Uint64("height", uint64(indexHeight.Height)).Msg("get and process block")
+		if countsAsAttempt(err) {
+			indexHeight.Attempts += 1
+		}
+		if w.giveUpBlock(indexHeight, err) {
+			return
+		}
+
+		delay := w.retryPolicy.GetDelay(indexHeight.Attempts, err)
+		w.log.Info().
+			Uint64("height", uint64(indexHeight.Height)).
+			Dur("delay", delay).
+			Msg("retry block")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		block, err = w.fetchBlock(ctx, indexHeight.Height)
+	}
+}
+
+// indexOrderedBlock indexes the provided block, in case of chain reorganization
+// the canonical branch is indexed again before the block to preserve the ordering.
+// Returns the heights above the block that have been rolled back, which the caller
+// must index again after the block.
+func (w *Worker) indexOrderedBlock(ctx context.Context, indexHeight IndexerHeight, block types.Block) ([]types.Height, error) {
+	height := indexHeight.Height
+
+	// Make sure the block is consistent with the chain indexed so far
+	forkHeight, rolledBackHeights, err := w.handleReorg(ctx, block)
+	if err != nil {
+		return nil, fmt.Errorf("handle reorg at block: %d, err: %w", height, err)
+	}
+
+	// Re-index the canonical branch
+	for h := forkHeight; h < height; h++ {
+		canonicalBlock, err := w.fetchBlock(ctx, h)
+		w.processOrderedBlock(ctx, NewIndexerHeight(h), canonicalBlock, err)
+	}
+
+	return rolledBackHeights, w.indexBlock(ctx, indexHeight, block)
+}
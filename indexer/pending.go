@@ -0,0 +1,43 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/milkyway-labs/flux/modules"
+)
+
+// pendingBlocksLoop fetches the blocks that are not final yet and
+// delivers them to the modules that implement the
+// modules.PendingBlockHandleModule interface.
+// Since pending blocks are delivered on a best-effort basis, failures are
+// only logged and the blocks are not retried.
+func (i *Indexer) pendingBlocksLoop(ctx context.Context, wg *sync.WaitGroup, pendingModules []modules.PendingBlockHandleModule) {
+	defer func() {
+		wg.Done()
+		i.log.Info().Msg("stopping pending blocks loop")
+	}()
+
+	for {
+		height, ok := i.pendingQueue.ContextDequeue(ctx)
+		if !ok {
+			return
+		}
+
+		block, err := i.node.GetBlock(ctx, height)
+		if err != nil {
+			i.log.Err(err).Uint64("height", uint64(height)).Msg("fetch pending block")
+			continue
+		}
+
+		for _, module := range pendingModules {
+			err := module.HandlePendingBlock(ctx, block)
+			if err != nil {
+				i.log.Err(err).
+					Uint64("height", uint64(height)).
+					Str("module", module.GetName()).
+					Msg("handle pending block")
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"context"
+	"sync"
 	"time"
 )
 
@@ -9,6 +10,16 @@ import (
 // objects between goroutines.
 type Queue[T any] struct {
 	channel chan T
+
+	// mu guards the fields used to delay the closing of the channel
+	// until all the asynchronous enqueues have terminated.
+	mu sync.Mutex
+	// Number of asynchronous enqueues that have not terminated yet.
+	pending int
+	// closing tells if Close has been called.
+	closing bool
+	// closed tells if the channel has been closed.
+	closed bool
 }
 
 // NewQueue creates and returns a new buffered queue with the specified size.
@@ -38,8 +49,15 @@ func (q *Queue[T]) EnqueueWithContext(ctx context.Context, value T) bool {
 
 // DelayedEnqueue schedules the insertion of a value into the queue after the specified delay.
 // If the context is canceled before the delay elapses, the enqueue operation is aborted.
+// If the queue has already been closed, the value is discarded.
 func (q *Queue[T]) DelayedEnqueue(ctx context.Context, delay time.Duration, value T) {
+	if !q.beginAsync() {
+		return
+	}
+
 	go func() {
+		defer q.endAsync()
+
 		select {
 		case <-ctx.Done():
 			return
@@ -49,6 +67,27 @@ func (q *Queue[T]) DelayedEnqueue(ctx context.Context, delay time.Duration, valu
 	}()
 }
 
+// EnqueueAllAsync inserts the provided values into the queue from a separate goroutine,
+// preserving their order. This allows a consumer of the queue to enqueue values without
+// the risk of blocking forever in case the queue is full.
+// If the context is canceled, the values that have not been enqueued yet are discarded.
+// If the queue has already been closed, the values are discarded.
+func (q *Queue[T]) EnqueueAllAsync(ctx context.Context, values []T) {
+	if !q.beginAsync() {
+		return
+	}
+
+	go func() {
+		defer q.endAsync()
+
+		for _, value := range values {
+			if !q.EnqueueWithContext(ctx, value) {
+				return
+			}
+		}
+	}()
+}
+
 // Dequeue removes and returns a value from the queue.
 // If the queue is empty, this call will block until a value is available or the queue is closed.
 // Returns (value, true) if successful, or (zero, false) if the queue has been closed and emptied.
@@ -71,7 +110,46 @@ func (q *Queue[T]) ContextDequeue(ctx context.Context) (T, bool) {
 }
 
 // Close closes the queue, indicating that no more values will be enqueued.
+// The asynchronous enqueues that are still pending are completed before
+// the queue is closed, so that their values are not lost.
 // After closing, all future Dequeue operations will return (zero, false) once the queue is empty.
 func (q *Queue[T]) Close() {
-	close(q.channel)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closing = true
+	q.closeIfDone()
+}
+
+// beginAsync registers a new asynchronous enqueue, preventing the channel from
+// being closed until endAsync is called.
+// Returns false if the queue has already been closed.
+func (q *Queue[T]) beginAsync() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+	q.pending++
+	return true
+}
+
+// endAsync signals that an asynchronous enqueue has terminated, closing the
+// channel if Close has been called and no other enqueue is pending.
+func (q *Queue[T]) endAsync() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending--
+	q.closeIfDone()
+}
+
+// closeIfDone closes the channel if Close has been called and no asynchronous
+// enqueue is pending. The caller must hold the lock.
+func (q *Queue[T]) closeIfDone() {
+	if q.closing && !q.closed && q.pending == 0 {
+		close(q.channel)
+		q.closed = true
+	}
 }
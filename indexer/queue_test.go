@@ -0,0 +1,71 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueCloseWaitsPendingAsyncEnqueues(t *testing.T) {
+	ctx := context.Background()
+	queue := NewQueue[int](1)
+
+	queue.Enqueue(1)
+	queue.EnqueueAllAsync(ctx, []int{2, 3})
+	queue.DelayedEnqueue(ctx, 10*time.Millisecond, 4)
+	queue.Close()
+
+	var values []int
+	for {
+		value, ok := queue.Dequeue()
+		if !ok {
+			break
+		}
+		values = append(values, value)
+	}
+	require.ElementsMatch(t, []int{1, 2, 3, 4}, values)
+}
+
+func TestQueueAsyncEnqueueAfterCloseIsDiscarded(t *testing.T) {
+	ctx := context.Background()
+	queue := NewQueue[int](1)
+	queue.Close()
+
+	// Must not panic sending on the closed channel
+	require.NotPanics(t, func() {
+		queue.EnqueueAllAsync(ctx, []int{1, 2})
+		queue.DelayedEnqueue(ctx, 0, 3)
+	})
+
+	_, ok := queue.Dequeue()
+	require.False(t, ok)
+}
+
+func TestQueueCloseAbortsPendingEnqueuesOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	queue := NewQueue[int](1)
+
+	queue.Enqueue(1)
+	queue.EnqueueAllAsync(ctx, []int{2, 3})
+	queue.Close()
+	cancel()
+
+	// The pending values can be discarded, but the queue must be closed
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, ok := queue.Dequeue(); !ok {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queue not closed")
+	}
+}
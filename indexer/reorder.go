@@ -0,0 +1,110 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+)
+
+// ReorderBuffer is a generic, thread-safe buffer that allows multiple producers
+// to push values in any order while a single consumer receives them in the
+// order in which their positions have been reserved.
+type ReorderBuffer[T any] struct {
+	mu sync.Mutex
+	// Values that have been pushed but not yet popped, by sequence number.
+	values map[uint64]T
+	// Sequence number of the next value to pop.
+	next uint64
+	// Sequence number that will be assigned to the next reservation.
+	reserved uint64
+	// Channel used to limit the number of reserved positions.
+	slots chan struct{}
+	// Channel used to notify the consumer that a value has been pushed.
+	notify chan struct{}
+	closed bool
+}
+
+// NewReorderBuffer creates and returns a new buffer that can hold at most
+// size reserved positions.
+func NewReorderBuffer[T any](size uint32) *ReorderBuffer[T] {
+	return &ReorderBuffer[T]{
+		values: make(map[uint64]T),
+		slots:  make(chan struct{}, max(size, 1)),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Reserve reserves the next position of the buffer and returns its sequence number.
+// If the buffer is full, this call will block until a value is popped or the context is canceled.
+// Returns (seq, true) if successful, or (0, false) if the context was canceled first.
+func (b *ReorderBuffer[T]) Reserve(ctx context.Context) (uint64, bool) {
+	select {
+	case <-ctx.Done():
+		return 0, false
+	case b.slots <- struct{}{}:
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	seq := b.reserved
+	b.reserved++
+	return seq, true
+}
+
+// Push inserts the value at the position identified by the provided
+// sequence number, that must have been previously obtained with Reserve.
+func (b *ReorderBuffer[T]) Push(seq uint64, value T) {
+	b.mu.Lock()
+	b.values[seq] = value
+	b.mu.Unlock()
+	b.signal()
+}
+
+// Pop removes and returns the value at the next position of the buffer.
+// If the value has not been pushed yet, this call will block until it becomes available,
+// the buffer is closed or the context is canceled.
+// Returns (value, true) if successful, or (zero, false) if the buffer has been closed
+// or the context was canceled.
+func (b *ReorderBuffer[T]) Pop(ctx context.Context) (T, bool) {
+	var zero T
+	for {
+		b.mu.Lock()
+		value, found := b.values[b.next]
+		if found {
+			delete(b.values, b.next)
+			b.next++
+			b.mu.Unlock()
+			// Release the position so that a new one can be reserved
+			<-b.slots
+			return value, true
+		}
+		closed := b.closed
+		b.mu.Unlock()
+
+		if closed {
+			return zero, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return zero, false
+		case <-b.notify:
+		}
+	}
+}
+
+// Close closes the buffer, indicating that no more values will be pushed.
+// After closing, Pop returns (zero, false) once the next value is not available.
+func (b *ReorderBuffer[T]) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.signal()
+}
+
+// signal wakes up the consumer waiting for a value, if any.
+func (b *ReorderBuffer[T]) signal() {
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,59 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderBufferPopsInOrder(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewReorderBuffer[string](3)
+
+	for expected := uint64(0); expected < 3; expected++ {
+		seq, ok := buffer.Reserve(ctx)
+		require.True(t, ok)
+		require.Equal(t, expected, seq)
+	}
+
+	buffer.Push(2, "c")
+	buffer.Push(0, "a")
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		buffer.Push(1, "b")
+	}()
+
+	for _, expected := range []string{"a", "b", "c"} {
+		value, ok := buffer.Pop(ctx)
+		require.True(t, ok)
+		require.Equal(t, expected, value)
+	}
+
+	buffer.Close()
+	_, ok := buffer.Pop(ctx)
+	require.False(t, ok)
+}
+
+func TestReorderBufferReserveBlocksWhenFull(t *testing.T) {
+	buffer := NewReorderBuffer[string](1)
+
+	seq, ok := buffer.Reserve(context.Background())
+	require.True(t, ok)
+	buffer.Push(seq, "a")
+
+	// The buffer is full, the reservation must wait until a value is popped
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, ok = buffer.Reserve(ctx)
+	require.False(t, ok)
+
+	value, ok := buffer.Pop(context.Background())
+	require.True(t, ok)
+	require.Equal(t, "a", value)
+
+	seq, ok = buffer.Reserve(context.Background())
+	require.True(t, ok)
+	require.Equal(t, uint64(1), seq)
+}
@@ -0,0 +1,256 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/prometheus"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// handleReorg checks if the provided block is consistent with the chain that has
+// been indexed so far, comparing its hashes with the ones of the blocks indexed at
+// the same height and at the heights preceding and following it. In case a chain
+// reorganization is detected, the blocks that have been orphaned are rolled back.
+// Returns the height from which the canonical branch must be indexed again, which
+// is equal to the block height if no orphaned block below it has been detected,
+// and the heights above the block that have been rolled back, which must be indexed
+// again too since they have been indexed by the other workers before the block.
+func (w *Worker) handleReorg(ctx context.Context, block types.Block) (types.Height, []types.Height, error) {
+	// Check the block without holding the lock, so that the workers are
+	// serialized only when a reorganization is detected
+	orphaned, err := w.hasOrphanedBlocks(block)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !orphaned {
+		return block.GetHeight(), nil, nil
+	}
+
+	// Serialize the reorg handling between the workers to prevent multiple
+	// workers from rolling back the same blocks.
+	w.reorgLock.Lock()
+	defer w.reorgLock.Unlock()
+
+	forkHeight, err := w.findForkHeight(ctx, block)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// If the ancestors of the block are canonical, only the block indexed
+	// at the same height or its descendants can have been orphaned
+	rollbackHeight := forkHeight
+	if forkHeight == block.GetHeight() {
+		var found bool
+		rollbackHeight, found, err = w.findOrphanedDescendant(block)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		// Another worker has already rolled back the orphaned blocks, nothing to do
+		if !found {
+			return forkHeight, nil, nil
+		}
+	}
+
+	w.log.Warn().
+		Uint64("height", uint64(block.GetHeight())).
+		Uint64("fork height", uint64(rollbackHeight)).
+		Msg("chain reorganization detected")
+	prometheus.IndexerReorgs.WithLabelValues(w.cfg.Name).Inc()
+
+	// Get the heights above the block that will be removed by the rollback
+	indexedRanges, err := w.getIndexedRangesAbove(ctx, block.GetHeight())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = w.rollback(ctx, rollbackHeight)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return forkHeight, database.ExpandHeightRanges(indexedRanges), nil
+}
+
+// hasOrphanedBlocks checks if the blocks indexed at the same height of the
+// provided one or at the heights preceding and following it have been orphaned.
+func (w *Worker) hasOrphanedBlocks(block types.Block) (bool, error) {
+	parentOrphaned, err := w.isParentOrphaned(block)
+	if err != nil || parentOrphaned {
+		return parentOrphaned, err
+	}
+
+	_, found, err := w.findOrphanedDescendant(block)
+	return found, err
+}
+
+// findOrphanedDescendant returns the height of the block indexed at the same height
+// of the provided one, or of the block indexed at the following height, if it has
+// been orphaned. Returns false if none of them has been orphaned.
+func (w *Worker) findOrphanedDescendant(block types.Block) (types.Height, bool, error) {
+	orphaned, err := w.isIndexedBlockOrphaned(block)
+	if err != nil {
+		return 0, false, err
+	}
+	if orphaned {
+		return block.GetHeight(), true, nil
+	}
+
+	orphaned, err = w.isChildOrphaned(block)
+	if err != nil || !orphaned {
+		return 0, false, err
+	}
+	return block.GetHeight() + 1, true, nil
+}
+
+// isParentOrphaned checks if the block indexed at the height preceding the
+// provided block is not its parent.
+func (w *Worker) isParentOrphaned(block types.Block) (bool, error) {
+	if block.GetHeight() == 0 || block.GetParentHash() == "" {
+		return false, nil
+	}
+
+	parentHeight := block.GetHeight() - 1
+	indexedParent, err := w.db.GetIndexedBlock(w.cfg.Name, w.node.GetChainID(), parentHeight)
+	if err != nil {
+		return false, fmt.Errorf("get indexed block %d: %w", parentHeight, err)
+	}
+
+	// The parent is orphaned only if it has been indexed with a different hash
+	return indexedParent != nil && indexedParent.Hash != "" && indexedParent.Hash != block.GetParentHash(), nil
+}
+
+// isIndexedBlockOrphaned checks if the block indexed at the same height
+// of the provided one has a different hash.
+func (w *Worker) isIndexedBlockOrphaned(block types.Block) (bool, error) {
+	if block.GetHash() == "" {
+		return false, nil
+	}
+
+	indexedBlock, err := w.db.GetIndexedBlock(w.cfg.Name, w.node.GetChainID(), block.GetHeight())
+	if err != nil {
+		return false, fmt.Errorf("get indexed block %d: %w", block.GetHeight(), err)
+	}
+
+	return indexedBlock != nil && indexedBlock.Hash != "" && indexedBlock.Hash != block.GetHash(), nil
+}
+
+// isChildOrphaned checks if the block indexed at the height following the
+// provided block is not its child. This detects the orphaned blocks that
+// have been indexed before their parent.
+func (w *Worker) isChildOrphaned(block types.Block) (bool, error) {
+	if block.GetHeight() == types.MaxHeight || block.GetHash() == "" {
+		return false, nil
+	}
+
+	childHeight := block.GetHeight() + 1
+	indexedChild, err := w.db.GetIndexedBlock(w.cfg.Name, w.node.GetChainID(), childHeight)
+	if err != nil {
+		return false, fmt.Errorf("get indexed block %d: %w", childHeight, err)
+	}
+
+	// The child is orphaned only if it has been indexed with a different parent
+	return indexedChild != nil && indexedChild.ParentHash != "" && indexedChild.ParentHash != block.GetHash(), nil
+}
+
+// getIndexedRangesAbove gets the ranges of the indexed heights greater than
+// the provided one, up to the current node height.
+func (w *Worker) getIndexedRangesAbove(ctx context.Context, height types.Height) ([]database.HeightRange, error) {
+	currentHeight, err := w.node.GetCurrentHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get current node height: %w", err)
+	}
+	if currentHeight <= height {
+		return nil, nil
+	}
+
+	missingRanges, err := w.db.GetMissingRanges(w.cfg.Name, w.node.GetChainID(), height+1, currentHeight)
+	if err != nil {
+		return nil, fmt.Errorf("get missing blocks above %d: %w", height, err)
+	}
+
+	// The indexed heights are the ones between the missing ranges
+	var result []database.HeightRange
+	next := height + 1
+	for _, missingRange := range missingRanges {
+		if missingRange.From > next {
+			result = append(result, database.NewHeightRange(next, missingRange.From-1))
+		}
+		next = missingRange.To + 1
+	}
+	if next <= currentHeight {
+		result = append(result, database.NewHeightRange(next, currentHeight))
+	}
+
+	return result, nil
+}
+
+// findForkHeight walks back the indexed chain starting from the provided block
+// and returns the lowest height whose indexed block has been replaced by the chain.
+// If the provided block extends the indexed chain, its height is returned.
+func (w *Worker) findForkHeight(ctx context.Context, block types.Block) (types.Height, error) {
+	forkHeight := block.GetHeight()
+	current := block
+	for {
+		// Stop if we haven't indexed the parent block or it is part of the canonical chain
+		orphaned, err := w.isParentOrphaned(current)
+		if err != nil {
+			return 0, err
+		}
+		if !orphaned {
+			break
+		}
+
+		// The indexed parent has been orphaned, fetch the canonical one to check
+		// if also its ancestors have been replaced.
+		forkHeight = current.GetHeight() - 1
+		current, err = w.node.GetBlock(ctx, forkHeight)
+		if err != nil {
+			return 0, fmt.Errorf("fetch canonical block %d: %w", forkHeight, err)
+		}
+	}
+
+	return forkHeight, nil
+}
+
+// rollback removes the data indexed from the blocks having a height greater or
+// equal to forkHeight.
+func (w *Worker) rollback(ctx context.Context, forkHeight types.Height) error {
+	// Perform the rollback atomically
+	tx, err := w.db.BeginBlockTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin rollback tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			w.log.Err(err).Uint64("fork height", uint64(forkHeight)).Msg("rollback reorg tx")
+		}
+	}()
+	rollbackCtx := database.InjectBlockTx(ctx, tx)
+
+	// Let the modules remove the data extracted from the orphaned blocks
+	for _, module := range w.modules {
+		if reorgHandler, ok := modules.As[modules.ReorgHandler](module); ok {
+			err := reorgHandler.OnRollback(rollbackCtx, forkHeight)
+			if err != nil {
+				return fmt.Errorf("rollback module %s from height %d: %w", module.GetName(), forkHeight, err)
+			}
+		}
+	}
+
+	// Remove the orphaned blocks from the indexed ones
+	err = tx.DeleteIndexedBlocks(w.cfg.Name, w.node.GetChainID(), forkHeight)
+	if err != nil {
+		return fmt.Errorf("delete indexed blocks from height %d: %w", forkHeight, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit rollback tx: %w", err)
+	}
+
+	return nil
+}
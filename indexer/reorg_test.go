@@ -0,0 +1,210 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestWorkerReorgRollsBackOrphanedBlocks(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	module := newTestModule("module")
+	queue := NewQueue[IndexerHeight](100)
+	stop := startTestWorkers(newTestIndexerConfig(), 3, queue, db, testNode, []modules.Module{module}, nil)
+	defer stop()
+
+	// Index the chain
+	enqueueHeights(queue, 1, 10)
+	requireMissingRanges(t, db, 1, 10, nil)
+	requireModuleMissingRanges(t, db, module.GetName(), 1, 10, nil)
+
+	// Replace the blocks starting from 6 and index one of the new blocks, the
+	// blocks above it have already been indexed and must be indexed again too
+	testNode.reorg(6, "b")
+	queue.Enqueue(NewIndexerHeight(8))
+
+	require.Eventually(t, func() bool {
+		for height := types.Height(1); height <= 10; height++ {
+			block, err := db.GetIndexedBlock(testIndexerName, testChainID, height)
+			if err != nil || block == nil || block.Hash != testNode.getHash(height) {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 5*time.Millisecond)
+	requireModuleMissingRanges(t, db, module.GetName(), 1, 10, nil)
+	requireModuleMissingRanges(t, db, module.dataKey(), 1, 10, nil)
+
+	require.Equal(t, []types.Height{6}, module.getRollbacks())
+	for height := types.Height(1); height <= 5; height++ {
+		require.Equal(t, 1, module.getHandled(height))
+	}
+	for height := types.Height(6); height <= 10; height++ {
+		require.Equal(t, 2, module.getHandled(height))
+	}
+}
+
+func TestWorkerReorgNotDetectedOnCanonicalChain(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	module := newTestModule("module")
+	queue := NewQueue[IndexerHeight](100)
+	stop := startTestWorkers(newTestIndexerConfig(), 3, queue, db, testNode, []modules.Module{module}, nil)
+	defer stop()
+
+	enqueueHeights(queue, 1, 10)
+	requireMissingRanges(t, db, 1, 10, nil)
+
+	// Indexing again a block of the canonical chain must not roll back anything
+	queue.Enqueue(NewIndexerHeight(5))
+	require.Eventually(t, func() bool {
+		return module.getHandled(5) == 2
+	}, 5*time.Second, 5*time.Millisecond)
+	require.Empty(t, module.getRollbacks())
+
+	missing, err := db.GetMissingRanges(testIndexerName, testChainID, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, []database.HeightRange(nil), missing)
+}
+
+func TestWorkerReorgDetectedByIndexedChild(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	module := newTestModule("module")
+	queue := NewQueue[IndexerHeight](100)
+	stop := startTestWorkers(newTestIndexerConfig(), 3, queue, db, testNode, []modules.Module{module}, nil)
+	defer stop()
+
+	// Index the chain except for a block
+	enqueueHeights(queue, 1, 4)
+	enqueueHeights(queue, 6, 10)
+	requireMissingRanges(t, db, 1, 10, []database.HeightRange{database.NewHeightRange(5, 5)})
+
+	// Replace the blocks starting from the missing one, its children
+	// have been orphaned and must be indexed again
+	testNode.reorg(5, "b")
+	queue.Enqueue(NewIndexerHeight(5))
+
+	require.Eventually(t, func() bool {
+		for height := types.Height(1); height <= 10; height++ {
+			block, err := db.GetIndexedBlock(testIndexerName, testChainID, height)
+			if err != nil || block == nil || block.Hash != testNode.getHash(height) {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 5*time.Millisecond)
+	requireModuleMissingRanges(t, db, module.GetName(), 1, 10, nil)
+
+	require.Equal(t, []types.Height{6}, module.getRollbacks())
+	require.Equal(t, 1, module.getHandled(5))
+	for height := types.Height(6); height <= 10; height++ {
+		require.Equal(t, 2, module.getHandled(height))
+	}
+}
+
+func TestWorkerReorgDetectedByIndexedBlock(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	module := newTestModule("module")
+	queue := NewQueue[IndexerHeight](100)
+	stop := startTestWorkers(newTestIndexerConfig(), 3, queue, db, testNode, []modules.Module{module}, nil)
+	defer stop()
+
+	enqueueHeights(queue, 1, 10)
+	requireMissingRanges(t, db, 1, 10, nil)
+
+	// Index again the first replaced block, the data extracted
+	// from the orphaned one must be rolled back
+	testNode.reorg(5, "b")
+	queue.Enqueue(NewIndexerHeight(5))
+
+	require.Eventually(t, func() bool {
+		for height := types.Height(1); height <= 10; height++ {
+			block, err := db.GetIndexedBlock(testIndexerName, testChainID, height)
+			if err != nil || block == nil || block.Hash != testNode.getHash(height) {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 5*time.Millisecond)
+	requireModuleMissingRanges(t, db, module.GetName(), 1, 10, nil)
+	require.Equal(t, []types.Height{5}, module.getRollbacks())
+}
+
+func TestWorkerReorgCheckDoesNotLockCanonicalBlocks(t *testing.T) {
+	db := newTestDatabase()
+	module := newTestModule("module")
+	worker := newTestWorker(NewQueue[IndexerHeight](10), db, newTestNode(10), []modules.Module{module}, nil)
+	require.NoError(t, worker.fetchAndProcessBlock(context.Background(), NewIndexerHeight(4)))
+
+	// Another worker is handling a reorganization
+	worker.reorgLock.Lock()
+	defer worker.reorgLock.Unlock()
+
+	// The blocks that are consistent with the indexed chain are indexed without waiting for the lock
+	done := make(chan error, 1)
+	go func() {
+		done <- worker.fetchAndProcessBlock(context.Background(), NewIndexerHeight(5))
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "block indexing waited for the reorg lock")
+	}
+}
+
+func TestOrderedIndexerReorgReindexesByHeight(t *testing.T) {
+	db := newTestDatabase()
+	testNode := newTestNode(10)
+	module := newTestModule("module")
+	mu := sync.Mutex{}
+	var received []types.Height
+	module.fail = func(height types.Height) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, height)
+		return nil
+	}
+
+	runOrderedIndexer := func(producer HeightProducer) {
+		cfg := newTestIndexerConfig()
+		cfg.Ordered = true
+		cfg.Workers = 3
+		indexer := NewIndexer(cfg, zerolog.Nop(), db, testNode, []modules.Module{module})
+		indexer.WithCustomHeightProducer(producer)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		wg := sync.WaitGroup{}
+		require.NoError(t, indexer.Start(ctx, &wg))
+		wg.Wait()
+		require.NoError(t, ctx.Err())
+	}
+
+	runOrderedIndexer(NewRangeHeightProducer(1, 10))
+	require.Equal(t, []types.Height{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, received)
+
+	// Replace the blocks starting from 6 and index one of the new blocks, both the canonical
+	// branch below it and the rolled back heights above it must be indexed by height
+	testNode.reorg(6, "b")
+	received = nil
+	runOrderedIndexer(NewListHeightProducer([]types.Height{8}))
+	require.Equal(t, []types.Height{6, 7, 8, 9, 10}, received)
+	require.Equal(t, []types.Height{6}, module.getRollbacks())
+	for height := types.Height(6); height <= 10; height++ {
+		block, err := db.GetIndexedBlock(testIndexerName, testChainID, height)
+		require.NoError(t, err)
+		require.Equal(t, testNode.getHash(height), block.Hash)
+	}
+}
@@ -0,0 +1,122 @@
+package indexer
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+// RetryPolicy computes the amount of time to wait before retrying to index
+// a failed block, the delay grows exponentially with the number of attempts.
+type RetryPolicy struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+	jitter       float64
+	// random returns a random number in the [0, 1) interval, used to compute the jitter.
+	random func() float64
+}
+
+// NewRetryPolicy creates a new RetryPolicy from the provided indexer configuration.
+func NewRetryPolicy(cfg *types.IndexerConfig) RetryPolicy {
+	return RetryPolicy{
+		initialDelay: cfg.TimeBeforeRetry,
+		maxDelay:     cfg.RetryPolicy.GetMaxDelay(cfg.TimeBeforeRetry),
+		multiplier:   cfg.RetryPolicy.Multiplier,
+		jitter:       cfg.RetryPolicy.Jitter,
+		random:       rand.Float64,
+	}
+}
+
+// withDefaults returns a copy of the policy where the unset values are
+// replaced by the default ones, so that a zero RetryPolicy never
+// retries without waiting.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.initialDelay <= 0 {
+		p.initialDelay = types.DefaultIndexerCfg.TimeBeforeRetry
+	}
+	if p.maxDelay <= 0 {
+		p.maxDelay = types.DefaultRetryPolicyCfg.GetMaxDelay(p.initialDelay)
+	}
+	if p.multiplier < 1 {
+		p.multiplier = types.DefaultRetryPolicyCfg.Multiplier
+	}
+	if p.random == nil {
+		p.random = rand.Float64
+	}
+	return p
+}
+
+// GetDelay gets the amount of time to wait before performing the next attempt,
+// given the number of attempts already performed and the error returned by the
+// last one. If the error is a types.RetryableError, the delay is at least
+// the one requested by the error.
+func (p RetryPolicy) GetDelay(attempts uint32, err error) time.Duration {
+	p = p.withDefaults()
+	exponent := float64(max(attempts, 1) - 1)
+	delay := float64(p.initialDelay) * math.Pow(p.multiplier, exponent)
+	delay = min(delay, float64(p.maxDelay))
+
+	// Randomly add or remove up to jitter * delay
+	if p.jitter > 0 {
+		delay += delay * p.jitter * (2*p.random() - 1)
+		delay = min(delay, float64(p.maxDelay))
+	}
+
+	result := time.Duration(delay)
+	var retryableErr *types.RetryableError
+	if errors.As(err, &retryableErr) && retryableErr.RetryAfter > result {
+		result = retryableErr.RetryAfter
+	}
+
+	return result
+}
+
+// errorClass represents how the indexer handles the error returned while indexing a block.
+type errorClass int
+
+const (
+	// errorClassUnknown represents the errors that have not been classified,
+	// the block is retried until the maximum number of attempts is reached.
+	errorClassUnknown errorClass = iota
+	// errorClassSkip represents the permanent errors, the block is stored as
+	// failed without being retried.
+	errorClassSkip
+	// errorClassRetryable represents the transient errors, the block is retried
+	// until the maximum number of attempts is reached, also by the modules that
+	// would otherwise skip it.
+	errorClassRetryable
+	// errorClassRateLimited represents the errors caused by the node rate limiting
+	// the requests, the block is retried without counting the attempt.
+	errorClassRateLimited
+)
+
+// classifyError gets the class of the provided error, returned while indexing a block.
+func classifyError(err error) errorClass {
+	switch {
+	case errors.Is(err, types.ErrSkipBlock):
+		return errorClassSkip
+	case errors.Is(err, types.ErrRateLimited):
+		return errorClassRateLimited
+	case errors.Is(err, types.ErrRetryable):
+		return errorClassRetryable
+	default:
+		return errorClassUnknown
+	}
+}
+
+// isTransient tells if the provided error is expected to be fixed by retrying.
+func isTransient(err error) bool {
+	class := classifyError(err)
+	return class == errorClassRetryable || class == errorClassRateLimited
+}
+
+// countsAsAttempt tells if a failed attempt should be counted toward the
+// maximum number of attempts, the attempts failed because the node is
+// rate limiting the requests are not counted.
+func countsAsAttempt(err error) bool {
+	return classifyError(err) != errorClassRateLimited
+}
@@ -0,0 +1,115 @@
+package indexer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+func TestRetryPolicyGetDelay(t *testing.T) {
+	policy := RetryPolicy{
+		initialDelay: time.Second,
+		maxDelay:     10 * time.Second,
+		multiplier:   2,
+		jitter:       0,
+	}
+
+	require.Equal(t, time.Second, policy.GetDelay(0, nil))
+	require.Equal(t, time.Second, policy.GetDelay(1, nil))
+	require.Equal(t, 2*time.Second, policy.GetDelay(2, nil))
+	require.Equal(t, 8*time.Second, policy.GetDelay(4, nil))
+	require.Equal(t, 10*time.Second, policy.GetDelay(5, nil))
+	require.Equal(t, 10*time.Second, policy.GetDelay(100, nil))
+
+	// Retryable errors can request a longer delay
+	retryableErr := fmt.Errorf("call block: %w", types.NewRetryableError(fmt.Errorf("rate limited"), time.Minute))
+	require.Equal(t, time.Minute, policy.GetDelay(1, retryableErr))
+	require.Equal(t, 2*time.Second, policy.GetDelay(2, types.NewRetryableError(fmt.Errorf("rate limited"), 0)))
+}
+
+func TestRetryPolicyGetDelayDefaults(t *testing.T) {
+	// A zero policy uses the default values instead of retrying immediately
+	policy := RetryPolicy{}
+	require.Equal(t, types.DefaultIndexerCfg.TimeBeforeRetry, policy.GetDelay(1, nil))
+	require.Equal(t, 2*types.DefaultIndexerCfg.TimeBeforeRetry, policy.GetDelay(2, nil))
+	require.Equal(t, types.DefaultRetryMaxDelay, policy.GetDelay(100, nil))
+
+	// Without max_delay, the delay is capped to the initial delay if greater than the default maximum
+	cfg := types.DefaultIndexerCfg
+	cfg.TimeBeforeRetry = 10 * time.Minute
+	cfg.RetryPolicy.Jitter = 0
+	require.Equal(t, 10*time.Minute, NewRetryPolicy(&cfg).GetDelay(5, nil))
+}
+
+func TestRetryPolicyGetDelayJitter(t *testing.T) {
+	policy := RetryPolicy{
+		initialDelay: 10 * time.Second,
+		maxDelay:     time.Minute,
+		multiplier:   1,
+		jitter:       0.5,
+	}
+
+	policy.random = func() float64 { return 0 }
+	require.Equal(t, 5*time.Second, policy.GetDelay(1, nil))
+
+	policy.random = func() float64 { return 0.5 }
+	require.Equal(t, 10*time.Second, policy.GetDelay(1, nil))
+
+	policy.random = func() float64 { return 0.99 }
+	require.InDelta(t, float64(15*time.Second), float64(policy.GetDelay(1, nil)), float64(time.Second))
+}
+
+func TestCountsAsAttempt(t *testing.T) {
+	require.True(t, countsAsAttempt(fmt.Errorf("generic error")))
+	require.True(t, countsAsAttempt(types.NewRetryableError(fmt.Errorf("unavailable"), time.Second)))
+	require.False(t, countsAsAttempt(fmt.Errorf("call block: %w", types.NewRateLimitedError(fmt.Errorf("rate limited"), 0))))
+}
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected errorClass
+	}{
+		{
+			name:     "generic error is unknown",
+			err:      fmt.Errorf("generic error"),
+			expected: errorClassUnknown,
+		},
+		{
+			name:     "wrapped skip block error is skip",
+			err:      fmt.Errorf("handle block: %w", types.NewSkipBlockError(fmt.Errorf("malformed block"))),
+			expected: errorClassSkip,
+		},
+		{
+			name:     "wrapped retryable sentinel is retryable",
+			err:      fmt.Errorf("handle block: %w", types.ErrRetryable),
+			expected: errorClassRetryable,
+		},
+		{
+			name:     "retryable error is retryable",
+			err:      fmt.Errorf("fetch block: %w", types.NewRetryableError(fmt.Errorf("unavailable"), time.Second)),
+			expected: errorClassRetryable,
+		},
+		{
+			name:     "rate limited error is rate limited",
+			err:      fmt.Errorf("fetch block: %w", types.NewRateLimitedError(fmt.Errorf("rate limited"), 0)),
+			expected: errorClassRateLimited,
+		},
+		{
+			name:     "module error is classified by the wrapped error",
+			err:      NewModuleError("module", fmt.Errorf("handle block: %w", types.ErrRetryable)),
+			expected: errorClassRetryable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, classifyError(tc.err))
+		})
+	}
+}
@@ -2,8 +2,10 @@ package indexer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	log "github.com/rs/zerolog"
 
@@ -31,6 +33,15 @@ type Worker struct {
 	// List of modules that will be used by the indexer to index data from
 	// the chain.
 	modules []modules.Module
+	// Indexes of the modules on which each module depends.
+	dependencies [][]int
+	// Policy used to handle the errors returned by each module.
+	errorPolicies []types.ModuleErrorPolicy
+	// Lock shared between the indexer's workers to serialize the handling
+	// of the chain reorganizations.
+	reorgLock *sync.Mutex
+	// Policy used to compute the delay before retrying to index a failed block.
+	retryPolicy RetryPolicy
 }
 
 func NewWorker(
@@ -40,17 +51,57 @@ func NewWorker(
 	db database.Database,
 	node node.Node,
 	modules []modules.Module,
+	errorPolicies map[string]types.ModuleErrorPolicy,
+	reorgLock *sync.Mutex,
 ) Worker {
 	return Worker{
-		cfg:          cfg,
-		log:          log.With().Str("component", "worker").Logger(),
-		heightsQueue: heightsQueue,
-		db:           db,
-		node:         node,
-		modules:      modules,
+		cfg:           cfg,
+		log:           log.With().Str("component", "worker").Logger(),
+		heightsQueue:  heightsQueue,
+		db:            db,
+		node:          node,
+		modules:       modules,
+		dependencies:  getModulesDependencies(modules),
+		errorPolicies: getModulesErrorPolicies(modules, errorPolicies),
+		reorgLock:     reorgLock,
+		retryPolicy:   NewRetryPolicy(cfg),
 	}
 }
 
+// getModulesErrorPolicies gets the policy used to handle the errors of each of
+// the provided modules, the modules without a policy fail the whole block.
+func getModulesErrorPolicies(indexerModules []modules.Module, errorPolicies map[string]types.ModuleErrorPolicy) []types.ModuleErrorPolicy {
+	policies := make([]types.ModuleErrorPolicy, len(indexerModules))
+	for index, module := range indexerModules {
+		policy, found := errorPolicies[module.GetName()]
+		if !found {
+			policy = types.ModuleErrorPolicyFail
+		}
+		policies[index] = policy
+	}
+	return policies
+}
+
+// getModulesDependencies gets, for each of the provided modules, the indexes
+// of the modules on which it depends.
+func getModulesDependencies(indexerModules []modules.Module) [][]int {
+	indexes := make(map[string]int, len(indexerModules))
+	for index, module := range indexerModules {
+		indexes[module.GetName()] = index
+	}
+
+	dependencies := make([][]int, len(indexerModules))
+	for index, module := range indexerModules {
+		for _, dependency := range modules.GetDependencies(module) {
+			if dependencyIndex, found := indexes[dependency]; found {
+				dependencies[index] = append(dependencies[index], dependencyIndex)
+			}
+		}
+	}
+
+	return dependencies
+}
+
 // Start the worker logic.
 func (w *Worker) Start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
@@ -79,82 +130,432 @@ func (w *Worker) workerLoop(ctx context.Context, wg *sync.WaitGroup) {
 			}
 
 			// Get the block from the node
-			err := w.fetchAndProcessBlock(ctx, indexHeight.Height)
+			err := w.fetchAndProcessBlock(ctx, indexHeight)
 			if err != nil {
 				w.log.Err(err).Uint64("height", uint64(indexHeight.Height)).Msg("get and process block")
-				w.reEnqueueBlock(ctx, indexHeight)
+				w.reEnqueueBlock(ctx, indexHeight, err)
 			}
 		}
 	}
 }
 
 // fetchAndProcessBlock fetches the block at the provided height and, if fetched successfully, processes it.
-func (w *Worker) fetchAndProcessBlock(ctx context.Context, height types.Height) error {
+func (w *Worker) fetchAndProcessBlock(ctx context.Context, indexHeight IndexerHeight) error {
+	height := indexHeight.Height
+	block, err := w.fetchBlock(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the block is consistent with the chain indexed so far
+	forkHeight, rolledBackHeights, err := w.handleReorg(ctx, block)
+	if err != nil {
+		return fmt.Errorf("handle reorg at block: %d, err: %w", height, err)
+	}
+
+	// Re-index the canonical branch and the rolled back heights above the block, by height
+	heights := make([]IndexerHeight, 0, int(height-forkHeight)+len(rolledBackHeights))
+	for h := forkHeight; h < height; h++ {
+		heights = append(heights, NewIndexerHeight(h))
+	}
+	for _, h := range rolledBackHeights {
+		heights = append(heights, NewIndexerHeight(h))
+	}
+	if len(heights) > 0 {
+		w.heightsQueue.EnqueueAllAsync(ctx, heights)
+	}
+
+	return w.indexBlock(ctx, indexHeight, block)
+}
+
+// fetchBlock fetches the block at the provided height from the node.
+func (w *Worker) fetchBlock(ctx context.Context, height types.Height) (types.Block, error) {
 	w.log.Debug().Uint64("height", uint64(height)).Msg("fetch block")
 
-	// Get the block from the node
 	block, err := w.node.GetBlock(ctx, height)
 	if err != nil {
-		return fmt.Errorf("fetch block: %d, err: %w", height, err)
+		return nil, fmt.Errorf("fetch block: %d, err: %w", height, err)
 	}
 
+	return block, nil
+}
+
+// indexBlock passes the provided block to the modules and stores it as indexed,
+// all the writes are performed atomically inside a single BlockTx.
+// The modules that handle their errors on their own process the block only
+// after it has been stored, each one inside a dedicated BlockTx.
+func (w *Worker) indexBlock(ctx context.Context, indexHeight IndexerHeight, block types.Block) error {
+	height := indexHeight.Height
+
+	// Let the database prepare the storage of the block outside of its transaction
+	if preparer, ok := w.db.(database.HeightPreparer); ok {
+		err := preparer.PrepareHeight(height)
+		if err != nil {
+			return fmt.Errorf("prepare block %d storage: %w", height, err)
+		}
+	}
+
+	// Start the transaction used to store atomically all the data
+	// extracted from the block
+	tx, err := w.db.BeginBlockTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin block %d tx: %w", height, err)
+	}
+	defer func() {
+		// Discard the writes in case the block has not been indexed
+		if err := tx.Rollback(); err != nil {
+			w.log.Err(err).Uint64("height", uint64(height)).Msg("rollback block tx")
+		}
+	}()
+	blockCtx := database.InjectBlockTx(ctx, tx)
+
 	// Process the fetched block
-	err = w.processBlock(w.log, ctx, block)
+	err = w.processBlock(blockCtx, tx, indexHeight, block)
 	if err != nil {
 		return fmt.Errorf("process block: %d, err: %w", height, err)
 	}
 
 	// Save in the database that we have successfully indexed the block
-	err = w.db.SaveIndexedBlock(w.cfg.Name, w.node.GetChainID(), height, block.GetTimeStamp())
+	err = tx.SaveIndexedBlock(w.cfg.Name, w.node.GetChainID(), database.NewIndexedBlock(
+		height,
+		block.GetHash(),
+		block.GetParentHash(),
+		block.GetTimeStamp(),
+	))
 	if err != nil {
 		return fmt.Errorf("save block %d as indexed: %w", height, err)
 	}
 
+	// Remove the failure not related to a module stored by the previous indexing attempts
+	err = tx.DeleteModuleFailedBlock(w.cfg.Name, w.node.GetChainID(), "", height)
+	if err != nil {
+		return fmt.Errorf("delete block %d failures: %w", height, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit block %d tx: %w", height, err)
+	}
+
 	w.log.Debug().Uint64("height", uint64(height)).Msg("block indexed")
 	prometheus.LatestIndexedHeightByIndexer.
 		WithLabelValues(w.cfg.Name).
 		Set(float64(height))
 
+	w.processIsolatedModules(ctx, indexHeight, block)
+
 	return nil
 }
 
-func (w *Worker) processBlock(_ log.Logger, ctx context.Context, b types.Block) error {
-	for _, m := range w.modules {
-		// Run the block handling logic
-		if blockHandler, ok := m.(modules.BlockHandleModule); ok {
-			err := blockHandler.HandleBlock(ctx, b)
-			if err != nil {
-				return fmt.Errorf("handle block, module: %s err: %w", m.GetName(), err)
+// processBlock passes the provided block to the modules that fail the whole
+// block in case of error. The modules that don't depend on each other process
+// the block concurrently, while each module processes the block only after
+// all its dependencies have processed it.
+func (w *Worker) processBlock(
+	ctx context.Context,
+	tx database.BlockTx,
+	indexHeight IndexerHeight,
+	b types.Block,
+) error {
+	selected := w.selectModules(indexHeight, false)
+	_, err := w.runModules(ctx, selected, true, func(ctx context.Context, _ int, m modules.Module) error {
+		return w.processModule(ctx, m, b)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Track the modules that have processed the block
+	for index, m := range w.modules {
+		if !selected[index] {
+			continue
+		}
+
+		err := tx.SaveModuleIndexedBlock(w.cfg.Name, w.node.GetChainID(), m.GetName(), b.GetHeight())
+		if err != nil {
+			return fmt.Errorf("save block as processed by module %s: %w", m.GetName(), err)
+		}
+
+		err = tx.DeleteModuleFailedBlock(w.cfg.Name, w.node.GetChainID(), m.GetName(), b.GetHeight())
+		if err != nil {
+			return fmt.Errorf("delete module %s failure: %w", m.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// selectModules gets, for each module, whether it must process the block at the
+// provided height. If isolated is true, only the modules that handle their
+// errors on their own are selected, otherwise only the ones that fail the whole block.
+func (w *Worker) selectModules(indexHeight IndexerHeight, isolated bool) []bool {
+	selected := make([]bool, len(w.modules))
+	for index, m := range w.modules {
+		// Skip the modules that have already processed the block
+		isIsolated := w.errorPolicies[index] != types.ModuleErrorPolicyFail
+		selected[index] = isIsolated == isolated && indexHeight.ShouldProcess(m.GetName())
+	}
+	return selected
+}
+
+// runModules executes the provided function for each of the selected modules,
+// passing the module along with its index.
+// The modules that don't depend on each other are executed concurrently, while
+// each module is executed only after all its selected dependencies have been
+// executed successfully. If failFast is true, the first error stops the execution
+// of the other modules.
+// Returns the error of each module that has not been executed successfully and
+// the first error that occurred.
+func (w *Worker) runModules(
+	ctx context.Context,
+	selected []bool,
+	failFast bool,
+	run func(ctx context.Context, index int, m modules.Module) error,
+) ([]error, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(w.modules))
+	var firstErr error
+	var errOnce sync.Once
+	fail := func(index int, err error) {
+		errs[index] = err
+		errOnce.Do(func() {
+			firstErr = err
+			if failFast {
+				cancel()
 			}
+		})
+	}
+
+	// Channels closed once the module at the same index has been executed
+	done := make([]chan struct{}, len(w.modules))
+	for index := range w.modules {
+		done[index] = make(chan struct{})
+	}
+
+	wg := sync.WaitGroup{}
+	for index, m := range w.modules {
+		if !selected[index] {
+			close(done[index])
+			continue
 		}
 
-		// Run the tx handling logic
-		if txHandler, ok := m.(modules.TxHandleModule); ok {
-			for _, tx := range b.GetTxs() {
-				err := txHandler.HandleTx(ctx, b, tx)
-				if err != nil {
-					return fmt.Errorf("handle tx, module: %s, tx: %s err: %w", m.GetName(), tx.GetHash(), err)
+		wg.Add(1)
+		go func() {
+			defer func() {
+				close(done[index])
+				wg.Done()
+			}()
+
+			// Wait for the dependencies to be executed
+			for _, dependency := range w.dependencies[index] {
+				select {
+				case <-ctx.Done():
+					fail(index, ctx.Err())
+					return
+				case <-done[dependency]:
+				}
+
+				if errs[dependency] != nil {
+					dependencyName := w.modules[dependency].GetName()
+					fail(index, NewModuleError(m.GetName(), fmt.Errorf("dependency %s failed", dependencyName)))
+					return
 				}
 			}
+
+			err := run(ctx, index, m)
+			if err != nil {
+				fail(index, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errs, firstErr
+}
+
+// processModule passes the provided block and its transactions to the given module.
+func (w *Worker) processModule(ctx context.Context, m modules.Module, b types.Block) error {
+	// Let the module set up its block state
+	if beforeBlockHook, ok := modules.As[modules.BeforeBlockHook](m); ok {
+		err := beforeBlockHook.BeforeBlock(ctx, b)
+		if err != nil {
+			return NewModuleError(m.GetName(), fmt.Errorf("before block, module: %s err: %w", m.GetName(), err))
+		}
+	}
+
+	// Run the block handling logic
+	if blockHandler, ok := m.(modules.BlockHandleModule); ok {
+		err := blockHandler.HandleBlock(ctx, b)
+		if err != nil {
+			return NewModuleError(m.GetName(), fmt.Errorf("handle block, module: %s err: %w", m.GetName(), err))
+		}
+	}
+
+	// Run the tx handling logic
+	if txHandler, ok := m.(modules.TxHandleModule); ok {
+		for _, tx := range b.GetTxs() {
+			err := txHandler.HandleTx(ctx, b, tx)
+			if err != nil {
+				return NewModuleError(m.GetName(), fmt.Errorf("handle tx, module: %s, tx: %s err: %w", m.GetName(), tx.GetHash(), err))
+			}
+		}
+	}
+
+	// Run the event handling logic
+	if eventHandler, ok := m.(modules.EventHandleModule); ok {
+		err := w.processEvents(ctx, eventHandler, b)
+		if err != nil {
+			return NewModuleError(m.GetName(), err)
+		}
+	}
+
+	// Run the message handling logic
+	if messageHandler, ok := m.(modules.MessageHandleModule); ok {
+		err := w.processMessages(ctx, messageHandler, b)
+		if err != nil {
+			return NewModuleError(m.GetName(), err)
+		}
+	}
+
+	// Let the module store its block state
+	if afterBlockHook, ok := modules.As[modules.AfterBlockHook](m); ok {
+		err := afterBlockHook.AfterBlock(ctx, b)
+		if err != nil {
+			return NewModuleError(m.GetName(), fmt.Errorf("after block, module: %s err: %w", m.GetName(), err))
 		}
 	}
 
 	return nil
 }
 
-func (w *Worker) reEnqueueBlock(ctx context.Context, indexHeight IndexerHeight) {
+// processEvents passes to the provided module the events that match its filters,
+// starting from the ones emitted outside of the block transactions.
+func (w *Worker) processEvents(ctx context.Context, m modules.EventHandleModule, b types.Block) error {
+	filters := m.GetEventFilters()
+
+	if eventsBlock, ok := b.(types.EventsBlock); ok {
+		for _, event := range eventsBlock.GetEvents() {
+			if !modules.MatchesAnyEventFilter(filters, event) {
+				continue
+			}
+
+			err := m.HandleEvent(ctx, b, nil, event)
+			if err != nil {
+				return fmt.Errorf("handle block event, module: %s, event: %s err: %w", m.GetName(), event.GetType(), err)
+			}
+		}
+	}
+
+	for _, tx := range b.GetTxs() {
+		eventsTx, ok := tx.(types.EventsTx)
+		if !ok {
+			continue
+		}
+
+		for _, event := range eventsTx.GetEvents() {
+			if !modules.MatchesAnyEventFilter(filters, event) {
+				continue
+			}
+
+			err := m.HandleEvent(ctx, b, tx, event)
+			if err != nil {
+				return fmt.Errorf("handle tx event, module: %s, tx: %s, event: %s err: %w",
+					m.GetName(), tx.GetHash(), event.GetType(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// processMessages passes to the provided module the messages included in the
+// block transactions that have one of the types it handles.
+func (w *Worker) processMessages(ctx context.Context, m modules.MessageHandleModule, b types.Block) error {
+	messageTypes := m.GetMessageTypes()
+
+	for _, tx := range b.GetTxs() {
+		messagesTx, ok := tx.(types.MessagesTx)
+		if !ok {
+			continue
+		}
+
+		for index, message := range messagesTx.GetMessages() {
+			if !modules.MatchesMessageTypes(messageTypes, message) {
+				continue
+			}
+
+			err := m.HandleMessage(ctx, b, tx, index, message)
+			if err != nil {
+				return fmt.Errorf("handle message, module: %s, tx: %s, message: %s err: %w",
+					m.GetName(), tx.GetHash(), message.GetType(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Worker) reEnqueueBlock(ctx context.Context, indexHeight IndexerHeight, indexErr error) {
 	select {
 	case <-ctx.Done():
 		w.log.Debug().Uint64("height", uint64(indexHeight.Height)).Msg("skip re-enqueue, context canceled")
 	default:
-		indexHeight.Attempts += 1
-		if indexHeight.Attempts >= w.cfg.MaxAttempts {
-			w.log.Error().Uint64("height", uint64(indexHeight.Height)).Msg("failed to parse block, reached max attempts")
-			prometheus.IndexerFailedBlocks.WithLabelValues(w.cfg.Name).Inc()
+		if countsAsAttempt(indexErr) {
+			indexHeight.Attempts += 1
+		}
+		if w.giveUpBlock(indexHeight, indexErr) {
 			return
 		}
 
-		w.log.Info().Uint64("height", uint64(indexHeight.Height)).Msg("re-enqueue block")
-		w.heightsQueue.DelayedEnqueue(ctx, w.cfg.TimeBeforeRetry, indexHeight)
+		delay := w.retryPolicy.GetDelay(indexHeight.Attempts, indexErr)
+		w.log.Info().
+			Uint64("height", uint64(indexHeight.Height)).
+			Dur("delay", delay).
+			Msg("re-enqueue block")
+		w.heightsQueue.DelayedEnqueue(ctx, delay, indexHeight)
+	}
+}
+
+// giveUpBlock checks if the worker should stop trying to index the provided block
+// after a failed attempt, in which case the block is stored as failed.
+func (w *Worker) giveUpBlock(indexHeight IndexerHeight, indexErr error) bool {
+	// Don't retry the blocks that can't be indexed
+	if classifyError(indexErr) == errorClassSkip {
+		w.log.Error().Uint64("height", uint64(indexHeight.Height)).Msg("failed to parse block, skipping it")
+		prometheus.IndexerFailedBlocks.WithLabelValues(w.cfg.Name).Inc()
+		w.saveFailedBlock(indexHeight, indexErr)
+		return true
+	}
+
+	if indexHeight.Attempts >= w.cfg.MaxAttempts {
+		w.log.Error().Uint64("height", uint64(indexHeight.Height)).Msg("failed to parse block, reached max attempts")
+		prometheus.IndexerFailedBlocks.WithLabelValues(w.cfg.Name).Inc()
+		w.saveFailedBlock(indexHeight, indexErr)
+		return true
+	}
+
+	return false
+}
+
+// saveFailedBlock stores the block that the worker failed to index into the
+// database, so that it can be inspected and retried later.
+func (w *Worker) saveFailedBlock(indexHeight IndexerHeight, indexErr error) {
+	var failedModule string
+	var moduleErr *ModuleError
+	if errors.As(indexErr, &moduleErr) {
+		failedModule = moduleErr.Module
+	}
+
+	err := w.db.SaveFailedBlock(w.cfg.Name, w.node.GetChainID(), database.NewFailedBlock(
+		indexHeight.Height,
+		failedModule,
+		indexErr.Error(),
+		indexHeight.Attempts,
+		time.Now(),
+	))
+	if err != nil {
+		w.log.Err(err).Uint64("height", uint64(indexHeight.Height)).Msg("save failed block")
 	}
 }
@@ -0,0 +1,550 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/database"
+	"github.com/milkyway-labs/flux/database/inmemory"
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/types"
+)
+
+const (
+	testIndexerName = "test-indexer"
+	testChainID     = "test-1"
+)
+
+var (
+	_ types.Block               = testBlock{}
+	_ node.Node                 = &testNode{}
+	_ modules.ReorgHandler      = &testModule{}
+	_ modules.BlockHandleModule = &testModule{}
+	_ modules.DependentModule   = &testModule{}
+)
+
+// testBlock is a block without transactions.
+type testBlock struct {
+	height     types.Height
+	hash       string
+	parentHash string
+}
+
+func (b testBlock) GetChainID() string {
+	return testChainID
+}
+
+func (b testBlock) GetHeight() types.Height {
+	return b.height
+}
+
+func (b testBlock) GetHash() string {
+	return b.hash
+}
+
+func (b testBlock) GetParentHash() string {
+	return b.parentHash
+}
+
+func (b testBlock) GetTimeStamp() time.Time {
+	return time.Unix(int64(b.height), 0).UTC()
+}
+
+func (b testBlock) GetTxs() []types.Tx {
+	return nil
+}
+
+// testNode is a node serving the blocks of a chain that can be reorganized.
+// The hash of each block is made of the branch to which the block belongs and its height.
+type testNode struct {
+	mu            sync.Mutex
+	currentHeight types.Height
+	// branches contains the heights from which each branch starts, sorted by height.
+	branches []testBranch
+}
+
+type testBranch struct {
+	name string
+	from types.Height
+}
+
+func newTestNode(currentHeight types.Height) *testNode {
+	return &testNode{
+		currentHeight: currentHeight,
+		branches:      []testBranch{{name: "a", from: 0}},
+	}
+}
+
+// reorg replaces the blocks starting from the provided height with the ones of a new branch.
+func (n *testNode) reorg(from types.Height, branch string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.branches = append(n.branches, testBranch{name: branch, from: from})
+}
+
+// getHash gets the hash of the canonical block at the provided height.
+func (n *testNode) getHash(height types.Height) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var branch string
+	for _, b := range n.branches {
+		if height >= b.from {
+			branch = b.name
+		}
+	}
+	return fmt.Sprintf("%s-%d", branch, height)
+}
+
+func (n *testNode) GetChainID() string {
+	return testChainID
+}
+
+func (n *testNode) GetBlock(_ context.Context, height types.Height) (types.Block, error) {
+	if height > n.currentHeight {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+
+	block := testBlock{height: height, hash: n.getHash(height)}
+	if height > 1 {
+		block.parentHash = n.getHash(height - 1)
+	}
+	return block, nil
+}
+
+func (n *testNode) GetLowestHeight(context.Context) (types.Height, error) {
+	return 1, nil
+}
+
+func (n *testNode) GetCurrentHeight(context.Context) (types.Height, error) {
+	return n.currentHeight, nil
+}
+
+// testModule is a module that, for each block, writes inside the BlockTx that
+// the block has been handled, using testModule.dataKey as module name.
+type testModule struct {
+	name string
+	// fail, if set, returns the error returned after the module has written the block.
+	fail func(height types.Height) error
+	// dependencies contains the names of the modules that must process a block before this one.
+	dependencies []string
+
+	mu        sync.Mutex
+	handled   map[types.Height]int
+	rollbacks []types.Height
+}
+
+func newTestModule(name string) *testModule {
+	return &testModule{
+		name:    name,
+		handled: make(map[types.Height]int),
+	}
+}
+
+func (m *testModule) GetName() string {
+	return m.name
+}
+
+func (m *testModule) GetDependencies() []string {
+	return m.dependencies
+}
+
+// dataKey gets the key under which the data written by the module are stored.
+func (m *testModule) dataKey() string {
+	return m.name + "-data"
+}
+
+func (m *testModule) HandleBlock(ctx context.Context, block types.Block) error {
+	tx, ok := database.GetBlockTx(ctx)
+	if !ok {
+		return fmt.Errorf("block tx not found")
+	}
+
+	err := tx.SaveModuleIndexedBlock(testIndexerName, testChainID, m.dataKey(), block.GetHeight())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.handled[block.GetHeight()]++
+	m.mu.Unlock()
+
+	if m.fail != nil {
+		return m.fail(block.GetHeight())
+	}
+	return nil
+}
+
+func (m *testModule) OnRollback(_ context.Context, fromHeight types.Height) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rollbacks = append(m.rollbacks, fromHeight)
+	return nil
+}
+
+// getHandled gets the number of times the module handled the block at the provided height.
+func (m *testModule) getHandled(height types.Height) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.handled[height]
+}
+
+// getRollbacks gets the heights from which the module has been rolled back.
+func (m *testModule) getRollbacks() []types.Height {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]types.Height(nil), m.rollbacks...)
+}
+
+// newTestIndexerConfig creates an indexer configuration that retries the
+// failed blocks almost immediately.
+func newTestIndexerConfig() *types.IndexerConfig {
+	cfg := types.DefaultIndexerCfg
+	cfg.Name = testIndexerName
+	cfg.MaxAttempts = 3
+	cfg.TimeBeforeRetry = time.Millisecond
+	cfg.RetryPolicy.MaxDelay = 10 * time.Millisecond
+	cfg.RetryPolicy.Jitter = 0
+	return &cfg
+}
+
+// newTestDatabase creates an in-memory database that keeps the details of all the blocks.
+func newTestDatabase() *inmemory.Database {
+	cfg := inmemory.NewConfig(0)
+	return inmemory.NewDatabase(&cfg)
+}
+
+// startTestWorkers starts the provided number of workers, sharing the same queue
+// and reorg lock. The returned function stops the workers and waits for them to terminate.
+func startTestWorkers(
+	cfg *types.IndexerConfig,
+	count int,
+	queue *Queue[IndexerHeight],
+	db database.Database,
+	node node.Node,
+	indexerModules []modules.Module,
+	errorPolicies map[string]types.ModuleErrorPolicy,
+) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	reorgLock := &sync.Mutex{}
+	for range count {
+		worker := NewWorker(cfg, zerolog.Nop(), queue, db, node, indexerModules, errorPolicies, reorgLock)
+		worker.Start(ctx, wg)
+	}
+
+	return func() {
+		cancel()
+		// Unblock the workers waiting for a height
+		queue.Close()
+		wg.Wait()
+	}
+}
+
+// enqueueHeights enqueues the heights in the provided range, inclusive.
+func enqueueHeights(queue *Queue[IndexerHeight], from types.Height, to types.Height) {
+	for height := from; height <= to; height++ {
+		queue.Enqueue(NewIndexerHeight(height))
+	}
+}
+
+// requireMissingRanges waits until the heights missing in the [from, to] range
+// are the expected ones.
+func requireMissingRanges(t *testing.T, db database.Database, from types.Height, to types.Height, expected []database.HeightRange) {
+	t.Helper()
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		missing, err := db.GetMissingRanges(testIndexerName, testChainID, from, to)
+		assert.NoError(c, err)
+		assert.Equal(c, expected, missing)
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+// requireModuleMissingRanges waits until the heights that the module with the provided
+// name has not processed in the [from, to] range are the expected ones.
+func requireModuleMissingRanges(
+	t *testing.T,
+	db database.Database,
+	module string,
+	from types.Height,
+	to types.Height,
+	expected []database.HeightRange,
+) {
+	t.Helper()
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		missing, err := db.GetModuleMissingRanges(testIndexerName, testChainID, module, from, to)
+		assert.NoError(c, err)
+		assert.Equal(c, expected, missing)
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+// newTestWorker creates a worker that uses the provided queue, database and modules.
+func newTestWorker(
+	queue *Queue[IndexerHeight],
+	db database.Database,
+	node node.Node,
+	indexerModules []modules.Module,
+	errorPolicies map[string]types.ModuleErrorPolicy,
+) Worker {
+	return NewWorker(newTestIndexerConfig(), zerolog.Nop(), queue, db, node, indexerModules, errorPolicies, &sync.Mutex{})
+}
+
+func TestWorkerIndexBlockCommitsAllWrites(t *testing.T) {
+	db := newTestDatabase()
+	first := newTestModule("first")
+	second := newTestModule("second")
+	worker := newTestWorker(NewQueue[IndexerHeight](10), db, newTestNode(10), []modules.Module{first, second}, nil)
+
+	err := worker.fetchAndProcessBlock(context.Background(), NewIndexerHeight(5))
+	require.NoError(t, err)
+
+	// The block, the modules progress and the modules data must be stored together
+	block, err := db.GetIndexedBlock(testIndexerName, testChainID, 5)
+	require.NoError(t, err)
+	require.Equal(t, database.NewIndexedBlock(5, "a-5", "a-4", time.Unix(5, 0).UTC()), *block)
+	for _, module := range []string{first.GetName(), first.dataKey(), second.GetName(), second.dataKey()} {
+		missing, err := db.GetModuleMissingRanges(testIndexerName, testChainID, module, 5, 5)
+		require.NoError(t, err)
+		require.Empty(t, missing, module)
+	}
+}
+
+func TestWorkerIndexBlockRollsBackOnModuleError(t *testing.T) {
+	db := newTestDatabase()
+	first := newTestModule("first")
+	second := newTestModule("second")
+	second.fail = func(types.Height) error {
+		return fmt.Errorf("module error")
+	}
+	second.dependencies = []string{first.GetName()}
+	worker := newTestWorker(NewQueue[IndexerHeight](10), db, newTestNode(10), []modules.Module{first, second}, nil)
+
+	err := worker.fetchAndProcessBlock(context.Background(), NewIndexerHeight(5))
+	require.ErrorContains(t, err, "module error")
+
+	// The first module has written its data before the second failed
+	require.Equal(t, 1, first.getHandled(5))
+	require.Equal(t, 1, second.getHandled(5))
+
+	// None of the writes must have been stored
+	block, err := db.GetIndexedBlock(testIndexerName, testChainID, 5)
+	require.NoError(t, err)
+	require.Nil(t, block)
+	for _, module := range []string{first.GetName(), first.dataKey(), second.GetName(), second.dataKey()} {
+		missing, err := db.GetModuleMissingRanges(testIndexerName, testChainID, module, 5, 5)
+		require.NoError(t, err)
+		require.Equal(t, []database.HeightRange{database.NewHeightRange(5, 5)}, missing, module)
+	}
+}
+
+func TestWorkerProcessesOnlyTheSelectedModules(t *testing.T) {
+	db := newTestDatabase()
+	first := newTestModule("first")
+	second := newTestModule("second")
+	worker := newTestWorker(NewQueue[IndexerHeight](10), db, newTestNode(10), []modules.Module{first, second}, nil)
+
+	err := worker.fetchAndProcessBlock(context.Background(), NewModulesIndexerHeight(5, []string{second.GetName()}))
+	require.NoError(t, err)
+
+	require.Equal(t, 0, first.getHandled(5))
+	require.Equal(t, 1, second.getHandled(5))
+
+	// The block is indexed, but only the selected module has processed it
+	block, err := db.GetIndexedBlock(testIndexerName, testChainID, 5)
+	require.NoError(t, err)
+	require.NotNil(t, block)
+	missing, err := db.GetModuleMissingRanges(testIndexerName, testChainID, first.GetName(), 5, 5)
+	require.NoError(t, err)
+	require.Equal(t, []database.HeightRange{database.NewHeightRange(5, 5)}, missing)
+	missing, err = db.GetModuleMissingRanges(testIndexerName, testChainID, second.GetName(), 5, 5)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func TestWorkerRunsDependentModulesInOrder(t *testing.T) {
+	mu := sync.Mutex{}
+	var order []string
+	newOrderedModule := func(name string, delay time.Duration, dependencies ...string) *testModule {
+		module := newTestModule(name)
+		module.dependencies = dependencies
+		module.fail = func(types.Height) error {
+			time.Sleep(delay)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+		return module
+	}
+
+	// Without the dependencies the slower modules would complete after the faster ones
+	first := newOrderedModule("first", 20*time.Millisecond)
+	second := newOrderedModule("second", 10*time.Millisecond, "first")
+	third := newOrderedModule("third", 0, "second")
+	worker := newTestWorker(NewQueue[IndexerHeight](10), newTestDatabase(), newTestNode(10), []modules.Module{first, second, third}, nil)
+
+	var expected []string
+	for height := types.Height(1); height <= 3; height++ {
+		err := worker.fetchAndProcessBlock(context.Background(), NewIndexerHeight(height))
+		require.NoError(t, err)
+		expected = append(expected, "first", "second", "third")
+	}
+	require.Equal(t, expected, order)
+}
+
+func TestWorkerSavesFailedBlockAfterMaxAttempts(t *testing.T) {
+	db := newTestDatabase()
+	module := newTestModule("module")
+	module.fail = func(types.Height) error {
+		return fmt.Errorf("module error")
+	}
+	queue := NewQueue[IndexerHeight](10)
+	cfg := newTestIndexerConfig()
+	stop := startTestWorkers(cfg, 2, queue, db, newTestNode(10), []modules.Module{module}, nil)
+	defer stop()
+
+	queue.Enqueue(NewIndexerHeight(5))
+
+	var failedBlocks []database.FailedBlock
+	require.Eventually(t, func() bool {
+		var err error
+		failedBlocks, err = db.GetFailedBlocks(testIndexerName, testChainID)
+		return err == nil && len(failedBlocks) > 0
+	}, 5*time.Second, 5*time.Millisecond)
+
+	require.Len(t, failedBlocks, 1)
+	require.Equal(t, types.Height(5), failedBlocks[0].Height)
+	require.Equal(t, module.GetName(), failedBlocks[0].Module)
+	require.Equal(t, cfg.MaxAttempts, failedBlocks[0].Attempts)
+	require.Contains(t, failedBlocks[0].Error, "module error")
+	require.Equal(t, int(cfg.MaxAttempts), module.getHandled(5))
+
+	block, err := db.GetIndexedBlock(testIndexerName, testChainID, 5)
+	require.NoError(t, err)
+	require.Nil(t, block)
+}
+
+func TestWorkerHandlesErrorClasses(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		// failures is the number of attempts that fail before the module succeeds
+		failures         int
+		policy           types.ModuleErrorPolicy
+		expectedHandled  int
+		expectedAttempts uint32
+		expectIndexed    bool
+	}{
+		{
+			name:             "skip block error is not retried",
+			err:              types.NewSkipBlockError(fmt.Errorf("malformed block")),
+			failures:         10,
+			policy:           types.ModuleErrorPolicyFail,
+			expectedHandled:  1,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "unknown error is retried until max attempts",
+			err:              fmt.Errorf("generic error"),
+			failures:         10,
+			policy:           types.ModuleErrorPolicyFail,
+			expectedHandled:  3,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "retryable error is retried until max attempts",
+			err:              fmt.Errorf("handle block: %w", types.ErrRetryable),
+			failures:         10,
+			policy:           types.ModuleErrorPolicyFail,
+			expectedHandled:  3,
+			expectedAttempts: 3,
+		},
+		{
+			name:            "rate limited error doesn't count as attempt",
+			err:             types.NewRateLimitedError(fmt.Errorf("rate limited"), 0),
+			failures:        5,
+			policy:          types.ModuleErrorPolicyFail,
+			expectedHandled: 6,
+			expectIndexed:   true,
+		},
+		{
+			name:             "unknown error is not retried by skip module",
+			err:              fmt.Errorf("generic error"),
+			failures:         1,
+			policy:           types.ModuleErrorPolicySkip,
+			expectedHandled:  1,
+			expectedAttempts: 1,
+			expectIndexed:    true,
+		},
+		{
+			name:            "retryable error is retried by skip module",
+			err:             fmt.Errorf("handle block: %w", types.ErrRetryable),
+			failures:        2,
+			policy:          types.ModuleErrorPolicySkip,
+			expectedHandled: 3,
+			expectIndexed:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDatabase()
+			module := newTestModule("module")
+			failures := atomic.Int32{}
+			module.fail = func(types.Height) error {
+				if failures.Add(1) <= int32(tc.failures) {
+					return tc.err
+				}
+				return nil
+			}
+			queue := NewQueue[IndexerHeight](10)
+			policies := map[string]types.ModuleErrorPolicy{module.GetName(): tc.policy}
+			stop := startTestWorkers(newTestIndexerConfig(), 2, queue, db, newTestNode(10), []modules.Module{module}, policies)
+			defer stop()
+
+			queue.Enqueue(NewIndexerHeight(5))
+
+			// Wait until the block has been processed or stored as failed
+			require.Eventually(t, func() bool {
+				missing, err := db.GetModuleMissingRanges(testIndexerName, testChainID, module.GetName(), 5, 5)
+				if err == nil && len(missing) == 0 {
+					return true
+				}
+				failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+				return err == nil && len(failedBlocks) > 0
+			}, 5*time.Second, 5*time.Millisecond)
+			// Make sure no other attempt is performed
+			time.Sleep(50 * time.Millisecond)
+
+			require.Equal(t, tc.expectedHandled, module.getHandled(5))
+
+			failedBlocks, err := db.GetFailedBlocks(testIndexerName, testChainID)
+			require.NoError(t, err)
+			if tc.expectedAttempts > 0 {
+				require.Len(t, failedBlocks, 1)
+				require.Equal(t, tc.expectedAttempts, failedBlocks[0].Attempts)
+				require.Equal(t, module.GetName(), failedBlocks[0].Module)
+			} else {
+				require.Empty(t, failedBlocks)
+			}
+
+			block, err := db.GetIndexedBlock(testIndexerName, testChainID, 5)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectIndexed, block != nil)
+		})
+	}
+}
@@ -43,3 +43,8 @@ func (b *BlockHandleAdapter[B]) HandleBlock(ctx context.Context, block types.Blo
 
 	return b.handler.HandleBlock(ctx, cosmosblock)
 }
+
+// Unwrap implements modules.WrapperModule.
+func (b *BlockHandleAdapter[B]) Unwrap() modules.Module {
+	return b.handler
+}
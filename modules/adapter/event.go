@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// EventHandleModule represent a module that index data by extracting them from
+// the events emitted by a block and its transactions.
+type EventHandleModule[B types.Block, T types.Tx, E types.Event] interface {
+	modules.Module
+	// GetEventFilters gets the filters used to select the events passed to HandleEvent.
+	GetEventFilters() []modules.EventFilter
+	// HandleEvent process the provided event emitted by the given tx included in the block.
+	// If the event has been emitted outside of the block transactions tx is the zero value.
+	HandleEvent(ctx context.Context, block B, tx T, event E) error
+}
+
+// ----------------------------------------------------------------------------
+// ---- Event module adapter
+// ----------------------------------------------------------------------------
+
+type EventHandleAdapter[B types.Block, T types.Tx, E types.Event] struct {
+	handler EventHandleModule[B, T, E]
+}
+
+func NewEventHandleAdapter[B types.Block, T types.Tx, E types.Event](handler EventHandleModule[B, T, E]) modules.EventHandleModule {
+	return &EventHandleAdapter[B, T, E]{
+		handler: handler,
+	}
+}
+
+// GetName implements modules.EventHandleModule.
+func (b *EventHandleAdapter[B, T, E]) GetName() string {
+	return b.handler.GetName()
+}
+
+// GetEventFilters implements modules.EventHandleModule.
+func (b *EventHandleAdapter[B, T, E]) GetEventFilters() []modules.EventFilter {
+	return b.handler.GetEventFilters()
+}
+
+// HandleEvent implements modules.EventHandleModule.
+func (b *EventHandleAdapter[B, T, E]) HandleEvent(ctx context.Context, block types.Block, tx types.Tx, event types.Event) error {
+	castedBlock, ok := block.(B)
+	if !ok {
+		return nil
+	}
+	var castedTx T
+	if tx != nil {
+		castedTx, ok = tx.(T)
+		if !ok {
+			return nil
+		}
+	}
+	castedEvent, ok := event.(E)
+	if !ok {
+		return nil
+	}
+
+	return b.handler.HandleEvent(ctx, castedBlock, castedTx, castedEvent)
+}
+
+// Unwrap implements modules.WrapperModule.
+func (b *EventHandleAdapter[B, T, E]) Unwrap() modules.Module {
+	return b.handler
+}
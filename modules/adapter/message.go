@@ -0,0 +1,72 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/milkyway-labs/flux/modules"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// MessageHandleModule represent a module that index data by extracting them from
+// the messages included in the block transactions.
+type MessageHandleModule[B types.Block, T types.Tx, M types.Message] interface {
+	modules.Module
+	// GetMessageTypes gets the types of the messages passed to HandleMessage.
+	GetMessageTypes() []string
+	// HandleMessage process the provided message, that is the one at the
+	// given index of the tx included in the provided block.
+	HandleMessage(ctx context.Context, block B, tx T, index int, message M) error
+}
+
+// ----------------------------------------------------------------------------
+// ---- Message module adapter
+// ----------------------------------------------------------------------------
+
+type MessageHandleAdapter[B types.Block, T types.Tx, M types.Message] struct {
+	handler MessageHandleModule[B, T, M]
+}
+
+func NewMessageHandleAdapter[B types.Block, T types.Tx, M types.Message](handler MessageHandleModule[B, T, M]) modules.MessageHandleModule {
+	return &MessageHandleAdapter[B, T, M]{
+		handler: handler,
+	}
+}
+
+// GetName implements modules.MessageHandleModule.
+func (b *MessageHandleAdapter[B, T, M]) GetName() string {
+	return b.handler.GetName()
+}
+
+// GetMessageTypes implements modules.MessageHandleModule.
+func (b *MessageHandleAdapter[B, T, M]) GetMessageTypes() []string {
+	return b.handler.GetMessageTypes()
+}
+
+// HandleMessage implements modules.MessageHandleModule.
+func (b *MessageHandleAdapter[B, T, M]) HandleMessage(
+	ctx context.Context,
+	block types.Block,
+	tx types.Tx,
+	index int,
+	message types.Message,
+) error {
+	castedBlock, ok := block.(B)
+	if !ok {
+		return nil
+	}
+	castedTx, ok := tx.(T)
+	if !ok {
+		return nil
+	}
+	castedMessage, ok := message.(M)
+	if !ok {
+		return nil
+	}
+
+	return b.handler.HandleMessage(ctx, castedBlock, castedTx, index, castedMessage)
+}
+
+// Unwrap implements modules.WrapperModule.
+func (b *MessageHandleAdapter[B, T, M]) Unwrap() modules.Module {
+	return b.handler
+}
@@ -45,3 +45,8 @@ func (b *TxHandleAdapter[B, T]) HandleTx(ctx context.Context, block types.Block,
 
 	return b.handler.HandleTx(ctx, castedBlock, castedTx)
 }
+
+// Unwrap implements modules.WrapperModule.
+func (b *TxHandleAdapter[B, T]) Unwrap() modules.Module {
+	return b.handler
+}
@@ -13,3 +13,14 @@ type BlockHandleModule interface {
 	// HandleBlock process the provided block.
 	HandleBlock(ctx context.Context, block types.Block) error
 }
+
+// PendingBlockHandleModule represent a module that wants to receive the blocks
+// produced by the chain that are not final yet.
+// A pending block can still be replaced by the chain, so the data extracted
+// from it should be treated as provisional. Once final, the block is
+// delivered through the regular handlers.
+type PendingBlockHandleModule interface {
+	Module
+	// HandlePendingBlock process the provided not yet final block.
+	HandlePendingBlock(ctx context.Context, block types.Block) error
+}
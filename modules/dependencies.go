@@ -0,0 +1,68 @@
+package modules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GetDependencies gets the names of the modules on which the provided module depends.
+// If the module doesn't implement the DependentModule interface, nil is returned.
+func GetDependencies(module Module) []string {
+	if dependentModule, ok := As[DependentModule](module); ok {
+		return dependentModule.GetDependencies()
+	}
+	return nil
+}
+
+// ValidateDependencies ensures that the dependencies declared by the provided
+// modules are part of the list and that they don't form a cycle.
+func ValidateDependencies(modules []Module) error {
+	modulesByName := make(map[string]Module, len(modules))
+	for _, module := range modules {
+		modulesByName[module.GetName()] = module
+	}
+
+	for _, module := range modules {
+		for _, dependency := range GetDependencies(module) {
+			if _, found := modulesByName[dependency]; !found {
+				return fmt.Errorf("module %s depends on module %s which is not enabled", module.GetName(), dependency)
+			}
+		}
+	}
+
+	// Detect the cycles with a depth-first visit of the graph, keeping
+	// track of the modules that are part of the current path
+	visited := make(map[string]bool, len(modules))
+	var path []string
+	var visit func(module Module) error
+	visit = func(module Module) error {
+		name := module.GetName()
+		if index := slices.Index(path, name); index >= 0 {
+			cycle := append(slices.Clone(path[index:]), name)
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+		if visited[name] {
+			return nil
+		}
+
+		path = append(path, name)
+		for _, dependency := range GetDependencies(module) {
+			if err := visit(modulesByName[dependency]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		visited[name] = true
+
+		return nil
+	}
+
+	for _, module := range modules {
+		if err := visit(module); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testModule struct {
+	name         string
+	dependencies []string
+}
+
+func (m testModule) GetName() string {
+	return m.name
+}
+
+func (m testModule) GetDependencies() []string {
+	return m.dependencies
+}
+
+func TestValidateDependencies(t *testing.T) {
+	testCases := []struct {
+		name      string
+		modules   []Module
+		shouldErr bool
+		errMsg    string
+	}{
+		{
+			name: "independent modules are valid",
+			modules: []Module{
+				testModule{name: "a"},
+				testModule{name: "b"},
+			},
+		},
+		{
+			name: "dependencies declared in any order are valid",
+			modules: []Module{
+				testModule{name: "c", dependencies: []string{"a", "b"}},
+				testModule{name: "b", dependencies: []string{"a"}},
+				testModule{name: "a"},
+			},
+		},
+		{
+			name: "missing dependency returns error",
+			modules: []Module{
+				testModule{name: "a", dependencies: []string{"b"}},
+			},
+			shouldErr: true,
+			errMsg:    "module a depends on module b which is not enabled",
+		},
+		{
+			name: "self dependency returns error",
+			modules: []Module{
+				testModule{name: "a", dependencies: []string{"a"}},
+			},
+			shouldErr: true,
+			errMsg:    "dependency cycle detected: a -> a",
+		},
+		{
+			name: "cycle returns error",
+			modules: []Module{
+				testModule{name: "a", dependencies: []string{"b"}},
+				testModule{name: "b", dependencies: []string{"c"}},
+				testModule{name: "c", dependencies: []string{"a"}},
+			},
+			shouldErr: true,
+			errMsg:    "dependency cycle detected: a -> b -> c -> a",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDependencies(tc.modules)
+			if tc.shouldErr {
+				require.EqualError(t, err, tc.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
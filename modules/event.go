@@ -0,0 +1,86 @@
+package modules
+
+import (
+	"context"
+	"maps"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+// EventFilter represents a filter used to select the events that are passed
+// to an EventHandleModule.
+type EventFilter struct {
+	// Type of the events to select, if empty the events of any type are selected.
+	Type string
+	// Attributes that the selected events must contain, by key.
+	// If a value is empty, only the presence of the attribute is checked.
+	Attributes map[string]string
+}
+
+// NewEventTypeFilter creates a new EventFilter that selects the events with the provided type.
+func NewEventTypeFilter(eventType string) EventFilter {
+	return EventFilter{
+		Type: eventType,
+	}
+}
+
+// WithAttribute returns a copy of the filter that selects only the events
+// containing an attribute with the provided key and value.
+// If value is empty, only the presence of the attribute is checked.
+func (f EventFilter) WithAttribute(key string, value string) EventFilter {
+	attributes := maps.Clone(f.Attributes)
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	attributes[key] = value
+
+	return EventFilter{
+		Type:       f.Type,
+		Attributes: attributes,
+	}
+}
+
+// Matches returns true if the provided event is selected by the filter.
+func (f EventFilter) Matches(event types.Event) bool {
+	if f.Type != "" && f.Type != event.GetType() {
+		return false
+	}
+
+	for key, expected := range f.Attributes {
+		value, found := event.GetAttributeValue(key)
+		if !found || (expected != "" && value != expected) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MatchesAnyEventFilter returns true if the provided event is selected by at
+// least one of the given filters. If no filters are provided, all the events are selected.
+func MatchesAnyEventFilter(filters []EventFilter, event types.Event) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, filter := range filters {
+		if filter.Matches(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EventHandleModule represent a module that index data by extracting them from
+// the events emitted by a block and its transactions.
+type EventHandleModule interface {
+	Module
+	// GetEventFilters gets the filters used to select the events passed to
+	// HandleEvent, an event is passed if it matches at least one of them.
+	// If no filters are returned, all the events are passed.
+	GetEventFilters() []EventFilter
+	// HandleEvent process the provided event emitted by the given tx included in the block.
+	// If the event has been emitted outside of the block transactions tx is nil.
+	HandleEvent(ctx context.Context, block types.Block, tx types.Tx, event types.Event) error
+}
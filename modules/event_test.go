@@ -0,0 +1,89 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	eventType  string
+	attributes map[string]string
+}
+
+func (e testEvent) GetType() string {
+	return e.eventType
+}
+
+func (e testEvent) GetAttributeValue(key string) (string, bool) {
+	value, found := e.attributes[key]
+	return value, found
+}
+
+func TestEventFilterMatches(t *testing.T) {
+	event := testEvent{
+		eventType:  "transfer",
+		attributes: map[string]string{"sender": "alice", "amount": "10stake"},
+	}
+
+	testCases := []struct {
+		name     string
+		filter   EventFilter
+		expected bool
+	}{
+		{
+			name:     "empty filter matches any event",
+			filter:   EventFilter{},
+			expected: true,
+		},
+		{
+			name:     "type filter matches the event type",
+			filter:   NewEventTypeFilter("transfer"),
+			expected: true,
+		},
+		{
+			name:     "type filter doesn't match other types",
+			filter:   NewEventTypeFilter("message"),
+			expected: false,
+		},
+		{
+			name:     "attribute filter matches the attribute value",
+			filter:   NewEventTypeFilter("transfer").WithAttribute("sender", "alice"),
+			expected: true,
+		},
+		{
+			name:     "attribute filter doesn't match other values",
+			filter:   NewEventTypeFilter("transfer").WithAttribute("sender", "bob"),
+			expected: false,
+		},
+		{
+			name:     "attribute filter with empty value checks the presence",
+			filter:   NewEventTypeFilter("transfer").WithAttribute("amount", ""),
+			expected: true,
+		},
+		{
+			name:     "attribute filter doesn't match missing attributes",
+			filter:   NewEventTypeFilter("transfer").WithAttribute("recipient", ""),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.filter.Matches(event))
+		})
+	}
+}
+
+func TestMatchesAnyEventFilter(t *testing.T) {
+	event := testEvent{eventType: "transfer"}
+
+	require.True(t, MatchesAnyEventFilter(nil, event))
+	require.True(t, MatchesAnyEventFilter([]EventFilter{NewEventTypeFilter("message"), NewEventTypeFilter("transfer")}, event))
+	require.False(t, MatchesAnyEventFilter([]EventFilter{NewEventTypeFilter("message")}, event))
+
+	// WithAttribute doesn't modify the original filter
+	filter := NewEventTypeFilter("transfer")
+	_ = filter.WithAttribute("sender", "alice")
+	require.True(t, filter.Matches(event))
+}
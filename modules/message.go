@@ -0,0 +1,26 @@
+package modules
+
+import (
+	"context"
+	"slices"
+
+	"github.com/milkyway-labs/flux/types"
+)
+
+// MatchesMessageTypes returns true if the type of the provided message is one
+// of the given types. If no types are provided, all the messages are selected.
+func MatchesMessageTypes(messageTypes []string, message types.Message) bool {
+	return len(messageTypes) == 0 || slices.Contains(messageTypes, message.GetType())
+}
+
+// MessageHandleModule represent a module that index data by extracting them from
+// the messages included in the block transactions.
+type MessageHandleModule interface {
+	Module
+	// GetMessageTypes gets the types of the messages passed to HandleMessage.
+	// If no types are returned, all the messages are passed.
+	GetMessageTypes() []string
+	// HandleMessage process the provided message, that is the one at the
+	// given index of the tx included in the provided block.
+	HandleMessage(ctx context.Context, block types.Block, tx types.Tx, index int, message types.Message) error
+}
@@ -1,6 +1,11 @@
 package modules
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/milkyway-labs/flux/types"
+)
 
 // Module represent a module used to index a block chain.
 type Module interface {
@@ -8,8 +13,93 @@ type Module interface {
 	GetName() string
 }
 
+// WrapperModule represents a module that wraps another module, like the
+// adapters. The indexer looks for the optional interfaces, such as the hooks,
+// also in the wrapped module.
+type WrapperModule interface {
+	Module
+	// Unwrap gets the wrapped module.
+	Unwrap() Module
+}
+
+// As finds the first module in the chain of wrapped modules, starting from the
+// provided one, that implements T.
+func As[T any](module Module) (T, bool) {
+	for module != nil {
+		if target, ok := module.(T); ok {
+			return target, true
+		}
+
+		wrapper, ok := module.(WrapperModule)
+		if !ok {
+			break
+		}
+		module = wrapper.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
 // IndexerStartHook represents a hook that is called when the indexer with the
 // module is started.
 type IndexerStartHook interface {
 	OnIndexerStart(ctx context.Context) error
 }
+
+// IndexerStopHook represents a hook that is called when the indexer with the
+// module is stopped, once all the blocks being processed have been handled.
+type IndexerStopHook interface {
+	// OnIndexerStop is called with a context that is not canceled together
+	// with the indexer, so that the module can flush its data.
+	OnIndexerStop(ctx context.Context) error
+}
+
+// BeforeBlockHook represents a hook that is called before the module
+// processes a block.
+type BeforeBlockHook interface {
+	// BeforeBlock is called before the block and its transactions are passed
+	// to the module. Returning an error fails the module processing of the block.
+	BeforeBlock(ctx context.Context, block types.Block) error
+}
+
+// AfterBlockHook represents a hook that is called after the module
+// processes a block.
+type AfterBlockHook interface {
+	// AfterBlock is called after the block and its transactions have been passed
+	// to the module, inside the same BlockTx. Returning an error fails the module
+	// processing of the block.
+	AfterBlock(ctx context.Context, block types.Block) error
+}
+
+// PeriodicHook represents a hook that is called periodically while
+// the indexer with the module is running.
+type PeriodicHook interface {
+	// GetTickInterval gets the interval between two calls of OnTick.
+	// A zero interval disables the hook.
+	GetTickInterval() time.Duration
+	// OnTick is called every tick interval.
+	OnTick(ctx context.Context) error
+}
+
+// ReorgHandler represents a module that needs to be notified when the indexer
+// detects a chain reorganization.
+type ReorgHandler interface {
+	// OnRollback is called when the blocks starting from the provided height
+	// have been replaced by the chain. The module should remove all the data
+	// extracted from the blocks having a height greater or equal to fromHeight,
+	// since they will be indexed again from the canonical chain.
+	OnRollback(ctx context.Context, fromHeight types.Height) error
+}
+
+// DependentModule represents a module that must process a block only after
+// other modules of the same indexer have processed it.
+// The modules that don't depend on each other process the same block concurrently,
+// regardless of their order inside the config, so the modules that rely on the data
+// written or the state shared by other modules must declare them as dependencies.
+type DependentModule interface {
+	Module
+	// GetDependencies gets the names of the modules that must process
+	// a block before this module.
+	GetDependencies() []string
+}
@@ -0,0 +1,38 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testWrapperModule struct {
+	wrapped Module
+}
+
+func (m testWrapperModule) GetName() string {
+	return m.wrapped.GetName()
+}
+
+func (m testWrapperModule) Unwrap() Module {
+	return m.wrapped
+}
+
+func TestAs(t *testing.T) {
+	inner := testModule{name: "inner", dependencies: []string{"other"}}
+
+	// The module itself implements the interface
+	dependent, ok := As[DependentModule](inner)
+	require.True(t, ok)
+	require.Equal(t, []string{"other"}, dependent.GetDependencies())
+
+	// The interface is implemented by a wrapped module
+	dependent, ok = As[DependentModule](testWrapperModule{wrapped: testWrapperModule{wrapped: inner}})
+	require.True(t, ok)
+	require.Equal(t, []string{"other"}, dependent.GetDependencies())
+	require.Equal(t, []string{"other"}, GetDependencies(testWrapperModule{wrapped: inner}))
+
+	// No module in the chain implements the interface
+	_, ok = As[IndexerStartHook](testWrapperModule{wrapped: inner})
+	require.False(t, ok)
+}
@@ -0,0 +1,50 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/milkyway-labs/flux/node"
+	nodemanager "github.com/milkyway-labs/flux/node/manager"
+	"github.com/milkyway-labs/flux/types"
+)
+
+const NodeType = "composite"
+
+// NewNodeBuilder creates the builder of the composite nodes, the wrapped nodes
+// are built with the provided NodesManager.
+func NewNodeBuilder(nodesManager *nodemanager.NodesManager) nodemanager.Builder {
+	return func(ctx context.Context, id string, rawConfig []byte) (node.Node, error) {
+		// Parse the configurations
+		var config Config
+		err := yaml.Unmarshal(rawConfig, &config)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal %s node config: %w", NodeType, err)
+		}
+
+		// Validate the configurations
+		err = config.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s node config: %w", NodeType, err)
+		}
+
+		indexerCtx := types.GetIndexerContext(ctx)
+		nodes := make([]node.Node, len(config.Nodes))
+		for i, nodeID := range config.Nodes {
+			// Prevent an infinite recursion caused by nested composite nodes
+			if nodeType, _ := indexerCtx.Config.Nodes[nodeID]["type"].(string); nodeType == NodeType {
+				return nil, fmt.Errorf("node %s can't wrap the %s node %s", id, NodeType, nodeID)
+			}
+
+			wrapped, err := nodesManager.BuildNode(ctx, indexerCtx.Config, nodeID)
+			if err != nil {
+				return nil, fmt.Errorf("build node %s: %w", nodeID, err)
+			}
+			nodes[i] = wrapped
+		}
+
+		return NewNode(ctx, indexerCtx.Logger, config, nodes)
+	}
+}
@@ -0,0 +1,63 @@
+package composite
+
+import (
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	// Nodes contains the IDs of the wrapped nodes. Each block is requested to
+	// the first node, following this order, whose heights range contains it.
+	Nodes []string `yaml:"nodes"`
+	// RangeRefreshInterval represents the interval after which the range of
+	// heights that can be provided by each node is refreshed.
+	RangeRefreshInterval time.Duration `yaml:"range_refresh_interval"`
+}
+
+func NewConfig(nodes []string, rangeRefreshInterval time.Duration) Config {
+	return Config{
+		Nodes:                nodes,
+		RangeRefreshInterval: rangeRefreshInterval,
+	}
+}
+
+func DefaultConfig() Config {
+	return NewConfig(nil, time.Minute)
+}
+
+// Implements the Unmarshaler interface of the yaml pkg.
+func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
+	// Local type to avoid recursion during the unmarshal
+	type privateCfg Config
+	config := privateCfg(DefaultConfig())
+	err := unmarshal(&config)
+	if err != nil {
+		return err
+	}
+
+	*c = Config(config)
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if len(c.Nodes) == 0 {
+		return fmt.Errorf("nodes can't be empty")
+	}
+
+	seen := make(map[string]bool, len(c.Nodes))
+	for _, nodeID := range c.Nodes {
+		if nodeID == "" {
+			return fmt.Errorf("node id can't be empty")
+		}
+		if seen[nodeID] {
+			return fmt.Errorf("duplicated node %s", nodeID)
+		}
+		seen[nodeID] = true
+	}
+
+	if c.RangeRefreshInterval <= 0 {
+		return fmt.Errorf("range_refresh_interval must be > 0")
+	}
+
+	return nil
+}
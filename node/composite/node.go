@@ -0,0 +1,364 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/types"
+)
+
+var (
+	_ node.Node                          = &Node{}
+	_ node.FinalizedHeightProvider       = &Node{}
+	_ node.FinalizedHeightSupportChecker = &Node{}
+	_ node.HeightsSubscriber             = &Node{}
+)
+
+// wrappedNode represents one of the nodes wrapped by the composite node,
+// along with the range of heights it can provide.
+type wrappedNode struct {
+	id   string
+	node node.Node
+
+	// Tells if the range of heights has been fetched at least once.
+	hasRange      bool
+	lowestHeight  types.Height
+	currentHeight types.Height
+	// Time at which the range of heights has been refreshed.
+	refreshedAt time.Time
+}
+
+// covers tells if the node can provide the block at the given height.
+func (w *wrappedNode) covers(height types.Height) bool {
+	return w.hasRange && w.lowestHeight <= height && height <= w.currentHeight
+}
+
+// Node is a node.Node that wraps multiple nodes of the same chain and requests
+// each block to a node whose range of heights contains it. This allows
+// to serve the chain tip with pruned nodes and the old blocks with archive nodes.
+type Node struct {
+	cfg     Config
+	logger  zerolog.Logger
+	chainID string
+
+	mu    sync.Mutex
+	nodes []*wrappedNode
+}
+
+// NewNode creates a new composite Node, the provided nodes must be
+// in the same order of the IDs defined in the config.
+func NewNode(ctx context.Context, logger zerolog.Logger, cfg Config, nodes []node.Node) (*Node, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes provided")
+	}
+	if len(nodes) != len(cfg.Nodes) {
+		return nil, fmt.Errorf("expected %d nodes, got %d", len(cfg.Nodes), len(nodes))
+	}
+
+	// Make sure that all the nodes serve the same chain
+	chainID := nodes[0].GetChainID()
+	wrappedNodes := make([]*wrappedNode, len(nodes))
+	for i, n := range nodes {
+		if n.GetChainID() != chainID {
+			return nil, fmt.Errorf("node %s serves chain %s instead of %s", cfg.Nodes[i], n.GetChainID(), chainID)
+		}
+		wrappedNodes[i] = &wrappedNode{id: cfg.Nodes[i], node: n}
+	}
+
+	compositeNode := &Node{
+		cfg:     cfg,
+		logger:  logger.With().Str("composite-node", chainID).Logger(),
+		chainID: chainID,
+		nodes:   wrappedNodes,
+	}
+
+	if err := compositeNode.refreshRanges(ctx, true); err != nil {
+		return nil, err
+	}
+
+	return compositeNode, nil
+}
+
+// GetChainID implements node.Node.
+func (n *Node) GetChainID() string {
+	return n.chainID
+}
+
+// GetCurrentHeight implements node.Node.
+// The height is the highest one reported by the wrapped nodes.
+func (n *Node) GetCurrentHeight(ctx context.Context) (types.Height, error) {
+	heights, err := n.queryNodes(ctx, n.nodes, func(ctx context.Context, w *wrappedNode) (types.Height, error) {
+		return w.node.GetCurrentHeight(ctx)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get current height: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var currentHeight types.Height
+	for i, w := range n.nodes {
+		if height, ok := heights[i]; ok {
+			w.currentHeight = height
+			currentHeight = max(currentHeight, height)
+		}
+	}
+
+	return currentHeight, nil
+}
+
+// SupportsFinalizedHeight implements node.FinalizedHeightSupportChecker.
+// The finalized height is supported if at least one of the wrapped nodes supports it.
+func (n *Node) SupportsFinalizedHeight() bool {
+	return len(n.getFinalizedHeightProviders()) > 0
+}
+
+// getFinalizedHeightProviders gets the wrapped nodes that can report the finalized height.
+func (n *Node) getFinalizedHeightProviders() []*wrappedNode {
+	var providers []*wrappedNode
+	for _, w := range n.nodes {
+		if node.SupportsFinalizedHeight(w.node) {
+			providers = append(providers, w)
+		}
+	}
+	return providers
+}
+
+// GetFinalizedHeight implements node.FinalizedHeightProvider.
+// The height is the highest one reported by the wrapped nodes that support
+// the finalized height.
+func (n *Node) GetFinalizedHeight(ctx context.Context) (types.Height, error) {
+	providers := n.getFinalizedHeightProviders()
+	if len(providers) == 0 {
+		return 0, fmt.Errorf("none of the wrapped nodes provides the finalized height")
+	}
+
+	heights, err := n.queryNodes(ctx, providers, func(ctx context.Context, w *wrappedNode) (types.Height, error) {
+		return w.node.(node.FinalizedHeightProvider).GetFinalizedHeight(ctx)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get finalized height: %w", err)
+	}
+
+	var finalizedHeight types.Height
+	for _, height := range heights {
+		finalizedHeight = max(finalizedHeight, height)
+	}
+	return finalizedHeight, nil
+}
+
+// SubscribeHeights implements node.HeightsSubscriber.
+// The subscription is performed with the first wrapped node that supports it.
+func (n *Node) SubscribeHeights(ctx context.Context) (<-chan types.Height, error) {
+	var errs []error
+	for _, w := range n.nodes {
+		subscriber, ok := w.node.(node.HeightsSubscriber)
+		if !ok {
+			continue
+		}
+
+		heights, err := subscriber.SubscribeHeights(ctx)
+		if err == nil {
+			return heights, nil
+		}
+		if !errors.Is(err, node.ErrSubscriptionNotSupported) {
+			errs = append(errs, fmt.Errorf("node %s: %w", w.id, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, node.ErrSubscriptionNotSupported
+}
+
+// GetLowestHeight implements node.Node.
+// The height is the lowest one reported by the wrapped nodes.
+func (n *Node) GetLowestHeight(ctx context.Context) (types.Height, error) {
+	heights, err := n.queryNodes(ctx, n.nodes, func(ctx context.Context, w *wrappedNode) (types.Height, error) {
+		return w.node.GetLowestHeight(ctx)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get lowest height: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	first := true
+	var lowestHeight types.Height
+	for i, w := range n.nodes {
+		if height, ok := heights[i]; ok {
+			w.lowestHeight = height
+			if first || height < lowestHeight {
+				lowestHeight = height
+				first = false
+			}
+		}
+	}
+
+	return lowestHeight, nil
+}
+
+// GetBlock implements node.Node.
+// The block is requested to the first node whose range of heights contains it,
+// in case of failure the request is sent to the other nodes that contain it.
+func (n *Node) GetBlock(ctx context.Context, height types.Height) (types.Block, error) {
+	if err := n.refreshRanges(ctx, false); err != nil {
+		n.logger.Warn().Err(err).Msg("refresh nodes ranges")
+	}
+
+	candidates := n.getCandidates(height)
+	if len(candidates) == 0 {
+		// The ranges might be outdated, refresh them before giving up
+		if err := n.refreshRanges(ctx, true); err != nil {
+			n.logger.Warn().Err(err).Msg("refresh nodes ranges")
+		}
+		candidates = n.getCandidates(height)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("none of the nodes can provide the block at height %d", height)
+	}
+
+	var errs []error
+	for _, w := range candidates {
+		block, err := w.node.GetBlock(ctx, height)
+		if err == nil {
+			return block, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("node %s: %w", w.id, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// getCandidates gets the nodes that can provide the block at the given height,
+// sorted following the config order. The nodes whose range is unknown are
+// returned last.
+func (n *Node) getCandidates(height types.Height) []*wrappedNode {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var candidates, unknown []*wrappedNode
+	for _, w := range n.nodes {
+		switch {
+		case w.covers(height):
+			candidates = append(candidates, w)
+		case !w.hasRange:
+			unknown = append(unknown, w)
+		}
+	}
+
+	return append(candidates, unknown...)
+}
+
+// refreshRanges refreshes the range of heights of the nodes that have not been
+// refreshed within the configured interval, or of all the nodes if force is true.
+// Returns an error if none of the refreshed nodes reported its range.
+func (n *Node) refreshRanges(ctx context.Context, force bool) error {
+	n.mu.Lock()
+	var toRefresh []*wrappedNode
+	for _, w := range n.nodes {
+		if force || time.Since(w.refreshedAt) >= n.cfg.RangeRefreshInterval {
+			// Update the refresh time to prevent concurrent refreshes of the same node
+			w.refreshedAt = time.Now()
+			toRefresh = append(toRefresh, w)
+		}
+	}
+	n.mu.Unlock()
+
+	if len(toRefresh) == 0 {
+		return nil
+	}
+
+	lowestHeights := make([]types.Height, len(toRefresh))
+	currentHeights := make([]types.Height, len(toRefresh))
+	errs := make([]error, len(toRefresh))
+	wg := sync.WaitGroup{}
+	for i, w := range toRefresh {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lowestHeight, err := w.node.GetLowestHeight(ctx)
+			if err != nil {
+				errs[i] = fmt.Errorf("node %s: get lowest height: %w", w.id, err)
+				return
+			}
+			currentHeight, err := w.node.GetCurrentHeight(ctx)
+			if err != nil {
+				errs[i] = fmt.Errorf("node %s: get current height: %w", w.id, err)
+				return
+			}
+			lowestHeights[i] = lowestHeight
+			currentHeights[i] = currentHeight
+		}()
+	}
+	wg.Wait()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	refreshed := 0
+	for i, w := range toRefresh {
+		if errs[i] != nil {
+			continue
+		}
+		w.hasRange = true
+		w.lowestHeight = lowestHeights[i]
+		w.currentHeight = max(w.currentHeight, currentHeights[i])
+		refreshed++
+	}
+
+	if refreshed == 0 {
+		return fmt.Errorf("refresh nodes ranges: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// queryNodes concurrently calls the query function on the provided nodes and returns
+// the heights reported by the nodes that didn't fail, indexed by their position
+// inside the provided slice. Returns an error only if all the queries failed.
+func (n *Node) queryNodes(
+	ctx context.Context,
+	nodes []*wrappedNode,
+	query func(ctx context.Context, w *wrappedNode) (types.Height, error),
+) (map[int]types.Height, error) {
+	heights := make([]types.Height, len(nodes))
+	errs := make([]error, len(nodes))
+	wg := sync.WaitGroup{}
+	for i, w := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			heights[i], errs[i] = query(ctx, w)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("node %s: %w", w.id, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := make(map[int]types.Height, len(nodes))
+	for i, err := range errs {
+		if err != nil {
+			n.logger.Warn().Err(err).Msg("query node")
+			continue
+		}
+		result[i] = heights[i]
+	}
+
+	if len(result) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
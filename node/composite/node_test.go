@@ -0,0 +1,179 @@
+package composite_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/node/composite"
+	"github.com/milkyway-labs/flux/types"
+)
+
+var _ node.Node = &testNode{}
+
+// testNode is a node that provides the blocks in the [lowest, current] range.
+type testNode struct {
+	id      string
+	chainID string
+	lowest  types.Height
+	current types.Height
+	failing bool
+}
+
+type testBlock struct {
+	types.Block
+	nodeID string
+}
+
+func (n *testNode) GetChainID() string {
+	return n.chainID
+}
+
+func (n *testNode) GetBlock(_ context.Context, height types.Height) (types.Block, error) {
+	if n.failing || height < n.lowest || height > n.current {
+		return nil, fmt.Errorf("block %d not available on node %s", height, n.id)
+	}
+	return &testBlock{nodeID: n.id}, nil
+}
+
+func (n *testNode) GetLowestHeight(context.Context) (types.Height, error) {
+	return n.lowest, nil
+}
+
+func (n *testNode) GetCurrentHeight(context.Context) (types.Height, error) {
+	return n.current, nil
+}
+
+// finalizedTestNode is a testNode that reports the finalized height.
+type finalizedTestNode struct {
+	*testNode
+	finalized types.Height
+}
+
+func (n *finalizedTestNode) GetFinalizedHeight(context.Context) (types.Height, error) {
+	return n.finalized, nil
+}
+
+func newCompositeNode(t *testing.T, nodes ...*testNode) *composite.Node {
+	ids := make([]string, len(nodes))
+	wrapped := make([]node.Node, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.id
+		wrapped[i] = n
+	}
+
+	compositeNode, err := composite.NewNode(
+		context.Background(),
+		zerolog.Nop(),
+		composite.NewConfig(ids, time.Minute),
+		wrapped,
+	)
+	require.NoError(t, err)
+	return compositeNode
+}
+
+func TestNode_GetBlock(t *testing.T) {
+	pruned := &testNode{id: "pruned", chainID: "test-1", lowest: 900, current: 1000}
+	archive := &testNode{id: "archive", chainID: "test-1", lowest: 1, current: 995}
+	compositeNode := newCompositeNode(t, pruned, archive)
+
+	testCases := []struct {
+		name       string
+		height     types.Height
+		shouldErr  bool
+		expectedID string
+	}{
+		{name: "tip is provided by the pruned node", height: 1000, expectedID: "pruned"},
+		{name: "common range is provided by the first node", height: 950, expectedID: "pruned"},
+		{name: "old block is provided by the archive node", height: 10, expectedID: "archive"},
+		{name: "future block returns error", height: 1001, shouldErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			block, err := compositeNode.GetBlock(context.Background(), tc.height)
+			if tc.shouldErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedID, block.(*testBlock).nodeID)
+			}
+		})
+	}
+}
+
+func TestNode_GetBlockFailover(t *testing.T) {
+	pruned := &testNode{id: "pruned", chainID: "test-1", lowest: 900, current: 1000, failing: true}
+	archive := &testNode{id: "archive", chainID: "test-1", lowest: 1, current: 1000}
+	compositeNode := newCompositeNode(t, pruned, archive)
+
+	block, err := compositeNode.GetBlock(context.Background(), 950)
+	require.NoError(t, err)
+	require.Equal(t, "archive", block.(*testBlock).nodeID)
+}
+
+func TestNode_Heights(t *testing.T) {
+	pruned := &testNode{id: "pruned", chainID: "test-1", lowest: 900, current: 1000}
+	archive := &testNode{id: "archive", chainID: "test-1", lowest: 1, current: 995}
+	compositeNode := newCompositeNode(t, pruned, archive)
+
+	lowest, err := compositeNode.GetLowestHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(1), lowest)
+
+	current, err := compositeNode.GetCurrentHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(1000), current)
+
+	// The tip moved, the new block should be provided by the pruned node
+	pruned.current = 1001
+	current, err = compositeNode.GetCurrentHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(1001), current)
+
+	block, err := compositeNode.GetBlock(context.Background(), 1001)
+	require.NoError(t, err)
+	require.Equal(t, "pruned", block.(*testBlock).nodeID)
+}
+
+func TestNewNode_DifferentChains(t *testing.T) {
+	_, err := composite.NewNode(
+		context.Background(),
+		zerolog.Nop(),
+		composite.NewConfig([]string{"a", "b"}, time.Minute),
+		[]node.Node{
+			&testNode{id: "a", chainID: "test-1"},
+			&testNode{id: "b", chainID: "test-2"},
+		},
+	)
+	require.Error(t, err)
+}
+
+func TestNode_SupportsFinalizedHeight(t *testing.T) {
+	pruned := &testNode{id: "pruned", chainID: "test-1", lowest: 900, current: 1000}
+	archive := &testNode{id: "archive", chainID: "test-1", lowest: 1, current: 995}
+
+	// None of the wrapped nodes reports the finalized height
+	compositeNode := newCompositeNode(t, pruned, archive)
+	require.False(t, node.SupportsFinalizedHeight(compositeNode))
+	_, err := compositeNode.GetFinalizedHeight(context.Background())
+	require.Error(t, err)
+
+	// One of the wrapped nodes reports the finalized height
+	compositeNode, err = composite.NewNode(
+		context.Background(),
+		zerolog.Nop(),
+		composite.NewConfig([]string{"pruned", "archive"}, time.Minute),
+		[]node.Node{&finalizedTestNode{testNode: pruned, finalized: 998}, archive},
+	)
+	require.NoError(t, err)
+	require.True(t, node.SupportsFinalizedHeight(compositeNode))
+	height, err := compositeNode.GetFinalizedHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.Height(998), height)
+}
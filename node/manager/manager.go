@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/milkyway-labs/flux/node"
+	"github.com/milkyway-labs/flux/types"
 )
 
 // NodesManager handle the construction of the Node instances that can
@@ -41,3 +44,23 @@ func (mm *NodesManager) GetNode(
 
 	return nodeBuilder(ctx, nodeID, cfg)
 }
+
+// BuildNode builds the node having the provided ID using its configuration.
+func (mm *NodesManager) BuildNode(ctx context.Context, cfg *types.Config, nodeID string) (node.Node, error) {
+	nodeCfg, nodeCfgFound := cfg.Nodes[nodeID]
+	if !nodeCfgFound {
+		return nil, fmt.Errorf("node %s not found", nodeID)
+	}
+
+	nodeType, foundNodeType := nodeCfg["type"].(string)
+	if !foundNodeType {
+		return nil, fmt.Errorf("can't find 'type' field in node %s", nodeID)
+	}
+
+	rawConfig, err := yaml.Marshal(nodeCfg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal node %s config", nodeID)
+	}
+
+	return mm.GetNode(ctx, nodeType, nodeID, rawConfig)
+}
@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"errors"
 
 	"github.com/milkyway-labs/flux/types"
 )
@@ -19,3 +20,44 @@ type Node interface {
 	// GetCurrentHeight gets the current node height.
 	GetCurrentHeight(context context.Context) (types.Height, error)
 }
+
+// FinalizedHeightProvider represents a Node that can report the highest
+// block height that is final and can't be replaced by the chain.
+type FinalizedHeightProvider interface {
+	// GetFinalizedHeight gets the height of the latest finalized block.
+	GetFinalizedHeight(context context.Context) (types.Height, error)
+}
+
+// FinalizedHeightSupportChecker represents a FinalizedHeightProvider that knows
+// only at runtime if it can report the finalized height, for example because
+// it relies on other nodes to do so.
+type FinalizedHeightSupportChecker interface {
+	// SupportsFinalizedHeight tells if the node can report the finalized height.
+	SupportsFinalizedHeight() bool
+}
+
+// SupportsFinalizedHeight tells if the provided node can report the
+// height of the latest finalized block.
+func SupportsFinalizedHeight(n Node) bool {
+	if _, ok := n.(FinalizedHeightProvider); !ok {
+		return false
+	}
+	if checker, ok := n.(FinalizedHeightSupportChecker); ok {
+		return checker.SupportsFinalizedHeight()
+	}
+	return true
+}
+
+// ErrSubscriptionNotSupported is returned by a HeightsSubscriber that
+// is not able to notify the new heights, for example because it is not
+// configured to do so.
+var ErrSubscriptionNotSupported = errors.New("subscription not supported")
+
+// HeightsSubscriber represents a Node that can notify the heights of the new
+// blocks as soon as they are produced, without being polled.
+type HeightsSubscriber interface {
+	// SubscribeHeights subscribes to the heights of the new blocks produced by the chain.
+	// The returned channel is closed once the subscription terminates, for example
+	// because the connection with the node dropped, or the context is canceled.
+	SubscribeHeights(context context.Context) (<-chan types.Height, error)
+}
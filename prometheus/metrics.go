@@ -32,8 +32,19 @@ var IndexerFailedBlocks = prometheus.NewCounterVec(
 	[]string{"indexer_name"},
 )
 
+// IndexerReorgs represents the Telemetry counter used to track the chain
+// reorganizations detected by each indexer.
+var IndexerReorgs = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "indexer_chain_reorgs",
+		Help: "Number of chain reorganizations detected by the indexer.",
+	},
+	[]string{"indexer_name"},
+)
+
 func init() {
 	prometheus.MustRegister(WorkersCount)
 	prometheus.MustRegister(LatestIndexedHeightByIndexer)
 	prometheus.MustRegister(IndexerFailedBlocks)
+	prometheus.MustRegister(IndexerReorgs)
 }
@@ -7,13 +7,60 @@ import (
 	"io"
 	"net/http"
 	urlpkg "net/url"
+	"strconv"
+	"time"
 
 	"github.com/goccy/go-json"
+
+	"github.com/milkyway-labs/flux/types"
 )
 
+// Caller represents a component that can perform JSON-RPC calls.
+type Caller interface {
+	// Call calls the provided method and unmarshals its result into result.
+	Call(ctx context.Context, method string, params any, result any) error
+}
+
+// BatchElem represents a single call of a batch request.
+type BatchElem struct {
+	Method string
+	Params any
+	// Result is the value into which the call result is unmarshalled.
+	Result any
+	// Error is set if the node returned an error for this call
+	// or the result couldn't be unmarshalled.
+	Error error
+}
+
+// NewBatchElem creates a new BatchElem.
+func NewBatchElem(method string, params any, result any) BatchElem {
+	return BatchElem{
+		Method: method,
+		Params: params,
+		Result: result,
+	}
+}
+
+// BatchCaller represents a component that can perform JSON-RPC batch calls.
+type BatchCaller interface {
+	Caller
+	// BatchCall performs all the provided calls with a single request. The returned error
+	// reports the failure of the whole request, while the error of each call is set
+	// into its BatchElem.
+	BatchCall(ctx context.Context, batch []BatchElem) error
+}
+
+var _ BatchCaller = &Client{}
+
 type Client struct {
 	url        string
 	httpClient *http.Client
+	// Limiters used to avoid being throttled by the node.
+	rateLimiter        *rateLimiter
+	concurrencyLimiter *concurrencyLimiter
+	// Credentials sent with the HTTP basic authentication, nil if
+	// the node doesn't require authentication.
+	basicAuth *urlpkg.Userinfo
 }
 
 func NewClient(url string, httpClient *http.Client) (*Client, error) {
@@ -21,40 +68,191 @@ func NewClient(url string, httpClient *http.Client) (*Client, error) {
 		return nil, fmt.Errorf("invalid url: %w", err)
 	}
 	return &Client{
-		url:        url,
-		httpClient: httpClient,
+		url:                url,
+		httpClient:         httpClient,
+		rateLimiter:        &rateLimiter{},
+		concurrencyLimiter: newConcurrencyLimiter(0),
 	}, nil
 }
 
+// WithMaxRequestsPerSecond allows to limit the number of requests sent
+// each second to the node, if zero the rate is not limited.
+func (c *Client) WithMaxRequestsPerSecond(requestsPerSecond float64) *Client {
+	c.rateLimiter.setRate(requestsPerSecond)
+	return c
+}
+
+// WithMaxConcurrentRequests allows to limit the number of requests sent
+// concurrently to the node, if zero the concurrency is not limited.
+func (c *Client) WithMaxConcurrentRequests(maxConcurrentRequests uint32) *Client {
+	c.concurrencyLimiter = newConcurrencyLimiter(maxConcurrentRequests)
+	return c
+}
+
+// WithBasicAuth allows to authenticate the requests sent to the node
+// using the HTTP basic authentication.
+func (c *Client) WithBasicAuth(username string, password string) *Client {
+	c.basicAuth = urlpkg.UserPassword(username, password)
+	return c
+}
+
 func (c *Client) Call(ctx context.Context, method string, params any, result any) error {
 	paramsJSON, err := json.Marshal(params)
 	if err != nil {
 		return fmt.Errorf("marshal params: %w", err)
 	}
 	req := NewRequest(-1, method, paramsJSON)
-	reqJSON, _ := json.Marshal(req)
+	statusCode, body, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("unmarshal response: status code %d: %w", statusCode, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("rpc error: status code %d: %w", statusCode, resp.Error)
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
+	}
+	return nil
+}
+
+// BatchCall implements BatchCaller.
+func (c *Client) BatchCall(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	reqs := make([]Request, len(batch))
+	for i, elem := range batch {
+		paramsJSON, err := json.Marshal(elem.Params)
+		if err != nil {
+			return fmt.Errorf("marshal params of call %d: %w", i, err)
+		}
+		reqs[i] = NewRequest(i, elem.Method, paramsJSON)
+	}
+	statusCode, body, err := c.send(ctx, reqs)
+	if err != nil {
+		return err
+	}
+
+	var resps []Response
+	if err := json.Unmarshal(body, &resps); err != nil {
+		// The node might reply with a single error if it can't process the batch
+		var resp Response
+		if json.Unmarshal(body, &resp) == nil && resp.Error != nil {
+			return fmt.Errorf("rpc error: status code %d: %w", statusCode, resp.Error)
+		}
+		return fmt.Errorf("unmarshal batch response: status code %d: %w", statusCode, err)
+	}
+
+	// The responses can be returned in any order, match them using their ID
+	received := make([]bool, len(batch))
+	for _, resp := range resps {
+		index, ok := parseBatchID(resp.ID)
+		if !ok || index >= len(batch) || received[index] {
+			return fmt.Errorf("invalid batch response id: %v", resp.ID)
+		}
+		received[index] = true
+
+		elem := &batch[index]
+		switch {
+		case resp.Error != nil:
+			elem.Error = fmt.Errorf("rpc error: %w", resp.Error)
+		default:
+			elem.Error = nil
+			if err := json.Unmarshal(resp.Result, elem.Result); err != nil {
+				elem.Error = fmt.Errorf("unmarshal result: %w", err)
+			}
+		}
+	}
+	for i, ok := range received {
+		if !ok {
+			batch[i].Error = fmt.Errorf("missing response")
+		}
+	}
+
+	return nil
+}
+
+// send sends the provided request to the node, respecting the configured
+// limits, and returns the response status code and body.
+func (c *Client) send(ctx context.Context, req any) (int, []byte, error) {
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("marshal request: %w", err)
+	}
+	if err := c.rateLimiter.wait(ctx); err != nil {
+		return 0, nil, err
+	}
+	release, err := c.concurrencyLimiter.acquire(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer release()
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(reqJSON))
 	if err != nil {
-		return fmt.Errorf("new http request: %w", err)
+		return 0, nil, fmt.Errorf("new http request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	if c.basicAuth != nil {
+		password, _ := c.basicAuth.Password()
+		httpReq.SetBasicAuth(c.basicAuth.Username(), password)
+	}
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 	defer func() {
 		_, _ = io.Copy(io.Discard, httpResp.Body)
 		_ = httpResp.Body.Close()
 	}()
-	var resp Response
-	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
-		return fmt.Errorf("unmarshal response: status code %d: %w", httpResp.StatusCode, err)
+	// The node is rate limiting us or temporarily unavailable, stop sending
+	// requests for the time requested by the node and let the caller retry later
+	if httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter := parseRetryAfter(httpResp.Header.Get("Retry-After"))
+		c.rateLimiter.pause(retryAfter)
+
+		err := fmt.Errorf("http error: status code %d", httpResp.StatusCode)
+		if httpResp.StatusCode == http.StatusTooManyRequests {
+			return 0, nil, types.NewRateLimitedError(err, retryAfter)
+		}
+		return 0, nil, types.NewRetryableError(err, retryAfter)
 	}
-	if resp.Error != nil {
-		return fmt.Errorf("rpc error: status code %d: %w", httpResp.StatusCode, resp.Error)
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read response: status code %d: %w", httpResp.StatusCode, err)
 	}
-	if err := json.Unmarshal(resp.Result, result); err != nil {
-		return fmt.Errorf("unmarshal result: %w", err)
+	return httpResp.StatusCode, body, nil
+}
+
+// parseBatchID parses the ID of a response to a batch request,
+// which is the index of the call inside the batch.
+func parseBatchID(id any) (int, bool) {
+	switch id := id.(type) {
+	case float64:
+		if id < 0 || id != float64(int(id)) {
+			return 0, false
+		}
+		return int(id), true
+	case json.Number:
+		value, err := strconv.Atoi(string(id))
+		return value, err == nil && value >= 0
+	default:
+		return 0, false
 	}
-	return nil
+}
+
+// parseRetryAfter parses the value of the Retry-After header, which can be
+// either a number of seconds or an HTTP date. Returns 0 if the value is invalid.
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
 }
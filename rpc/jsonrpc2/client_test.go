@@ -0,0 +1,189 @@
+package jsonrpc2_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+	"github.com/milkyway-labs/flux/types"
+)
+
+// newEchoServer creates a server that replies to each request with its method name
+// in reverse order, or with an error if the method is "fail".
+func newEchoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []jsonrpc2.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+
+		resps := make([]jsonrpc2.Response, 0, len(reqs))
+		for i := len(reqs) - 1; i >= 0; i-- {
+			resp := jsonrpc2.Response{JSONRPC: jsonrpc2.ProtocolVersion, ID: reqs[i].ID}
+			if reqs[i].Method == "fail" {
+				resp.Error = &jsonrpc2.Error{Code: -32603, Message: "internal error"}
+			} else {
+				resp.Result, _ = json.Marshal(reqs[i].Method)
+			}
+			resps = append(resps, resp)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resps))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_BatchCall(t *testing.T) {
+	client, err := jsonrpc2.NewClient(newEchoServer(t).URL, http.DefaultClient)
+	require.NoError(t, err)
+
+	var first, second, third string
+	batch := []jsonrpc2.BatchElem{
+		jsonrpc2.NewBatchElem("first", nil, &first),
+		jsonrpc2.NewBatchElem("fail", nil, &second),
+		jsonrpc2.NewBatchElem("third", nil, &third),
+	}
+	require.NoError(t, client.BatchCall(context.Background(), batch))
+
+	require.NoError(t, batch[0].Error)
+	require.Equal(t, "first", first)
+
+	var rpcErr *jsonrpc2.Error
+	require.ErrorAs(t, batch[1].Error, &rpcErr)
+	require.Equal(t, -32603, rpcErr.Code)
+
+	require.NoError(t, batch[2].Error)
+	require.Equal(t, "third", third)
+}
+
+func TestClient_BatchCallRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, err := jsonrpc2.NewClient(server.URL, http.DefaultClient)
+	require.NoError(t, err)
+
+	var result string
+	err = client.BatchCall(context.Background(), []jsonrpc2.BatchElem{
+		jsonrpc2.NewBatchElem("first", nil, &result),
+	})
+	require.Error(t, err)
+}
+
+// newSlowServer creates a server that replies to each call after the provided delay,
+// tracking the maximum number of requests handled concurrently.
+func newSlowServer(t *testing.T, delay time.Duration, maxConcurrent *atomic.Int32) *httptest.Server {
+	var concurrent atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		current := concurrent.Add(1)
+		defer concurrent.Add(-1)
+		for {
+			maxValue := maxConcurrent.Load()
+			if current <= maxValue || maxConcurrent.CompareAndSwap(maxValue, current) {
+				break
+			}
+		}
+
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":-1,"result":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_MaxConcurrentRequests(t *testing.T) {
+	var maxConcurrent atomic.Int32
+	client, err := jsonrpc2.NewClient(newSlowServer(t, 20*time.Millisecond, &maxConcurrent).URL, http.DefaultClient)
+	require.NoError(t, err)
+	client.WithMaxConcurrentRequests(2)
+
+	wg := sync.WaitGroup{}
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result string
+			require.NoError(t, client.Call(context.Background(), "test", nil, &result))
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(2), maxConcurrent.Load())
+}
+
+func TestClient_MaxRequestsPerSecond(t *testing.T) {
+	var maxConcurrent atomic.Int32
+	client, err := jsonrpc2.NewClient(newSlowServer(t, 0, &maxConcurrent).URL, http.DefaultClient)
+	require.NoError(t, err)
+	client.WithMaxRequestsPerSecond(50)
+
+	start := time.Now()
+	for range 6 {
+		var result string
+		require.NoError(t, client.Call(context.Background(), "test", nil, &result))
+	}
+
+	// The first request is sent immediately, the other ones every 20ms
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":-1,"result":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := jsonrpc2.NewClient(server.URL, http.DefaultClient)
+	require.NoError(t, err)
+
+	var result string
+	err = client.Call(context.Background(), "test", nil, &result)
+	require.ErrorIs(t, err, types.ErrRateLimited)
+	var retryableErr *types.RetryableError
+	require.ErrorAs(t, err, &retryableErr)
+	require.Equal(t, time.Second, retryableErr.RetryAfter)
+
+	// The next request is sent only after the time requested by the node
+	start := time.Now()
+	require.NoError(t, client.Call(context.Background(), "test", nil, &result))
+	require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestClient_BasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":-1,"result":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := jsonrpc2.NewClient(server.URL, http.DefaultClient)
+	require.NoError(t, err)
+
+	var result string
+	require.Error(t, client.Call(context.Background(), "test", nil, &result))
+
+	client.WithBasicAuth("user", "pass")
+	require.NoError(t, client.Call(context.Background(), "test", nil, &result))
+	require.Equal(t, "ok", result)
+}
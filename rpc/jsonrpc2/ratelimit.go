@@ -0,0 +1,95 @@
+package jsonrpc2
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter limits the rate at which the requests are sent to the node.
+type rateLimiter struct {
+	mu sync.Mutex
+	// Minimum interval between two requests, zero if the rate is not limited.
+	interval time.Duration
+	// Time at which the next request can be sent.
+	next time.Time
+}
+
+// setRate sets the maximum number of requests that can be sent each second,
+// if zero the rate is not limited.
+func (l *rateLimiter) setRate(requestsPerSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval = 0
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+}
+
+// wait waits until a new request can be sent.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	sendAt := now
+	if l.next.After(now) {
+		sendAt = l.next
+	}
+	l.next = sendAt.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := sendAt.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause prevents the requests from being sent for the provided amount of time.
+func (l *rateLimiter) pause(duration time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(duration)
+	if until.After(l.next) {
+		l.next = until
+	}
+}
+
+// concurrencyLimiter limits the number of requests that can be sent concurrently.
+type concurrencyLimiter struct {
+	// Semaphore containing a slot for each request being sent, nil if
+	// the concurrency is not limited.
+	slots chan struct{}
+}
+
+func newConcurrencyLimiter(maxConcurrentRequests uint32) *concurrencyLimiter {
+	var slots chan struct{}
+	if maxConcurrentRequests > 0 {
+		slots = make(chan struct{}, maxConcurrentRequests)
+	}
+	return &concurrencyLimiter{slots: slots}
+}
+
+// acquire waits until a new request can be sent, the returned function
+// must be called once the request has been completed.
+func (l *concurrencyLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
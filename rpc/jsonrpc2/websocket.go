@@ -0,0 +1,219 @@
+package jsonrpc2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time allowed to write a message to the peer.
+	wsWriteTimeout = 10 * time.Second
+	// Interval at which the ping messages are sent to the peer.
+	wsPingInterval = 30 * time.Second
+	// Time allowed to read the next message from the peer, the peer
+	// must reply to our pings within this time.
+	wsReadTimeout = wsPingInterval * 2
+)
+
+var _ Caller = &WebSocketClient{}
+
+// WebSocketClient is a JSON-RPC client that communicates with the node through
+// a WebSocket connection. Besides the calls responses, the node can push
+// notifications, such as the events of a subscription, that are delivered
+// through the Notifications channel.
+type WebSocketClient struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[string]chan Response
+
+	notifications chan Response
+	done          chan struct{}
+	closeOnce     sync.Once
+	err           error
+}
+
+// DialWebSocket connects to the node WebSocket endpoint at the provided url.
+func DialWebSocket(ctx context.Context, url string) (*WebSocketClient, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial websocket: %w", err)
+	}
+
+	c := &WebSocketClient{
+		conn:          conn,
+		pending:       make(map[string]chan Response),
+		notifications: make(chan Response, 100),
+		done:          make(chan struct{}),
+	}
+	go c.readLoop()
+	go c.pingLoop()
+
+	return c, nil
+}
+
+// Call implements Caller.
+func (c *WebSocketClient) Call(ctx context.Context, method string, params any, result any) error {
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("marshal params: %w", err)
+	}
+
+	// Register the call to receive its response
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	respCh := make(chan Response, 1)
+	c.pending[strconv.FormatUint(id, 10)] = respCh
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, strconv.FormatUint(id, 10))
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(NewRequest(id, method, paramsJSON)); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-respCh:
+		if resp.Error != nil {
+			return fmt.Errorf("rpc error: %w", resp.Error)
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("unmarshal result: %w", err)
+		}
+		return nil
+	case <-c.done:
+		return fmt.Errorf("connection closed: %w", c.Err())
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Notifications returns the channel from which are received the messages
+// pushed by the node that are not responses to a call.
+// The channel is closed once the connection is closed.
+func (c *WebSocketClient) Notifications() <-chan Response {
+	return c.notifications
+}
+
+// Done returns a channel that is closed once the connection is closed.
+func (c *WebSocketClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that caused the connection to be closed.
+func (c *WebSocketClient) Err() error {
+	<-c.done
+	return c.err
+}
+
+// Close closes the connection.
+func (c *WebSocketClient) Close() error {
+	c.closeWithError(fmt.Errorf("connection closed by the client"))
+	return nil
+}
+
+// closeWithError closes the connection storing the reason for which it was closed.
+func (c *WebSocketClient) closeWithError(err error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		c.writeMu.Lock()
+		_ = c.conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(wsWriteTimeout),
+		)
+		c.writeMu.Unlock()
+		_ = c.conn.Close()
+		close(c.done)
+	})
+}
+
+// write sends the provided message to the node.
+func (c *WebSocketClient) write(message any) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := c.conn.WriteJSON(message); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	return nil
+}
+
+// readLoop reads the messages sent by the node, delivering the responses
+// to the pending calls and the other messages to the notifications channel.
+func (c *WebSocketClient) readLoop() {
+	defer close(c.notifications)
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.closeWithError(fmt.Errorf("read message: %w", err))
+			return
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+
+		var resp Response
+		if err := json.Unmarshal(data, &resp); err != nil {
+			c.closeWithError(fmt.Errorf("unmarshal message: %w", err))
+			return
+		}
+
+		c.mu.Lock()
+		respCh, found := c.pending[fmt.Sprint(resp.ID)]
+		if found {
+			delete(c.pending, fmt.Sprint(resp.ID))
+		}
+		c.mu.Unlock()
+
+		if found {
+			respCh <- resp
+			continue
+		}
+
+		select {
+		case c.notifications <- resp:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// pingLoop periodically sends ping messages to the node to
+// detect when the connection drops.
+func (c *WebSocketClient) pingLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.writeMu.Lock()
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+			c.writeMu.Unlock()
+			if err != nil {
+				c.closeWithError(fmt.Errorf("send ping: %w", err))
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
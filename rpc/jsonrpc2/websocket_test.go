@@ -0,0 +1,75 @@
+package jsonrpc2_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milkyway-labs/flux/rpc/jsonrpc2"
+)
+
+// newSubscriptionServer creates a WebSocket server that replies to the subscribe
+// request and then pushes the provided events.
+func newSubscriptionServer(t *testing.T, events []string) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var req jsonrpc2.Request
+		require.NoError(t, conn.ReadJSON(&req))
+		require.Equal(t, "subscribe", req.Method)
+		require.NoError(t, conn.WriteJSON(jsonrpc2.Response{
+			JSONRPC: jsonrpc2.ProtocolVersion,
+			ID:      req.ID,
+			Result:  json.RawMessage(`{}`),
+		}))
+
+		for _, event := range events {
+			require.NoError(t, conn.WriteJSON(jsonrpc2.Response{
+				JSONRPC: jsonrpc2.ProtocolVersion,
+				ID:      "1#event",
+				Result:  json.RawMessage(event),
+			}))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebSocketClient(t *testing.T) {
+	server := newSubscriptionServer(t, []string{`"first"`, `"second"`})
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := jsonrpc2.DialWebSocket(ctx, url)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var res struct{}
+	require.NoError(t, client.Call(ctx, "subscribe", map[string]string{"query": "test"}, &res))
+
+	// The events are delivered as notifications
+	var received []string
+	for notification := range client.Notifications() {
+		var event string
+		require.NoError(t, json.Unmarshal(notification.Result, &event))
+		received = append(received, event)
+	}
+	require.Equal(t, []string{"first", "second"}, received)
+
+	// The server closed the connection after sending the events
+	<-client.Done()
+	require.Error(t, client.Err())
+	require.Error(t, client.Call(ctx, "subscribe", nil, &res))
+}
@@ -16,6 +16,12 @@ type Block interface {
 	GetChainID() string
 	// GetHeight provides the height at which this block has been produced.
 	GetHeight() Height
+	// GetHash provides the hash that identifies this Block.
+	GetHash() string
+	// GetParentHash provides the hash of the Block that precedes this one in the chain.
+	// This is used to detect chain reorganizations, if the chain doesn't
+	// support them an empty string can be returned.
+	GetParentHash() string
 	// GetTimeStamp provides the time at which this block has been produced.
 	GetTimeStamp() time.Time
 	// GetTxs get the transactions included in this Block.
@@ -29,3 +35,40 @@ type Tx interface {
 	// IsSuccessful returns true if the transaction has been executed without errors, false otherwise.
 	IsSuccessful() bool
 }
+
+// Event represents a generic event emitted by a blockchain while executing
+// a Block or one of its transactions.
+type Event interface {
+	// GetType gets the type of this event.
+	GetType() string
+	// GetAttributeValue gets the value of the first attribute of this event with the provided key.
+	GetAttributeValue(key string) (string, bool)
+}
+
+// Message represents a generic message included in a Tx.
+type Message interface {
+	// GetType gets the type of this message (e.g. the protobuf type URL).
+	GetType() string
+}
+
+// EventsBlock represents a Block that provides the events emitted outside
+// of its transactions.
+type EventsBlock interface {
+	Block
+	// GetEvents gets the events emitted outside of the block transactions.
+	GetEvents() []Event
+}
+
+// EventsTx represents a Tx that provides the events emitted during its execution.
+type EventsTx interface {
+	Tx
+	// GetEvents gets the events emitted during the execution of this transaction.
+	GetEvents() []Event
+}
+
+// MessagesTx represents a Tx that provides the messages that it contains.
+type MessagesTx interface {
+	Tx
+	// GetMessages gets the messages included in this transaction.
+	GetMessages() []Message
+}
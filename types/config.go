@@ -72,6 +72,12 @@ func (cfg *Config) Validate() error {
 			return fmt.Errorf("duplicated indexer with name: %s", indexerCfg.Name)
 		}
 		indexersName[indexerCfg.Name] = true
+
+		for _, module := range indexerCfg.Modules {
+			if _, err := cfg.GetModuleErrorPolicy(&indexerCfg, module); err != nil {
+				return fmt.Errorf("invalid module %s config for indexer %s: %w", module, indexerCfg.Name, err)
+			}
+		}
 	}
 
 	return nil
@@ -87,6 +93,26 @@ func (cfg *Config) GetIndexerConfig(name string) (*IndexerConfig, error) {
 	return nil, fmt.Errorf("config for indexer %s not found", name)
 }
 
+// GetModuleErrorPolicy gets the policy that the indexer uses to handle the errors returned
+// by the module with the provided name. The policy defined inside the indexer's
+// override_module_config takes precedence over the one defined in the module config.
+func (cfg *Config) GetModuleErrorPolicy(indexerCfg *IndexerConfig, module string) (ModuleErrorPolicy, error) {
+	value, found := indexerCfg.OverrideModuleConfig[module][ModuleErrorPolicyKey]
+	if !found {
+		value, found = cfg.Modules[module][ModuleErrorPolicyKey]
+	}
+	if !found {
+		return ModuleErrorPolicyFail, nil
+	}
+
+	policy, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", ModuleErrorPolicyKey)
+	}
+
+	return ModuleErrorPolicy(policy), ModuleErrorPolicy(policy).Validate()
+}
+
 func (cfg *Config) UnmarshalYAML(unmarshal func(any) error) error {
 	// Local type to avoid recursion during the unmarshal
 	type privateCfg Config
@@ -189,10 +215,22 @@ type IndexerConfig struct {
 	// a block in case of failure.
 	MaxAttempts uint32 `yaml:"max_attempts"`
 	// Define the amount of time the indexer will wait before re-enqueuing a failed
-	// block for parsing.
+	// block for parsing. This is used as the initial delay of the RetryPolicy.
 	TimeBeforeRetry time.Duration `yaml:"time_before_retry"`
+	// RetryPolicy defines how the delay between the attempts to index a failed
+	// block grows.
+	RetryPolicy RetryPolicyConfig `yaml:"retry_policy"`
 	// Disabled if true, the indexer will not be started.
 	Disabled bool `yaml:"disabled"`
+	// FinalityMode defines how the indexer determines if a block is final
+	// and can be indexed.
+	FinalityMode FinalityMode `yaml:"finality_mode"`
+	// Confirmations represents the number of blocks that must be produced on top
+	// of a block before considering it final. Used only with the `latest` finality mode.
+	Confirmations uint32 `yaml:"confirmations"`
+	// Ordered if true, the blocks are still fetched in parallel by the workers
+	// but they are passed to the modules one at a time, strictly ordered by height.
+	Ordered bool `yaml:"ordered"`
 }
 
 var DefaultIndexerCfg = IndexerConfig{
@@ -201,6 +239,9 @@ var DefaultIndexerCfg = IndexerConfig{
 	NodePollingInterval: time.Second,
 	MaxAttempts:         5,
 	TimeBeforeRetry:     10 * time.Second,
+	RetryPolicy:         DefaultRetryPolicyCfg,
+	FinalityMode:        FinalityModeLatest,
+	Confirmations:       0,
 }
 
 // Implements the Unmarshaler interface of the yaml pkg.
@@ -250,9 +291,147 @@ func (cfg *IndexerConfig) Validate() error {
 		return fmt.Errorf("modules list can't be empty")
 	}
 
+	if err := cfg.RetryPolicy.Validate(); err != nil {
+		return fmt.Errorf("invalid retry_policy: %w", err)
+	}
+
+	if cfg.RetryPolicy.MaxDelay > 0 && cfg.RetryPolicy.MaxDelay < cfg.TimeBeforeRetry {
+		return fmt.Errorf("retry_policy.max_delay must be >= than time_before_retry")
+	}
+
+	if err := cfg.FinalityMode.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.FinalityMode == FinalityModeFinalized && cfg.Confirmations > 0 {
+		return fmt.Errorf("confirmations can't be used with the `%s` finality_mode", FinalityModeFinalized)
+	}
+
+	return nil
+}
+
+// FinalityMode represents the strategy used by the indexer to determine
+// if a block is final.
+type FinalityMode string
+
+const (
+	// FinalityModeLatest considers final the blocks that have at least
+	// `confirmations` blocks produced on top of them.
+	FinalityModeLatest FinalityMode = "latest"
+	// FinalityModeFinalized considers final the blocks up to the finalized
+	// height reported by the node.
+	FinalityModeFinalized FinalityMode = "finalized"
+)
+
+func (m FinalityMode) Validate() error {
+	if m != FinalityModeLatest && m != FinalityModeFinalized {
+		return fmt.Errorf("invalid finality_mode, we only support `%s` and `%s` current: `%s`",
+			FinalityModeLatest, FinalityModeFinalized, m)
+	}
+
 	return nil
 }
 
+// ----------------------------------------------------------------------------
+// ---- Module error policy
+// ----------------------------------------------------------------------------
+
+// ModuleErrorPolicyKey is the key of the module config used to define
+// the module's ModuleErrorPolicy.
+const ModuleErrorPolicyKey = "on_error"
+
+// ModuleErrorPolicy represents how the indexer handles the errors returned
+// by a module while processing a block.
+type ModuleErrorPolicy string
+
+const (
+	// ModuleErrorPolicyFail fails the whole block, that will be retried
+	// by all the modules.
+	ModuleErrorPolicyFail ModuleErrorPolicy = "fail"
+	// ModuleErrorPolicySkip stores the block as failed for the module without
+	// retrying it, the other modules process the block as usual.
+	ModuleErrorPolicySkip ModuleErrorPolicy = "skip"
+	// ModuleErrorPolicyRetryModuleOnly retries the block only for the module,
+	// the other modules process the block as usual.
+	ModuleErrorPolicyRetryModuleOnly ModuleErrorPolicy = "retry_module_only"
+)
+
+func (p ModuleErrorPolicy) Validate() error {
+	switch p {
+	case ModuleErrorPolicyFail, ModuleErrorPolicySkip, ModuleErrorPolicyRetryModuleOnly:
+		return nil
+	default:
+		return fmt.Errorf("invalid %s, we only support `%s`, `%s` and `%s` current: `%s`",
+			ModuleErrorPolicyKey, ModuleErrorPolicyFail, ModuleErrorPolicySkip, ModuleErrorPolicyRetryModuleOnly, p)
+	}
+}
+
+// ----------------------------------------------------------------------------
+// ---- Retry policy config
+// ----------------------------------------------------------------------------
+
+type RetryPolicyConfig struct {
+	// Multiplier represents the factor by which the delay grows after each attempt.
+	// A value of 1 means that the indexer waits the same amount of time
+	// before each attempt.
+	Multiplier float64 `yaml:"multiplier"`
+	// MaxDelay represents the maximum amount of time the indexer will wait
+	// before re-enqueuing a failed block. If not set, the greater between
+	// DefaultRetryMaxDelay and the indexer's time_before_retry is used.
+	MaxDelay time.Duration `yaml:"max_delay"`
+	// Jitter represents the fraction of the delay that is randomly added or
+	// removed to spread the retries over time, must be between 0 and 1.
+	Jitter float64 `yaml:"jitter"`
+}
+
+// DefaultRetryMaxDelay is the maximum delay between two attempts used when
+// max_delay is not set.
+const DefaultRetryMaxDelay = 5 * time.Minute
+
+var DefaultRetryPolicyCfg = RetryPolicyConfig{
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Implements the Unmarshaler interface of the yaml pkg.
+func (cfg *RetryPolicyConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	// Local type to avoid recursion during the unmarshal
+	type privateRetryPolicyCfg RetryPolicyConfig
+	config := privateRetryPolicyCfg(DefaultRetryPolicyCfg)
+	err := unmarshal(&config)
+	if err != nil {
+		return err
+	}
+
+	*cfg = RetryPolicyConfig(config)
+	return nil
+}
+
+func (cfg *RetryPolicyConfig) Validate() error {
+	if cfg.Multiplier < 1 {
+		return fmt.Errorf("multiplier must be >= 1")
+	}
+
+	if cfg.Jitter < 0 || cfg.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+
+	if cfg.MaxDelay < 0 {
+		return fmt.Errorf("max_delay can't be negative")
+	}
+
+	return nil
+}
+
+// GetMaxDelay gets the maximum delay between two attempts, given the
+// initial delay configured with time_before_retry.
+func (cfg RetryPolicyConfig) GetMaxDelay(timeBeforeRetry time.Duration) time.Duration {
+	if cfg.MaxDelay > 0 {
+		return cfg.MaxDelay
+	}
+	return max(DefaultRetryMaxDelay, timeBeforeRetry)
+}
+
 // ----------------------------------------------------------------------------
 // ---- Monitoring config
 // ----------------------------------------------------------------------------
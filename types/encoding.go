@@ -61,6 +61,11 @@ func (bz HexBytes) MarshalJSON() ([]byte, error) {
 
 // This is the point of Bytes.
 func (bz *HexBytes) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*bz = make(HexBytes, 0)
+		return nil
+	}
+
 	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
 		return fmt.Errorf("invalid hex string: %s", data)
 	}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSkipBlock can be returned by a module or a node to signal that a block
+// can't be indexed and retrying won't help (e.g. the block is malformed).
+// Blocks failing with this error are marked as failed without performing
+// further attempts.
+var ErrSkipBlock = errors.New("skip block")
+
+// ErrRetryable can be returned by a module or a node to signal that a block
+// failed due to a transient error (e.g. the node is rate limiting the requests).
+// Unlike the other errors, the blocks failing with this error are retried
+// also by the modules whose `on_error` policy is `skip`.
+// Use NewRetryableError to also provide the minimum amount of time to wait
+// before retrying.
+var ErrRetryable = errors.New("retryable error")
+
+// ErrRateLimited is returned when a block failed because the node is rate
+// limiting the requests. The attempts failed with this error are retried
+// without being counted toward the maximum number of attempts.
+var ErrRateLimited = errors.New("rate limited")
+
+// NewSkipBlockError wraps the provided error so that it's classified as ErrSkipBlock.
+func NewSkipBlockError(err error) error {
+	return fmt.Errorf("%w: %w", ErrSkipBlock, err)
+}
+
+// RetryableError represents a transient error that should be retried after
+// at least RetryAfter.
+type RetryableError struct {
+	Err error
+	// RetryAfter represents the minimum amount of time to wait before retrying,
+	// if zero the delay defined by the indexer's retry policy is used.
+	RetryAfter time.Duration
+	// RateLimited tells if the error has been caused by the node rate limiting
+	// the requests, in which case the error is also classified as ErrRateLimited.
+	RateLimited bool
+}
+
+// NewRetryableError wraps the provided error so that it's classified as ErrRetryable.
+func NewRetryableError(err error, retryAfter time.Duration) *RetryableError {
+	return &RetryableError{
+		Err:        err,
+		RetryAfter: retryAfter,
+	}
+}
+
+// NewRateLimitedError wraps the provided error so that it's classified as
+// both ErrRetryable and ErrRateLimited.
+func NewRateLimitedError(err error, retryAfter time.Duration) *RetryableError {
+	return &RetryableError{
+		Err:         err,
+		RetryAfter:  retryAfter,
+		RateLimited: true,
+	}
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// Is implements the interface used by errors.Is to check if
+// the error is ErrRetryable or ErrRateLimited.
+func (e *RetryableError) Is(target error) bool {
+	return target == ErrRetryable || (target == ErrRateLimited && e.RateLimited)
+}